@@ -3,22 +3,58 @@ package app
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
+	"news/app/handlers"
 	"news/app/routes"
+	"news/domain/audit"
+	"news/domain/comment"
+	"news/domain/media"
 	"news/domain/news"
 	"news/domain/tag"
+	"news/domain/webhook"
+	"news/shared/health"
 )
 
 const (
 	v1 = "/api/v1"
 )
 
-func CreateApp(newsService news.Service, tagService tag.Service) *fiber.App {
-	app := fiber.New()
+// Proxies are the reverse proxies in front of the app. The client IP is read from
+// Header, set by the proxy to the IP alone like X-Real-IP, on the requests coming
+// from a Trusted IP or CIDR range only, the others use their remote address.
+type Proxies struct {
+	Header  string
+	Trusted []string
+}
+
+func CreateApp(newsService news.Service, tagService tag.Service, commentService comment.Service,
+	mediaService media.Service, transfer news.Transfer, webhookService webhook.Service, auditService audit.Service,
+	rateLimit handlers.RateLimitConfig, checker *health.Checker, cache handlers.CachePolicies,
+	idempotency handlers.IdempotencyConfig, apiKeys handlers.APIKeys, proxies Proxies) *fiber.App {
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler, ProxyHeader: proxies.Header,
+		EnableTrustedProxyCheck: true, TrustedProxies: proxies.Trusted})
+	app.Use(handlers.RequestID())
+	app.Use(handlers.AccessLog())
+	app.Use(handlers.Metrics())
+	// probes are answered before the rate limit, an orchestrator must never be throttled
+	app.Get("/healthz", handlers.Healthz())
+	app.Get("/readyz", handlers.Readyz(checker))
 	app.Use(cors.New())
+	app.Use(handlers.RateLimit(rateLimit))
+	app.Use(handlers.Authenticate(apiKeys))
+	app.Use(handlers.AuditMetadata())
 	app.Get("/", func(ctx *fiber.Ctx) error {
 		return ctx.Send([]byte("Welcome to app!"))
 	})
-	routes.NewsRouter(app.Group(v1+"/news"), newsService)
-	routes.TagRouter(app.Group(v1+"/tag"), tagService)
+	app.Get("/metrics", handlers.GetMetrics())
+	routes.NewsRouter(app.Group(v1+"/news"), newsService, cache, idempotency)
+	routes.NewsCommentRouter(app.Group(v1+"/news/:slug/comments"), commentService)
+	routes.TagRouter(app.Group(v1+"/tag"), tagService, cache, idempotency)
+	routes.CommentRouter(app.Group(v1+"/comments", handlers.RequireAuth()), commentService)
+	routes.MediaRouter(app.Group(v1+"/media"), mediaService)
+	admin := app.Group(v1+"/admin", handlers.RequireAuth())
+	routes.AdminNewsRouter(admin.Group("/news"), transfer)
+	routes.AdminWebhookRouter(admin.Group("/webhooks"), webhookService)
+	routes.AuditRouter(app.Group(v1+"/audit", handlers.RequireAuth()), auditService)
+	app.Use(handlers.Unmatched())
 	return app
 }
@@ -0,0 +1,1065 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/magiconair/properties/assert"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"news/app"
+	"news/app/handlers"
+	"news/domain/audit"
+	"news/domain/comment"
+	"news/domain/entities"
+	"news/domain/media"
+	"news/domain/news"
+	"news/domain/outbox"
+	"news/domain/tag"
+	"news/domain/webhook"
+	"news/shared/failure"
+	"news/shared/health"
+	"news/shared/idempotency"
+	"news/shared/ratelimit"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// adminAPIKey authenticates editor@example.com and reporterAPIKey reporter@example.com
+// in the apps the tests build.
+const (
+	adminAPIKey    = "admin-key"
+	reporterAPIKey = "reporter-key"
+)
+
+// workers are the background jobs main.go starts next to the server.
+type workers struct {
+	relay      *outbox.Relay
+	dispatcher *webhook.Dispatcher
+	published  *outbox.MemoryPublisher
+}
+
+// flush publishes the pending events and makes one attempt at the due webhook deliveries.
+func (w *workers) flush(t *testing.T) {
+	_, err := w.relay.Flush(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = w.dispatcher.Flush(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newApp builds the whole application on the memory repositories, the way DB.DRIVER=memory does,
+// without rate limiting.
+func newApp(t *testing.T) (*fiber.App, *workers) {
+	return newRateLimitedApp(t, handlers.RateLimitConfig{})
+}
+
+func newRateLimitedApp(t *testing.T, rateLimit handlers.RateLimitConfig) (*fiber.App, *workers) {
+	return newProxiedApp(t, rateLimit, app.Proxies{})
+}
+
+func newProxiedApp(t *testing.T, rateLimit handlers.RateLimitConfig, proxies app.Proxies) (*fiber.App, *workers) {
+	return buildApp(t, rateLimit, health.NewChecker(nil, time.Second, health.Build()), proxies)
+}
+
+// newCheckedApp builds the application with the dependency checks of its readiness probe.
+func newCheckedApp(t *testing.T, checks ...health.Check) *fiber.App {
+	fiberApp, _ := buildApp(t, handlers.RateLimitConfig{}, health.NewChecker(checks, 100*time.Millisecond, health.Build()),
+		app.Proxies{})
+	return fiberApp
+}
+
+func buildApp(t *testing.T, rateLimit handlers.RateLimitConfig, checker *health.Checker,
+	proxies app.Proxies) (*fiber.App, *workers) {
+	newsRepo, tagRepo := news.NewMemoryRepository(), tag.NewMemoryRepository()
+	commentRepo, mediaRepo := comment.NewMemoryRepository(), media.NewMemoryRepository()
+	newsRepo.CountComments = func(newsID string) int {
+		return commentRepo.CountComments(newsID, entities.CommentApproved)
+	}
+	box := outbox.NewMemoryRepository()
+	newsRepo.Outbox, tagRepo.Outbox = box, box
+	auditRepo := audit.NewMemoryRepository()
+	newsRepo.Audit, tagRepo.Audit = auditRepo, auditRepo
+	webhookRepo := webhook.NewMemoryRepository()
+	background := &workers{
+		dispatcher: webhook.NewDispatcher(webhookRepo, http.DefaultClient, 3, time.Second, time.Second),
+		published:  outbox.NewMemoryPublisher(),
+	}
+	background.relay = outbox.NewRelay(box, outbox.NewMultiPublisher(background.published,
+		webhook.NewPublisher(webhookRepo)), time.Second, 10, 3)
+	store, err := media.NewLocalStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	auditService := audit.NewService(auditRepo)
+	instrumentedNews := news.NewInstrumentedRepository(newsRepo)
+	newsCache := news.NewMemoryCache(0)
+	apiKeys, err := handlers.ParseAPIKeys([]string{"editor@example.com:" + adminAPIKey,
+		"reporter@example.com:" + reporterAPIKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rateLimit.Keys = apiKeys
+	return app.CreateApp(
+		news.NewService(instrumentedNews, tagRepo, mediaRepo, newsCache),
+		tag.NewService(tagRepo),
+		comment.NewService(commentRepo, newsRepo, comment.NewWordListModerator(nil, false), newsCache),
+		media.NewService(mediaRepo, store, 1<<20, 1<<20, []string{"image/png"}, nil),
+		news.NewTransfer(newsRepo, tagRepo, mediaRepo),
+		webhook.NewService(webhookRepo, background.dispatcher),
+		auditService,
+		rateLimit,
+		checker,
+		handlers.CachePolicies{News: "public, max-age=60", NewsList: "public, no-cache", Tags: "public, max-age=300"},
+		handlers.IdempotencyConfig{Store: idempotency.NewMemoryStore(), TTL: time.Hour},
+		apiKeys,
+		proxies,
+	), background
+}
+
+type response struct {
+	Status int                 `json:"status"`
+	Code   string              `json:"code"`
+	Detail string              `json:"detail"`
+	Errors []failure.Violation `json:"errors"`
+	Data   json.RawMessage     `json:"data"`
+}
+
+func call(t *testing.T, fiberApp *fiber.App, method string, target string, body string, data interface{}) response {
+	return send(t, fiberApp, newRequest(method, target, body), data)
+}
+
+// callAdmin is call authenticated with adminAPIKey.
+func callAdmin(t *testing.T, fiberApp *fiber.App, method string, target string, body string, data interface{}) response {
+	req := newRequest(method, target, body)
+	req.Header.Set(handlers.HeaderAPIKey, adminAPIKey)
+	return send(t, fiberApp, req, data)
+}
+
+func newRequest(method string, target string, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func send(t *testing.T, fiberApp *fiber.App, req *http.Request, data interface{}) response {
+	res, err := fiberApp.Test(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	var result response
+	err = json.NewDecoder(res.Body).Decode(&result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status == 0 {
+		result.Status = res.StatusCode
+	}
+	if data != nil && result.Data != nil {
+		err = json.Unmarshal(result.Data, data)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return result
+}
+
+func TestNewsLifecycle(t *testing.T) {
+	fiberApp, _ := newApp(t)
+
+	var footballTag entities.TagDto
+	res := call(t, fiberApp, http.MethodPost, "/api/v1/tag/", `{"name": "football"}`, &footballTag)
+	assert.Equal(t, res.Status, http.StatusCreated)
+
+	var created entities.NewsDto
+	res = call(t, fiberApp, http.MethodPost, "/api/v1/news/", `{"title": "derby day", "content": "the derby ends in a draw",
+		"status": "publish", "topic": "sport", "tags": ["`+footballTag.ID+`"]}`, &created)
+	assert.Equal(t, res.Status, http.StatusCreated)
+	assert.Equal(t, created.Slug, "derby-day")
+
+	var got entities.NewsDto
+	res = call(t, fiberApp, http.MethodGet, "/api/v1/news/derby-day", "", &got)
+	assert.Equal(t, res.Status, http.StatusOK)
+	assert.Equal(t, got.ID, created.ID)
+	assert.Equal(t, got.Tags, []string{"football"})
+
+	var found entities.SliceNewsDto
+	res = call(t, fiberApp, http.MethodGet, "/api/v1/news/search?q=derby", "", &found)
+	assert.Equal(t, res.Status, http.StatusOK)
+	assert.Equal(t, len(found), 1)
+
+	res = call(t, fiberApp, http.MethodPatch, "/api/v1/news/"+created.ID, `{"title": "derby day", "content": "a late winner",
+		"status": "draft", "topic": "sport", "tags": ["`+footballTag.ID+`"]}`, nil)
+	assert.Equal(t, res.Status, http.StatusOK)
+
+	var drafts entities.SliceNewsDto
+	res = call(t, fiberApp, http.MethodGet, "/api/v1/news/status/draft", "", &drafts)
+	assert.Equal(t, res.Status, http.StatusOK)
+	assert.Equal(t, len(drafts), 1)
+
+	res = call(t, fiberApp, http.MethodDelete, "/api/v1/news/"+created.ID, "", nil)
+	assert.Equal(t, res.Status, http.StatusOK)
+
+	res = call(t, fiberApp, http.MethodGet, "/api/v1/news/", "", nil)
+	assert.Equal(t, res.Status, http.StatusNotFound)
+}
+
+func TestCommentModeration(t *testing.T) {
+	fiberApp, _ := newApp(t)
+
+	var footballTag entities.TagDto
+	call(t, fiberApp, http.MethodPost, "/api/v1/tag/", `{"name": "football"}`, &footballTag)
+	res := call(t, fiberApp, http.MethodPost, "/api/v1/news/", `{"title": "derby day", "content": "the derby ends in a draw",
+		"status": "publish", "topic": "sport", "tags": ["`+footballTag.ID+`"]}`, nil)
+	assert.Equal(t, res.Status, http.StatusCreated)
+
+	var added entities.CommentDto
+	res = call(t, fiberApp, http.MethodPost, "/api/v1/news/derby-day/comments/", `{"author": "budi", "content": "what a game"}`, &added)
+	assert.Equal(t, res.Status, http.StatusCreated)
+	assert.Equal(t, added.Status, entities.CommentPending.String())
+
+	// moderation is for editors only, adding and reading comments stays open
+	res = call(t, fiberApp, http.MethodGet, "/api/v1/comments/moderation", "", nil)
+	assert.Equal(t, res.Status, http.StatusUnauthorized)
+	assert.Equal(t, res.Code, "auth.required")
+	res = call(t, fiberApp, http.MethodPost, "/api/v1/comments/"+added.ID+"/approve", "", nil)
+	assert.Equal(t, res.Status, http.StatusUnauthorized)
+
+	var queue []entities.CommentDto
+	res = callAdmin(t, fiberApp, http.MethodGet, "/api/v1/comments/moderation", "", &queue)
+	assert.Equal(t, res.Status, http.StatusOK)
+	assert.Equal(t, len(queue), 1)
+
+	// the news is cached without comments
+	var got entities.NewsDto
+	call(t, fiberApp, http.MethodGet, "/api/v1/news/derby-day", "", &got)
+	assert.Equal(t, got.CommentCount, 0)
+
+	res = callAdmin(t, fiberApp, http.MethodPost, "/api/v1/comments/"+added.ID+"/approve", "", nil)
+	assert.Equal(t, res.Status, http.StatusOK)
+	res = callAdmin(t, fiberApp, http.MethodPost, "/api/v1/comments/"+added.ID+"/approve", "", nil)
+	assert.Equal(t, res.Status, http.StatusConflict)
+	assert.Equal(t, res.Code, "comment.transition_not_allowed")
+
+	var comments []entities.CommentDto
+	res = call(t, fiberApp, http.MethodGet, "/api/v1/news/derby-day/comments/", "", &comments)
+	assert.Equal(t, res.Status, http.StatusOK)
+	assert.Equal(t, len(comments), 1)
+
+	call(t, fiberApp, http.MethodGet, "/api/v1/news/derby-day", "", &got)
+	assert.Equal(t, got.CommentCount, 1)
+
+	res = callAdmin(t, fiberApp, http.MethodPost, "/api/v1/comments/"+added.ID+"/spam", "", nil)
+	assert.Equal(t, res.Status, http.StatusOK)
+	call(t, fiberApp, http.MethodGet, "/api/v1/news/derby-day", "", &got)
+	assert.Equal(t, got.CommentCount, 0)
+}
+
+func TestErrors(t *testing.T) {
+	fiberApp, _ := newApp(t)
+
+	res := call(t, fiberApp, http.MethodGet, "/api/v1/news/missing", "", nil)
+	assert.Equal(t, res.Status, http.StatusNotFound)
+	assert.Equal(t, res.Detail, "news not found")
+
+	res = call(t, fiberApp, http.MethodPost, "/api/v1/tag/", `{"name": ""}`, nil)
+	assert.Equal(t, res.Status, http.StatusBadRequest)
+
+	res = call(t, fiberApp, http.MethodPost, "/api/v1/news/derby-day/comments/", `{"author": "budi", "content": "hello"}`, nil)
+	assert.Equal(t, res.Status, http.StatusNotFound)
+}
+
+func TestProblemDetails(t *testing.T) {
+	fiberApp, _ := newApp(t)
+	problem := func(method string, target string, body string) (result handlers.Problem) {
+		res, err := fiberApp.Test(newRequest(method, target, body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		assert.Equal(t, res.Header.Get("Content-Type"), "application/problem+json")
+		err = json.NewDecoder(res.Body).Decode(&result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, result.Status, res.StatusCode)
+		return
+	}
+
+	result := problem(http.MethodGet, "/api/v1/news/missing?lang=en", "")
+	assert.Equal(t, result.Type, "about:blank")
+	assert.Equal(t, result.Title, "Not Found")
+	assert.Equal(t, result.Status, http.StatusNotFound)
+	assert.Equal(t, result.Detail, "news not found")
+	assert.Equal(t, result.Instance, "/api/v1/news/missing?lang=en")
+	assert.Equal(t, result.Code, "news.not_found")
+	assert.Equal(t, result.RequestID != "", true)
+
+	result = problem(http.MethodPost, "/api/v1/news/", `{"title": "derby day", "status": "soon"}`)
+	assert.Equal(t, result.Status, http.StatusBadRequest)
+	assert.Equal(t, result.Code, "validation.failed")
+	assert.Equal(t, result.Errors, []failure.Violation{
+		{Field: "content", Message: "content can't be null"},
+		{Field: "status", Message: "status not valid"},
+		{Field: "topic", Message: "topic can't be null"},
+		{Field: "tags", Message: "please insert tags"},
+	})
+
+	// unique keys answer 409 instead of 500
+	var footballTag entities.TagDto
+	call(t, fiberApp, http.MethodPost, "/api/v1/tag/", `{"name": "football"}`, &footballTag)
+	result = problem(http.MethodPost, "/api/v1/tag/", `{"name": "football"}`)
+	assert.Equal(t, result.Status, http.StatusConflict)
+	assert.Equal(t, result.Code, "tag.name_taken")
+	body := `{"title": "derby day", "content": "the derby ends in a draw", "status": "publish", "topic": "sport",
+		"tags": ["` + footballTag.ID + `"]}`
+	call(t, fiberApp, http.MethodPost, "/api/v1/news/", body, nil)
+	result = problem(http.MethodPost, "/api/v1/news/", body)
+	assert.Equal(t, result.Status, http.StatusConflict)
+	assert.Equal(t, result.Code, "news.slug_taken")
+
+	result = problem(http.MethodGet, "/api/v1/unknown", "")
+	assert.Equal(t, result.Status, http.StatusNotFound)
+	assert.Equal(t, result.Code, "route.not_found")
+	// the path has routes for other methods
+	result = problem(http.MethodPatch, "/api/v1/tag/", "")
+	assert.Equal(t, result.Status, http.StatusMethodNotAllowed)
+	assert.Equal(t, result.Code, "method_not_allowed")
+
+	result = problem(http.MethodPost, "/api/v1/news/", `{"title": `)
+	assert.Equal(t, result.Status, http.StatusBadRequest)
+	assert.Equal(t, result.Code, "request.malformed")
+	req := newRequest(http.MethodPut, "/api/v1/tag/"+footballTag.ID, "name=soccer")
+	req.Header.Set("Content-Type", "text/plain")
+	res, err := fiberApp.Test(req)
+	assert.Equal(t, err, nil)
+	err = json.NewDecoder(res.Body).Decode(&result)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, result.Status, http.StatusBadRequest)
+	assert.Equal(t, result.Code, "request.malformed")
+}
+
+func TestEventsPublished(t *testing.T) {
+	fiberApp, background := newApp(t)
+
+	var footballTag entities.TagDto
+	call(t, fiberApp, http.MethodPost, "/api/v1/tag/", `{"name": "football"}`, &footballTag)
+	var created entities.NewsDto
+	call(t, fiberApp, http.MethodPost, "/api/v1/news/", `{"title": "derby day", "content": "the derby ends in a draw",
+		"status": "publish", "topic": "sport", "tags": ["`+footballTag.ID+`"]}`, &created)
+	call(t, fiberApp, http.MethodDelete, "/api/v1/news/"+created.ID, "", nil)
+
+	background.flush(t)
+	var types []string
+	for _, event := range background.published.Events() {
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, types, []string{"TagCreated", "NewsCreated", "NewsPublished", "NewsDeleted"})
+}
+
+func TestWebhookDelivery(t *testing.T) {
+	fiberApp, background := newApp(t)
+	received := make(chan *http.Request, 10)
+	status := int32(http.StatusServiceUnavailable)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		received <- req
+		w.WriteHeader(int(atomic.LoadInt32(&status)))
+	}))
+	defer receiver.Close()
+
+	var hook entities.WebhookDto
+	res := callAdmin(t, fiberApp, http.MethodPost, "/api/v1/admin/webhooks/",
+		`{"url": "`+receiver.URL+`", "event_types": ["NewsPublished"], "secret": "secret"}`, &hook)
+	assert.Equal(t, res.Status, http.StatusCreated)
+	res = callAdmin(t, fiberApp, http.MethodPost, "/api/v1/admin/webhooks/", `{"url": "ftp://example.com", "event_types": ["Unknown"]}`, nil)
+	assert.Equal(t, res.Status, http.StatusBadRequest)
+	assert.Equal(t, res.Detail, "url not valid, event type Unknown not valid")
+
+	var footballTag entities.TagDto
+	call(t, fiberApp, http.MethodPost, "/api/v1/tag/", `{"name": "football"}`, &footballTag)
+	call(t, fiberApp, http.MethodPost, "/api/v1/news/", `{"title": "derby day", "content": "the derby ends in a draw",
+		"status": "publish", "topic": "sport", "tags": ["`+footballTag.ID+`"]}`, nil)
+	background.flush(t)
+
+	req := <-received
+	assert.Equal(t, req.Header.Get(webhook.HeaderEvent), "NewsPublished")
+	var deliveries []entities.WebhookDeliveryDto
+	res = callAdmin(t, fiberApp, http.MethodGet, "/api/v1/admin/webhooks/"+hook.ID+"/deliveries", "", &deliveries)
+	assert.Equal(t, res.Status, http.StatusOK)
+	assert.Equal(t, len(deliveries), 1)
+	assert.Equal(t, deliveries[0].Status, "pending")
+	assert.Equal(t, deliveries[0].ResponseCode, http.StatusServiceUnavailable)
+
+	atomic.StoreInt32(&status, http.StatusOK)
+	var redelivered entities.WebhookDeliveryDto
+	res = callAdmin(t, fiberApp, http.MethodPost, "/api/v1/admin/webhooks/"+hook.ID+"/deliveries/"+deliveries[0].ID+"/redeliver",
+		"", &redelivered)
+	assert.Equal(t, res.Status, http.StatusOK)
+	assert.Equal(t, redelivered.Status, "succeeded")
+	assert.Equal(t, redelivered.Attempts, 2)
+	<-received
+
+	res = callAdmin(t, fiberApp, http.MethodDelete, "/api/v1/admin/webhooks/"+hook.ID, "", nil)
+	assert.Equal(t, res.Status, http.StatusOK)
+	var hooks []entities.WebhookDto
+	callAdmin(t, fiberApp, http.MethodGet, "/api/v1/admin/webhooks/", "", &hooks)
+	assert.Equal(t, len(hooks), 0)
+}
+
+func TestAdminAuth(t *testing.T) {
+	fiberApp, _ := newApp(t)
+
+	res := call(t, fiberApp, http.MethodGet, "/api/v1/admin/webhooks/", "", nil)
+	assert.Equal(t, res.Status, http.StatusUnauthorized)
+	assert.Equal(t, res.Code, "auth.required")
+	req := newRequest(http.MethodGet, "/api/v1/admin/news/export", "")
+	req.Header.Set(handlers.HeaderAPIKey, "wrong-key")
+	res = send(t, fiberApp, req, nil)
+	assert.Equal(t, res.Status, http.StatusUnauthorized)
+	assert.Equal(t, res.Code, "auth.api_key_not_valid")
+
+	res = callAdmin(t, fiberApp, http.MethodGet, "/api/v1/admin/webhooks/", "", nil)
+	assert.Equal(t, res.Status, http.StatusOK)
+}
+
+func TestAuditLog(t *testing.T) {
+	fiberApp, _ := newApp(t)
+
+	req := newRequest(http.MethodPost, "/api/v1/tag/", `{"name": "football"}`)
+	req.Header.Set(handlers.HeaderAPIKey, adminAPIKey)
+	req.Header.Set("X-Request-ID", "request-1")
+	var footballTag entities.TagDto
+	res := send(t, fiberApp, req, &footballTag)
+	assert.Equal(t, res.Status, http.StatusCreated)
+	var created entities.NewsDto
+	call(t, fiberApp, http.MethodPost, "/api/v1/news/", `{"title": "derby day", "content": "the derby ends in a draw",
+		"status": "publish", "topic": "sport", "tags": ["`+footballTag.ID+`"]}`, &created)
+	call(t, fiberApp, http.MethodDelete, "/api/v1/news/"+created.ID, "", nil)
+
+	// the audit log is only for authenticated callers
+	res = call(t, fiberApp, http.MethodGet, "/api/v1/audit/", "", nil)
+	assert.Equal(t, res.Status, http.StatusUnauthorized)
+
+	var entries []entities.AuditEntryDto
+	res = callAdmin(t, fiberApp, http.MethodGet, "/api/v1/audit/?entity_type=news&entity_id="+created.ID, "", &entries)
+	assert.Equal(t, res.Status, http.StatusOK)
+	assert.Equal(t, len(entries), 2)
+	actions := map[string]entities.AuditEntryDto{}
+	for _, entry := range entries {
+		assert.Equal(t, entry.Actor, "anonymous")
+		actions[entry.Action] = entry
+	}
+	assert.Equal(t, string(actions["create"].Before), "null")
+	assert.Equal(t, string(actions["delete"].After), "null")
+	var deleted entities.NewsDto
+	err := json.Unmarshal(actions["delete"].Before, &deleted)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, deleted.Slug, "derby-day")
+
+	res = callAdmin(t, fiberApp, http.MethodGet, "/api/v1/audit/?actor=editor@example.com", "", &entries)
+	assert.Equal(t, res.Status, http.StatusOK)
+	assert.Equal(t, len(entries), 1)
+	assert.Equal(t, entries[0].EntityType, "tag")
+	assert.Equal(t, entries[0].RequestID, "request-1")
+	assert.Equal(t, entries[0].IP != "", true)
+
+	// imports are audited like the writes of the API
+	var report entities.ImportReport
+	res = callAdmin(t, fiberApp, http.MethodPost, "/api/v1/admin/news/import?format=jsonl", `{"title": "cup final", `+
+		`"content": "the cup goes home", "status": "publish", "topic": "sport", "tags": ["football", "cup"]}`, &report)
+	assert.Equal(t, res.Status, http.StatusOK)
+	assert.Equal(t, report.Imported, 1)
+	res = callAdmin(t, fiberApp, http.MethodGet, "/api/v1/audit/?actor=editor@example.com", "", &entries)
+	assert.Equal(t, res.Status, http.StatusOK)
+	assert.Equal(t, len(entries), 3)
+	var types []string
+	for _, entry := range entries {
+		assert.Equal(t, entry.Action, "create")
+		types = append(types, entry.EntityType)
+	}
+	sort.Strings(types)
+	assert.Equal(t, types, []string{"news", "tag", "tag"})
+
+	res = callAdmin(t, fiberApp, http.MethodGet, "/api/v1/audit/?from="+time.Now().Add(time.Hour).Format(time.RFC3339), "", &entries)
+	assert.Equal(t, res.Status, http.StatusOK)
+	assert.Equal(t, len(entries), 0)
+
+	res = callAdmin(t, fiberApp, http.MethodGet, "/api/v1/audit/?entity_type=comment&from=yesterday", "", nil)
+	assert.Equal(t, res.Status, http.StatusBadRequest)
+	assert.Equal(t, res.Detail, "entity_type not valid, from not valid")
+}
+
+func TestRateLimit(t *testing.T) {
+	fiberApp, _ := newRateLimitedApp(t, handlers.RateLimitConfig{
+		Limiter: ratelimit.NewMemoryLimiter(),
+		KeyBy:   handlers.RateLimitByAPIKey,
+		Read:    ratelimit.Budget{Limit: 2, Window: time.Hour},
+		Write:   ratelimit.Budget{Limit: 1, Window: time.Hour},
+	})
+	get := func(apiKey string) *http.Response {
+		req := newRequest(http.MethodGet, "/api/v1/audit/", "")
+		req.Header.Set("X-API-Key", apiKey)
+		res, err := fiberApp.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	res := get(adminAPIKey)
+	assert.Equal(t, res.StatusCode, http.StatusOK)
+	assert.Equal(t, res.Header.Get("RateLimit-Limit"), "2")
+	assert.Equal(t, res.Header.Get("RateLimit-Remaining"), "1")
+	assert.Equal(t, res.Header.Get("RateLimit-Reset") != "", true)
+	assert.Equal(t, get(adminAPIKey).StatusCode, http.StatusOK)
+
+	res = get(adminAPIKey)
+	assert.Equal(t, res.StatusCode, http.StatusTooManyRequests)
+	assert.Equal(t, res.Header.Get("RateLimit-Remaining"), "0")
+	assert.Equal(t, res.Header.Get("Retry-After") != "", true)
+	var body response
+	err := json.NewDecoder(res.Body).Decode(&body)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, body.Status, http.StatusTooManyRequests)
+	assert.Equal(t, body.Code, "too_many_requests")
+	assert.Equal(t, body.Detail, "too many requests")
+
+	// every key has its own budget, and writes have their own
+	assert.Equal(t, get(reporterAPIKey).StatusCode, http.StatusOK)
+	// made up keys share the budget of their IP
+	assert.Equal(t, get("unknown-key1").StatusCode, http.StatusUnauthorized)
+	assert.Equal(t, get("unknown-key2").StatusCode, http.StatusUnauthorized)
+	assert.Equal(t, get("unknown-key3").StatusCode, http.StatusTooManyRequests)
+	req := newRequest(http.MethodPost, "/api/v1/tag/", `{"name": "football"}`)
+	req.Header.Set("X-API-Key", adminAPIKey)
+	assert.Equal(t, send(t, fiberApp, req, nil).Status, http.StatusCreated)
+}
+
+func TestTrustedProxies(t *testing.T) {
+	rateLimit := handlers.RateLimitConfig{
+		Limiter: ratelimit.NewMemoryLimiter(),
+		KeyBy:   handlers.RateLimitByIP,
+		Read:    ratelimit.Budget{Limit: 1, Window: time.Hour},
+	}
+	get := func(fiberApp *fiber.App, ip string) int {
+		req := newRequest(http.MethodGet, "/api/v1/news/missing", "")
+		req.Header.Set("X-Real-IP", ip)
+		return send(t, fiberApp, req, nil).Status
+	}
+
+	// the test requests come from 0.0.0.0
+	fiberApp, _ := newProxiedApp(t, rateLimit, app.Proxies{Header: "X-Real-IP", Trusted: []string{"0.0.0.0/32"}})
+	assert.Equal(t, get(fiberApp, "10.0.0.1"), http.StatusNotFound)
+	assert.Equal(t, get(fiberApp, "10.0.0.1"), http.StatusTooManyRequests)
+	assert.Equal(t, get(fiberApp, "10.0.0.2"), http.StatusNotFound)
+
+	rateLimit.Limiter = ratelimit.NewMemoryLimiter()
+	fiberApp, _ = newProxiedApp(t, rateLimit, app.Proxies{Header: "X-Real-IP", Trusted: []string{"10.0.0.0/8"}})
+	assert.Equal(t, get(fiberApp, "10.0.0.1"), http.StatusNotFound)
+	assert.Equal(t, get(fiberApp, "10.0.0.2"), http.StatusTooManyRequests)
+}
+
+func TestRequestLogging(t *testing.T) {
+	var output bytes.Buffer
+	global := log.Logger
+	log.Logger = zerolog.New(&output)
+	defer func() { log.Logger = global }()
+	fiberApp, _ := newApp(t)
+
+	var footballTag entities.TagDto
+	call(t, fiberApp, http.MethodPost, "/api/v1/tag/", `{"name": "football"}`, &footballTag)
+	call(t, fiberApp, http.MethodPost, "/api/v1/news/", `{"title": "derby day", "content": "the derby ends in a draw",
+		"status": "publish", "topic": "sport", "tags": ["`+footballTag.ID+`"]}`, nil)
+	output.Reset()
+
+	for _, requestID := range []string{"request-1", "request-2"} {
+		req := newRequest(http.MethodGet, "/api/v1/news/derby-day", "")
+		req.Header.Set("X-Request-ID", requestID)
+		res, err := fiberApp.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, res.Header.Get("X-Request-ID"), requestID)
+	}
+	res := call(t, fiberApp, http.MethodGet, "/api/v1/news/missing", "", nil)
+	assert.Equal(t, res.Status, http.StatusNotFound)
+
+	var lines []map[string]interface{}
+	decoder := json.NewDecoder(&output)
+	for decoder.More() {
+		var line map[string]interface{}
+		if err := decoder.Decode(&line); err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, line)
+	}
+	assert.Equal(t, len(lines), 3)
+	assert.Equal(t, lines[0]["request_id"], "request-1")
+	assert.Equal(t, lines[0]["cache"], "miss")
+	assert.Equal(t, lines[0]["status"], float64(http.StatusOK))
+	assert.Equal(t, lines[0]["route"], "/api/v1/news/:slug")
+	assert.Equal(t, lines[0]["message"], "request")
+	assert.Equal(t, lines[1]["request_id"], "request-2")
+	assert.Equal(t, lines[1]["cache"], "hit")
+	assert.Equal(t, lines[2]["level"], "warn")
+	assert.Equal(t, lines[2]["request_id"] != "", true)
+}
+
+func TestMetrics(t *testing.T) {
+	fiberApp, _ := newApp(t)
+
+	var footballTag entities.TagDto
+	call(t, fiberApp, http.MethodPost, "/api/v1/tag/", `{"name": "football"}`, &footballTag)
+	call(t, fiberApp, http.MethodPost, "/api/v1/news/", `{"title": "derby day", "content": "the derby ends in a draw",
+		"status": "publish", "topic": "sport", "tags": ["`+footballTag.ID+`"]}`, nil)
+	call(t, fiberApp, http.MethodGet, "/api/v1/news/derby-day", "", nil)
+	call(t, fiberApp, http.MethodGet, "/api/v1/news/derby-day", "", nil)
+	res, err := fiberApp.Test(newRequest(http.MethodGet, "/unknown/path", ""))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, res.StatusCode, http.StatusNotFound)
+
+	res, err = fiberApp.Test(newRequest(http.MethodGet, "/metrics", ""))
+	assert.Equal(t, err, nil)
+	assert.Equal(t, res.StatusCode, http.StatusOK)
+	body, err := io.ReadAll(res.Body)
+	assert.Equal(t, err, nil)
+	for _, expected := range []string{
+		`http_request_duration_seconds_count{method="GET",route="/api/v1/news/:slug",status="200"}`,
+		`http_request_duration_seconds_count{method="POST",route="/api/v1/tag",status="201"}`,
+		`http_request_duration_seconds_count{method="GET",route="unmatched",status="404"}`,
+		`news_cache_requests_total{family="slug",result="hit"}`,
+		`news_cache_requests_total{family="slug",result="miss"}`,
+		`repository_query_duration_seconds_count{method="GetNewsBySlug",repository="news"}`,
+	} {
+		assert.Equal(t, strings.Contains(string(body), expected), true, expected)
+	}
+}
+
+func TestHealth(t *testing.T) {
+	up := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+	var output bytes.Buffer
+	global := log.Logger
+	log.Logger = zerolog.New(&output)
+	defer func() { log.Logger = global }()
+	probe := func(fiberApp *fiber.App, target string) (int, health.Report) {
+		res, err := fiberApp.Test(newRequest(http.MethodGet, target, ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := io.ReadAll(res.Body)
+		assert.Equal(t, err, nil)
+		// the errors of the dependencies are only logged
+		assert.Equal(t, strings.Contains(string(body), "connection refused"), false)
+		var report health.Report
+		err = json.Unmarshal(body, &report)
+		assert.Equal(t, err, nil)
+		return res.StatusCode, report
+	}
+
+	fiberApp := newCheckedApp(t, health.Check{Name: "database", Required: true, Ping: up},
+		health.Check{Name: "redis", Ping: down})
+	status, report := probe(fiberApp, "/healthz")
+	assert.Equal(t, status, http.StatusOK)
+	assert.Equal(t, report.Status, health.StatusUp)
+	status, report = probe(fiberApp, "/readyz")
+	assert.Equal(t, status, http.StatusOK)
+	assert.Equal(t, report.Status, health.StatusDegraded)
+	assert.Equal(t, report.Checks["database"].Status, health.StatusUp)
+	assert.Equal(t, report.Checks["redis"].Status, health.StatusDown)
+	assert.Equal(t, strings.Contains(output.String(), `"check":"redis","error":"connection refused"`), true)
+	assert.Equal(t, report.Build.GoVersion != "", true)
+
+	fiberApp = newCheckedApp(t, health.Check{Name: "database", Required: true, Ping: down})
+	status, report = probe(fiberApp, "/readyz")
+	assert.Equal(t, status, http.StatusServiceUnavailable)
+	assert.Equal(t, report.Status, health.StatusUnavailable)
+	status, _ = probe(fiberApp, "/healthz")
+	assert.Equal(t, status, http.StatusOK)
+}
+
+func TestConditionalRequests(t *testing.T) {
+	fiberApp, _ := newApp(t)
+
+	var footballTag entities.TagDto
+	call(t, fiberApp, http.MethodPost, "/api/v1/tag/", `{"name": "football"}`, &footballTag)
+	var created entities.NewsDto
+	call(t, fiberApp, http.MethodPost, "/api/v1/news/", `{"title": "derby day", "content": "the derby ends in a draw",
+		"status": "publish", "topic": "sport", "tags": ["`+footballTag.ID+`"]}`, &created)
+	get := func(target string, header string, value string) *http.Response {
+		req := newRequest(http.MethodGet, target, "")
+		if header != "" {
+			req.Header.Set(header, value)
+		}
+		res, err := fiberApp.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	res := get("/api/v1/news/derby-day", "", "")
+	assert.Equal(t, res.StatusCode, http.StatusOK)
+	assert.Equal(t, res.Header.Get("Cache-Control"), "public, max-age=60")
+	etag, lastModified := res.Header.Get("ETag"), res.Header.Get("Last-Modified")
+	assert.Equal(t, strings.HasPrefix(etag, `"`), true)
+	assert.Equal(t, lastModified != "", true)
+
+	res = get("/api/v1/news/derby-day", "If-None-Match", `"other", `+etag)
+	assert.Equal(t, res.StatusCode, http.StatusNotModified)
+	assert.Equal(t, res.Header.Get("ETag"), etag)
+	assert.Equal(t, res.Header.Get("Cache-Control"), "public, max-age=60")
+	body, err := io.ReadAll(res.Body)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(body), 0)
+	assert.Equal(t, get("/api/v1/news/derby-day", "If-None-Match", `"other"`).StatusCode, http.StatusOK)
+	assert.Equal(t, get("/api/v1/news/derby-day", "If-Modified-Since", lastModified).StatusCode,
+		http.StatusNotModified)
+	assert.Equal(t, get("/api/v1/news/derby-day", "If-Modified-Since", "Sat, 01 Jan 2000 00:00:00 GMT").StatusCode,
+		http.StatusOK)
+	// an edit changes the tag of the cached news too
+	res = get("/api/v1/news/derby-day", "If-None-Match", etag)
+	assert.Equal(t, res.StatusCode, http.StatusNotModified)
+	call(t, fiberApp, http.MethodPut, "/api/v1/news/"+created.ID, `{"title": "derby day", "content": "a late winner",
+		"status": "publish", "topic": "sport", "tags": ["`+footballTag.ID+`"]}`, nil)
+	res = get("/api/v1/news/derby-day", "If-None-Match", etag)
+	assert.Equal(t, res.StatusCode, http.StatusOK)
+	assert.Equal(t, res.Header.Get("ETag") != etag, true)
+	assert.Equal(t, strings.HasPrefix(res.Header.Get("ETag"), `"v2-`), true)
+
+	res = get("/api/v1/news/", "", "")
+	assert.Equal(t, res.Header.Get("Cache-Control"), "public, no-cache")
+	assert.Equal(t, res.Header.Get("Last-Modified"), "")
+	assert.Equal(t, get("/api/v1/news/", "If-None-Match", res.Header.Get("ETag")).StatusCode, http.StatusNotModified)
+	// lists only answer the ETag
+	assert.Equal(t, get("/api/v1/news/", "If-Modified-Since", lastModified).StatusCode, http.StatusOK)
+	// errors are never cached
+	res = get("/api/v1/news/missing", "", "")
+	assert.Equal(t, res.StatusCode, http.StatusNotFound)
+	assert.Equal(t, res.Header.Get("Cache-Control"), "")
+
+	res = get("/api/v1/tag/", "", "")
+	assert.Equal(t, res.Header.Get("Cache-Control"), "public, max-age=300")
+	assert.Equal(t, res.Header.Get("Last-Modified"), "")
+	etag = res.Header.Get("ETag")
+	assert.Equal(t, get("/api/v1/tag/", "If-None-Match", etag).StatusCode, http.StatusNotModified)
+	call(t, fiberApp, http.MethodPut, "/api/v1/tag/"+footballTag.ID, `{"name": "soccer"}`, nil)
+	res = get("/api/v1/tag/", "If-None-Match", etag)
+	assert.Equal(t, res.StatusCode, http.StatusOK)
+	assert.Equal(t, res.Header.Get("ETag") != etag, true)
+}
+
+func TestOptimisticConcurrency(t *testing.T) {
+	fiberApp, _ := newApp(t)
+
+	var footballTag entities.TagDto
+	call(t, fiberApp, http.MethodPost, "/api/v1/tag/", `{"name": "football"}`, &footballTag)
+	assert.Equal(t, footballTag.Version, 1)
+	var news entities.NewsDto
+	call(t, fiberApp, http.MethodPost, "/api/v1/news/", `{"title": "derby day", "content": "the derby ends in a draw",
+		"status": "publish", "topic": "sport", "tags": ["`+footballTag.ID+`"]}`, &news)
+	assert.Equal(t, news.Version, 1)
+	res, err := fiberApp.Test(newRequest(http.MethodGet, "/api/v1/news/derby-day", ""))
+	assert.Equal(t, err, nil)
+	etag := res.Header.Get("ETag")
+	assert.Equal(t, strings.HasPrefix(etag, `"v1-`), true)
+
+	update := func(ifMatch string, body string, data interface{}) (response, string) {
+		req := newRequest(http.MethodPatch, "/api/v1/news/"+news.ID, body)
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		res, err := fiberApp.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		var result response
+		err = json.NewDecoder(res.Body).Decode(&result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result.Status = res.StatusCode
+		if data != nil && result.Data != nil {
+			err = json.Unmarshal(result.Data, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		return result, res.Header.Get("ETag")
+	}
+	body := `{"title": "derby day", "content": "the derby ends in a late win", "status": "publish", "topic": "sport",
+		"tags": ["` + footballTag.ID + `"]}`
+
+	result, _ := update(etag, body, nil)
+	assert.Equal(t, result.Status, http.StatusOK)
+	// the second write with the same tag lost the race
+	var current entities.NewsDto
+	result, currentTag := update(etag, body, &current)
+	assert.Equal(t, result.Status, http.StatusConflict)
+	assert.Equal(t, result.Detail, "news was changed by another write")
+	assert.Equal(t, current.Version, 2)
+	assert.Equal(t, current.Content, "the derby ends in a late win")
+	assert.Equal(t, strings.HasPrefix(currentTag, `"v2-`), true)
+
+	result, _ = update(currentTag, body, nil)
+	assert.Equal(t, result.Status, http.StatusOK)
+	result, _ = update("*", body, nil)
+	assert.Equal(t, result.Status, http.StatusOK)
+	result, _ = update(`"other"`, body, nil)
+	assert.Equal(t, result.Status, http.StatusBadRequest)
+	assert.Equal(t, result.Detail, "If-Match not valid")
+	// without If-Match the version of the body is checked
+	result, _ = update("", `{"title": "derby day", "content": "stale", "status": "publish", "topic": "sport",
+		"tags": ["`+footballTag.ID+`"], "version": 2}`, nil)
+	assert.Equal(t, result.Status, http.StatusConflict)
+	result, _ = update("", `{"title": "derby day", "content": "fresh", "status": "publish", "topic": "sport",
+		"tags": ["`+footballTag.ID+`"], "version": 4}`, nil)
+	assert.Equal(t, result.Status, http.StatusOK)
+
+	var tag entities.TagDto
+	result = call(t, fiberApp, http.MethodPut, "/api/v1/tag/"+footballTag.ID, `{"name": "soccer", "version": 1}`, &tag)
+	assert.Equal(t, result.Status, http.StatusOK)
+	assert.Equal(t, tag.Version, 2)
+	result = call(t, fiberApp, http.MethodPut, "/api/v1/tag/"+footballTag.ID, `{"name": "futsal", "version": 1}`, &tag)
+	assert.Equal(t, result.Status, http.StatusConflict)
+	assert.Equal(t, tag.Name, "soccer")
+	assert.Equal(t, tag.Version, 2)
+}
+
+func TestNewsCacheInvalidation(t *testing.T) {
+	fiberApp, _ := newApp(t)
+
+	var footballTag entities.TagDto
+	call(t, fiberApp, http.MethodPost, "/api/v1/tag/", `{"name": "football"}`, &footballTag)
+	var created entities.NewsDto
+	call(t, fiberApp, http.MethodPost, "/api/v1/news/", `{"title": "derby day", "content": "the derby ends in a draw",
+		"status": "publish", "topic": "sport", "tags": ["`+footballTag.ID+`"]}`, &created)
+	put := func(ifMatch string, title string) int {
+		req := newRequest(http.MethodPut, "/api/v1/news/"+created.ID, `{"title": "`+title+`",
+			"content": "the derby ends in a draw", "status": "publish", "topic": "sport", "tags": ["`+footballTag.ID+`"]}`)
+		req.Header.Set("If-Match", ifMatch)
+		return send(t, fiberApp, req, nil).Status
+	}
+	get := func() (entities.NewsDto, string) {
+		res, err := fiberApp.Test(newRequest(http.MethodGet, "/api/v1/news/derby-day", ""))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		var result response
+		var got entities.NewsDto
+		err = json.NewDecoder(res.Body).Decode(&result)
+		if err == nil && res.StatusCode == http.StatusOK {
+			err = json.Unmarshal(result.Data, &got)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		return got, res.Header.Get("ETag")
+	}
+
+	got, etag := get()
+	assert.Equal(t, got.Version, 1)
+	var all entities.SliceNewsDto
+	call(t, fiberApp, http.MethodGet, "/api/v1/news/", "", &all)
+	assert.Equal(t, all[0].Title, "derby day")
+
+	// the write drops the cached reads, the next read sees its version
+	assert.Equal(t, put(etag, "derby night"), http.StatusOK)
+	got, etag = get()
+	assert.Equal(t, got.Title, "derby night")
+	assert.Equal(t, got.Version, 2)
+	assert.Equal(t, strings.HasPrefix(etag, `"v2-`), true)
+	call(t, fiberApp, http.MethodGet, "/api/v1/news/", "", &all)
+	assert.Equal(t, all[0].Title, "derby night")
+	assert.Equal(t, put(etag, "derby evening"), http.StatusOK)
+
+	got, _ = get()
+	assert.Equal(t, got.Title, "derby evening")
+	res := call(t, fiberApp, http.MethodDelete, "/api/v1/news/"+created.ID, "", nil)
+	assert.Equal(t, res.Status, http.StatusOK)
+	res = call(t, fiberApp, http.MethodGet, "/api/v1/news/derby-day", "", nil)
+	assert.Equal(t, res.Status, http.StatusNotFound)
+	res = call(t, fiberApp, http.MethodGet, "/api/v1/news/", "", nil)
+	assert.Equal(t, res.Status, http.StatusNotFound)
+}
+
+func TestRenderedContent(t *testing.T) {
+	fiberApp, _ := newApp(t)
+
+	var footballTag entities.TagDto
+	call(t, fiberApp, http.MethodPost, "/api/v1/tag/", `{"name": "football"}`, &footballTag)
+	var created entities.NewsDto
+	call(t, fiberApp, http.MethodPost, "/api/v1/news/", `{"title": "derby day", "content": "a **draw**",
+		"content_format": "markdown", "status": "publish", "topic": "sport", "tags": ["`+footballTag.ID+`"]}`, &created)
+	patchNews := func(body string) {
+		req := newRequest(http.MethodPatch, "/api/v1/news/"+created.ID, body)
+		req.Header.Set("Content-Type", "application/merge-patch+json")
+		assert.Equal(t, send(t, fiberApp, req, nil).Status, http.StatusOK)
+	}
+	rendered := func() string {
+		var got entities.NewsDto
+		res := call(t, fiberApp, http.MethodGet, "/api/v1/news/derby-day", "", &got)
+		assert.Equal(t, res.Status, http.StatusOK)
+		return got.ContentHTML
+	}
+
+	assert.Equal(t, rendered(), "<p>a <strong>draw</strong></p>\n")
+	patchNews(`{"content": "a *late* winner"}`)
+	assert.Equal(t, rendered(), "<p>a <em>late</em> winner</p>\n")
+	patchNews(`{"content_format": "plain"}`)
+	assert.Equal(t, rendered(), "<p>a *late* winner</p>\n")
+}
+
+func TestPatchNews(t *testing.T) {
+	fiberApp, _ := newApp(t)
+
+	var footballTag entities.TagDto
+	call(t, fiberApp, http.MethodPost, "/api/v1/tag/", `{"name": "football"}`, &footballTag)
+	var created entities.NewsDto
+	call(t, fiberApp, http.MethodPost, "/api/v1/news/", `{"title": "derby day", "content": "the derby ends in a draw",
+		"status": "publish", "topic": "sport", "tags": ["`+footballTag.ID+`"]}`, &created)
+	patchNews := func(contentType string, body string) response {
+		req := newRequest(http.MethodPatch, "/api/v1/news/"+created.ID, body)
+		req.Header.Set("Content-Type", contentType)
+		return send(t, fiberApp, req, nil)
+	}
+	search := func() entities.NewsDto {
+		var found entities.SliceNewsDto
+		res := call(t, fiberApp, http.MethodGet, "/api/v1/news/search?q=derby&fields=content", "", &found)
+		assert.Equal(t, res.Status, http.StatusOK)
+		assert.Equal(t, len(found), 1)
+		return found[0]
+	}
+
+	res := patchNews("application/merge-patch+json", `{"content": "a late winner", "tags": []}`)
+	assert.Equal(t, res.Status, http.StatusOK)
+	actual := search()
+	assert.Equal(t, actual.Content, "a late winner")
+	assert.Equal(t, actual.Topic, "sport")
+	assert.Equal(t, len(actual.Tags), 0)
+
+	res = patchNews("application/json-patch+json", `[{"op": "add", "path": "/tags/-", "value": "`+footballTag.ID+`"},
+		{"op": "replace", "path": "/topic", "value": "football"}]`)
+	assert.Equal(t, res.Status, http.StatusOK)
+	actual = search()
+	assert.Equal(t, actual.Topic, "football")
+	assert.Equal(t, actual.Tags, []string{"football"})
+
+	res = patchNews("application/merge-patch+json", `{"content": null}`)
+	assert.Equal(t, res.Status, http.StatusBadRequest)
+	assert.Equal(t, res.Detail, "content can't be null")
+	res = patchNews("application/json-patch+json", `[{"op": "remove", "path": "/summary"}]`)
+	assert.Equal(t, res.Status, http.StatusConflict)
+	res = patchNews("text/plain", `content`)
+	assert.Equal(t, res.Status, http.StatusUnsupportedMediaType)
+
+	// PUT replaces the whole news, but its slug
+	res = call(t, fiberApp, http.MethodPut, "/api/v1/news/"+created.ID, `{"title": "derby day", "slug": "other",
+		"content": "replaced", "status": "publish", "topic": "sport"}`, nil)
+	assert.Equal(t, res.Status, http.StatusOK)
+	actual = search()
+	assert.Equal(t, actual.Slug, "derby-day")
+	assert.Equal(t, actual.Content, "replaced")
+	assert.Equal(t, len(actual.Tags), 0)
+	res = call(t, fiberApp, http.MethodPut, "/api/v1/news/"+created.ID, `{"content": "replaced"}`, nil)
+	assert.Equal(t, res.Status, http.StatusBadRequest)
+
+	// a PUT without translation_group_id or language keeps the news in its group and language
+	var translation entities.NewsDto
+	res = call(t, fiberApp, http.MethodPost, "/api/v1/news/", `{"title": "derby day", "content": "the derby ends in a draw",
+		"status": "publish", "topic": "sport", "tags": ["`+footballTag.ID+`"], "language": "en",
+		"translation_group_id": "`+created.TranslationGroupID+`"}`, &translation)
+	assert.Equal(t, res.Status, http.StatusCreated)
+	res = call(t, fiberApp, http.MethodPut, "/api/v1/news/"+translation.ID, `{"title": "derby day",
+		"content": "replaced", "status": "publish", "topic": "sport"}`, nil)
+	assert.Equal(t, res.Status, http.StatusOK)
+	var translations entities.SliceNewsDto
+	res = call(t, fiberApp, http.MethodGet, "/api/v1/news/derby-day/translations?lang=id", "", &translations)
+	assert.Equal(t, res.Status, http.StatusOK)
+	assert.Equal(t, len(translations), 1)
+	assert.Equal(t, translations[0].ID, translation.ID)
+	assert.Equal(t, translations[0].TranslationGroupID, created.TranslationGroupID)
+	assert.Equal(t, translations[0].Language, "en")
+}
+
+func TestIdempotency(t *testing.T) {
+	fiberApp, _ := newApp(t)
+	post := func(target string, key string, body string, data interface{}) (response, *http.Response) {
+		req := newRequest(http.MethodPost, target, body)
+		req.Header.Set("Idempotency-Key", key)
+		res, err := fiberApp.Test(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+		var result response
+		err = json.NewDecoder(res.Body).Decode(&result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result.Status = res.StatusCode
+		if data != nil && result.Data != nil {
+			err = json.Unmarshal(result.Data, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+		return result, res
+	}
+
+	var first, retried entities.TagDto
+	result, res := post("/api/v1/tag/", "key1", `{"name": "football"}`, &first)
+	assert.Equal(t, result.Status, http.StatusCreated)
+	assert.Equal(t, res.Header.Get("Idempotent-Replayed"), "")
+	result, res = post("/api/v1/tag/", "key1", `{"name": "football"}`, &retried)
+	assert.Equal(t, result.Status, http.StatusCreated)
+	assert.Equal(t, res.Header.Get("Idempotent-Replayed"), "true")
+	assert.Equal(t, res.Header.Get("Content-Type"), "application/json")
+	assert.Equal(t, retried, first)
+	var tags []entities.TagDto
+	call(t, fiberApp, http.MethodGet, "/api/v1/tag/", "", &tags)
+	assert.Equal(t, len(tags), 1)
+
+	result, _ = post("/api/v1/tag/", "key1", `{"name": "tennis"}`, nil)
+	assert.Equal(t, result.Status, http.StatusUnprocessableEntity)
+	// the key of a tag is not the key of a news
+	body := `{"title": "derby day", "content": "the derby ends in a draw", "status": "publish", "topic": "sport",
+		"tags": ["` + first.ID + `"]}`
+	result, _ = post("/api/v1/news/", "key1", body, nil)
+	assert.Equal(t, result.Status, http.StatusUnprocessableEntity)
+
+	var created, replayed entities.NewsDto
+	result, _ = post("/api/v1/news/", "key2", body, &created)
+	assert.Equal(t, result.Status, http.StatusCreated)
+	result, _ = post("/api/v1/news/", "key2", body, &replayed)
+	assert.Equal(t, result.Status, http.StatusCreated)
+	assert.Equal(t, replayed.ID, created.ID)
+	var all entities.SliceNewsDto
+	call(t, fiberApp, http.MethodGet, "/api/v1/news/", "", &all)
+	assert.Equal(t, len(all), 1)
+
+	// errors are replayed too, but not the ones of the server
+	result, _ = post("/api/v1/news/", "key3", `{"title": "no content"}`, nil)
+	assert.Equal(t, result.Status, http.StatusBadRequest)
+	result, res = post("/api/v1/news/", "key3", `{"title": "no content"}`, nil)
+	assert.Equal(t, result.Status, http.StatusBadRequest)
+	assert.Equal(t, res.Header.Get("Idempotent-Replayed"), "true")
+
+	result, _ = post("/api/v1/tag/", strings.Repeat("k", 256), `{"name": "golf"}`, nil)
+	assert.Equal(t, result.Status, http.StatusBadRequest)
+}
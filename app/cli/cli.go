@@ -0,0 +1,157 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"news/configs"
+	"news/domain/news"
+	"news/migrations"
+	"os"
+	"time"
+)
+
+const usage = `usage: news [--config file] [--<key> value ...] [command]
+  config print
+  news export --format jsonl|csv [--output file]
+  news import --format jsonl|csv [--input file] [--dry-run]
+  migrate up
+  migrate down [--steps n]
+  migrate status`
+
+// Run executes a command line subcommand, args are os.Args without the program name.
+func Run(args []string, transfer news.Transfer, migrator *migrations.Migrator) error {
+	if len(args) < 2 {
+		return fmt.Errorf(usage)
+	}
+	switch args[0] + " " + args[1] {
+	case "news export":
+		return export(args[2:], transfer)
+	case "news import":
+		return importNews(args[2:], transfer)
+	case "migrate up", "migrate down", "migrate status":
+		if migrator == nil {
+			return fmt.Errorf("migrations need a database, DB.DRIVER is memory")
+		}
+	}
+	switch args[0] + " " + args[1] {
+	case "migrate up":
+		return migrateUp(migrator)
+	case "migrate down":
+		return migrateDown(args[2:], migrator)
+	case "migrate status":
+		return migrateStatus(migrator)
+	}
+	return fmt.Errorf(usage)
+}
+
+// RunConfig executes the config subcommands, they need no database.
+func RunConfig(args []string, configuration configs.Config) error {
+	if len(args) < 2 || args[1] != "print" {
+		return fmt.Errorf(usage)
+	}
+	err := configs.Write(os.Stdout, configuration)
+	if err != nil {
+		return err
+	}
+	return configuration.Validate()
+}
+
+func export(args []string, transfer news.Transfer) (err error) {
+	flags := flag.NewFlagSet("news export", flag.ContinueOnError)
+	format := flags.String("format", news.FormatJSONL, "file format, jsonl or csv")
+	output := flags.String("output", "", "output file, stdout when empty")
+	err = flags.Parse(args)
+	if err != nil {
+		return
+	}
+
+	var w io.Writer = os.Stdout
+	if *output != "" {
+		file, errs := os.Create(*output)
+		if errs != nil {
+			return errs
+		}
+		defer file.Close()
+		w = file
+	}
+	return transfer.Export(context.Background(), *format, w)
+}
+
+func importNews(args []string, transfer news.Transfer) (err error) {
+	flags := flag.NewFlagSet("news import", flag.ContinueOnError)
+	format := flags.String("format", news.FormatJSONL, "file format, jsonl or csv")
+	input := flags.String("input", "", "input file, stdin when empty")
+	dryRun := flags.Bool("dry-run", false, "validate without writing")
+	err = flags.Parse(args)
+	if err != nil {
+		return
+	}
+
+	var r io.Reader = os.Stdin
+	if *input != "" {
+		file, errs := os.Open(*input)
+		if errs != nil {
+			return errs
+		}
+		defer file.Close()
+		r = file
+	}
+	report, err := transfer.Import(context.Background(), *format, r, *dryRun)
+	if err != nil {
+		return
+	}
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(report)
+	if err != nil {
+		return
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d rows failed", report.Failed, report.Total)
+	}
+	return
+}
+
+func migrateUp(migrator *migrations.Migrator) error {
+	done, err := migrator.Up(context.Background())
+	for _, migration := range done {
+		fmt.Printf("applied %04d_%s\n", migration.Version, migration.Name)
+	}
+	if err == nil && len(done) == 0 {
+		fmt.Println("no pending migration")
+	}
+	return err
+}
+
+func migrateDown(args []string, migrator *migrations.Migrator) (err error) {
+	flags := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+	steps := flags.Int("steps", 1, "number of migrations to revert")
+	err = flags.Parse(args)
+	if err != nil {
+		return
+	}
+
+	done, err := migrator.Down(context.Background(), *steps)
+	for _, migration := range done {
+		fmt.Printf("reverted %04d_%s\n", migration.Version, migration.Name)
+	}
+	return
+}
+
+func migrateStatus(migrator *migrations.Migrator) error {
+	statuses, err := migrator.Status(context.Background())
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		appliedAt := "pending"
+		if status.AppliedAt != nil {
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Printf("%04d_%-30s %s\n", status.Version, status.Name, appliedAt)
+	}
+	return nil
+}
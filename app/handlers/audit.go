@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"news/domain/audit"
+	"news/domain/entities"
+	"news/shared/failure"
+)
+
+// AnonymousActor is the actor of the requests Authenticate didn't verify.
+const AnonymousActor = "anonymous"
+
+// AuditMetadata stores who made the request for the audit log, the actor
+// Authenticate verified. It runs after RequestID and Authenticate.
+func AuditMetadata() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		actor := ActorFrom(c)
+		if actor == "" {
+			actor = AnonymousActor
+		}
+		c.Locals(audit.MetadataKey, audit.Metadata{Actor: actor, IP: c.IP(), RequestID: RequestIDFrom(c)})
+		return c.Next()
+	}
+}
+
+func GetAuditEntries(service audit.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var query entities.AuditQuery
+		err := c.QueryParser(&query)
+		if err != nil {
+			return ErrorResponse(c, failure.BadRequestWithString("bad request"))
+		}
+
+		filter, err := query.ToFilter()
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+
+		result, err := service.GetEntries(c.Context(), filter)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		return SuccessResponse(c, http.StatusOK, result)
+	}
+}
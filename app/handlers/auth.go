@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"news/shared/failure"
+	"strings"
+)
+
+const (
+	HeaderAPIKey = "X-API-Key"
+
+	// LocalsActor is the c.Locals key of the actor Authenticate verified.
+	LocalsActor = "actor"
+)
+
+var (
+	errAPIKeyNotValid = failure.New(http.StatusUnauthorized, "auth.api_key_not_valid", "X-API-Key not valid")
+	errAuthRequired   = failure.New(http.StatusUnauthorized, "auth.required", "X-API-Key required")
+)
+
+// APIKeys maps the SHA-256 of every API key to the actor it authenticates, the
+// keys themselves aren't kept.
+type APIKeys map[string]string
+
+// ParseAPIKeys reads keys given as "actor:key".
+func ParseAPIKeys(pairs []string) (APIKeys, error) {
+	keys := APIKeys{}
+	for _, pair := range pairs {
+		actor, key, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || actor == "" || key == "" {
+			// the pair may be a key alone, it is not printed
+			return nil, errors.New("API keys must be actor:key pairs")
+		}
+		hash := hashAPIKey(key)
+		if _, ok := keys[hash]; ok {
+			return nil, fmt.Errorf("API key of %q given twice", actor)
+		}
+		keys[hash] = actor
+	}
+	return keys, nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// Authenticate verifies the X-API-Key of the request and stores its actor, a
+// request without one goes on unauthenticated and a wrong one gets a 401.
+func Authenticate(keys APIKeys) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(HeaderAPIKey)
+		if key == "" {
+			return c.Next()
+		}
+		// the lookup is on the hash, its time tells nothing about the key
+		actor, ok := keys[hashAPIKey(key)]
+		if !ok {
+			return ErrorResponse(c, errAPIKeyNotValid)
+		}
+		c.Locals(LocalsActor, actor)
+		return c.Next()
+	}
+}
+
+// RequireAuth answers 401 to the requests Authenticate didn't verify.
+func RequireAuth() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if ActorFrom(c) == "" {
+			return ErrorResponse(c, errAuthRequired)
+		}
+		return c.Next()
+	}
+}
+
+// ActorFrom returns the actor Authenticate verified, empty for unauthenticated requests.
+func ActorFrom(c *fiber.Ctx) string {
+	actor, _ := c.Locals(LocalsActor).(string)
+	return actor
+}
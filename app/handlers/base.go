@@ -1,10 +1,30 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/gofiber/fiber/v2"
+	"net/http"
 	"news/shared/failure"
 )
 
+// ProblemContentType is the media type of error responses, RFC 7807 problem details.
+const ProblemContentType = "application/problem+json"
+
+// Problem is the body of an error response. Code is stable and meant for
+// clients to match on, Detail is for humans and may change.
+type Problem struct {
+	Type      string              `json:"type"`
+	Title     string              `json:"title"`
+	Status    int                 `json:"status"`
+	Detail    string              `json:"detail,omitempty"`
+	Instance  string              `json:"instance,omitempty"`
+	Code      string              `json:"code"`
+	Errors    []failure.Violation `json:"errors,omitempty"`
+	RequestID string              `json:"request_id,omitempty"`
+	// Data is the current representation of what a request conflicted with.
+	Data interface{} `json:"data,omitempty"`
+}
+
 var SuccessResponse = func(ctx *fiber.Ctx, status int, data interface{}) error {
 	return ctx.Status(status).JSON(&fiber.Map{
 		"status": status,
@@ -13,9 +33,79 @@ var SuccessResponse = func(ctx *fiber.Ctx, status int, data interface{}) error {
 }
 
 var ErrorResponse = func(ctx *fiber.Ctx, err error) error {
-	code := failure.GetCode(err)
-	return ctx.Status(code).JSON(fiber.Map{
-		"code":  code,
-		"error": err.Error(),
-	})
+	return problemResponse(ctx, newProblem(ctx, err))
+}
+
+var (
+	errRouteNotFound    = failure.New(http.StatusNotFound, "route.not_found", "route not found")
+	errRequestMalformed = failure.New(http.StatusBadRequest, "request.malformed", "request body not valid")
+)
+
+// ErrorHandler answers the errors fiber raises itself with problem details too:
+// a path without a route is route.not_found, a path without a route for the
+// method keeps its 405 method_not_allowed.
+func ErrorHandler(ctx *fiber.Ctx, err error) error {
+	var fiberErr *fiber.Error
+	if errors.As(err, &fiberErr) && fiberErr.Code == http.StatusNotFound {
+		err = errRouteNotFound
+	}
+	return ErrorResponse(ctx, err)
+}
+
+// Unmatched is registered after every route, the requests reaching it matched
+// none. fiber still answers them, 405 for a path with routes for other methods,
+// but writes its 404 without an error: it is raised as fiber.ErrNotFound so
+// ErrorHandler answers it.
+func Unmatched() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+		if err == nil && c.Response().StatusCode() == http.StatusNotFound {
+			return fiber.ErrNotFound
+		}
+		return err
+	}
+}
+
+// parseBody reads the request body into out, a body that can't be read, or in
+// a content type fiber doesn't parse, is request.malformed.
+func parseBody(c *fiber.Ctx, out interface{}) error {
+	err := c.BodyParser(out)
+	if err != nil {
+		return errRequestMalformed.Wrap(err)
+	}
+	return nil
+}
+
+// asFailure is failure.As knowing the errors fiber returns, they keep their status.
+func asFailure(err error) *failure.CustomError {
+	var custom *failure.CustomError
+	var fiberErr *fiber.Error
+	if !errors.As(err, &custom) && errors.As(err, &fiberErr) {
+		err = failure.Error(fiberErr, fiberErr.Code)
+	}
+	return failure.As(err)
+}
+
+// newProblem describes err, the cause of an error is never shown.
+func newProblem(ctx *fiber.Ctx, err error) Problem {
+	custom := asFailure(err)
+	return Problem{
+		Type:      "about:blank",
+		Title:     http.StatusText(custom.Status),
+		Status:    custom.Status,
+		Detail:    custom.Message,
+		Instance:  ctx.OriginalURL(),
+		Code:      custom.Code,
+		Errors:    custom.Violations,
+		RequestID: RequestIDFrom(ctx),
+	}
+}
+
+func problemResponse(ctx *fiber.Ctx, problem Problem) error {
+	err := ctx.Status(problem.Status).JSON(problem)
+	if err != nil {
+		return err
+	}
+	ctx.Set(fiber.HeaderContentType, ProblemContentType)
+	return nil
 }
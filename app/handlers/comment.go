@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"news/domain/comment"
+	"news/domain/entities"
+)
+
+func AddComment(service comment.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var requestBody entities.CreateComment
+		err := parseBody(c, &requestBody)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+
+		err = requestBody.Validate()
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+
+		lang := c.Query("lang")
+		err = entities.ValidateLanguage(lang)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+
+		result, err := service.Create(c.Context(), c.Params("slug"), lang, &requestBody)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		return SuccessResponse(c, http.StatusCreated, result)
+	}
+}
+
+func GetCommentsBySlug(service comment.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		lang := c.Query("lang")
+		err := entities.ValidateLanguage(lang)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+
+		result, err := service.GetBySlug(c.Context(), c.Params("slug"), lang)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		return SuccessResponse(c, http.StatusOK, result)
+	}
+}
+
+func GetModerationQueue(service comment.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		result, err := service.GetModerationQueue(c.Context())
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		return SuccessResponse(c, http.StatusOK, result)
+	}
+}
+
+func ModerateComment(service comment.Service, status entities.CommentStatus) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		result, err := service.Moderate(c.Context(), c.Params("id"), status)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		return SuccessResponse(c, http.StatusOK, result)
+	}
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"news/shared/failure"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CachePolicies are the Cache-Control headers of the cacheable reads, an empty
+// policy sends no header.
+type CachePolicies struct {
+	News     string
+	NewsList string
+	Tags     string
+}
+
+// CacheControl sets policy on the successful and not modified responses of the route.
+func CacheControl(policy string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := c.Next()
+		if policy != "" && c.Response().StatusCode() < http.StatusBadRequest {
+			c.Set(fiber.HeaderCacheControl, policy)
+		}
+		return err
+	}
+}
+
+// ConditionalResponse answers a read with data, or with 304 Not Modified when
+// the request already holds it. The strong ETag is made of version and a hash
+// of data, Last-Modified is lastModified, left out when zero.
+func ConditionalResponse(c *fiber.Ctx, data interface{}, version int, lastModified time.Time) error {
+	tag, err := ETag(data, version)
+	if err != nil {
+		return ErrorResponse(c, failure.InternalServerError)
+	}
+	c.Set(fiber.HeaderETag, tag)
+	if !lastModified.IsZero() {
+		c.Set(fiber.HeaderLastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+	if notModified(c, tag, lastModified) {
+		c.Status(http.StatusNotModified)
+		return nil
+	}
+	return SuccessResponse(c, http.StatusOK, data)
+}
+
+// listResponse is ConditionalResponse for lists, they only carry the ETag: the
+// newest update of the items they hold doesn't move when an item leaves the
+// list, so a Last-Modified would answer 304 to a stale copy.
+func listResponse(c *fiber.Ctx, data interface{}) error {
+	return ConditionalResponse(c, data, 0, time.Time{})
+}
+
+// ETag returns the strong entity tag of the JSON encoding of data. The tag of
+// a single resource starts with its version, "v<version>-", so clients can
+// send it back in If-Match, lists have a version of 0 and no prefix.
+func ETag(data interface{}, version int) (string, error) {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(body)
+	tag := hex.EncodeToString(sum[:16])
+	if version > 0 {
+		tag = "v" + strconv.Itoa(version) + "-" + tag
+	}
+	return `"` + tag + `"`, nil
+}
+
+var errIfMatchNotValid = failure.New(http.StatusBadRequest, "if_match.not_valid", "If-Match not valid")
+
+// ExpectedVersion returns the version an update expects to replace: the one of
+// the If-Match entity tag when the header is sent, bodyVersion otherwise. 0,
+// like If-Match: *, replaces any version.
+func ExpectedVersion(c *fiber.Ctx, bodyVersion int) (int, error) {
+	match := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if match == "" {
+		return bodyVersion, nil
+	}
+	if match == "*" {
+		return 0, nil
+	}
+	// a version is only in the strong tags ETag returns
+	if len(match) < 4 || !strings.HasPrefix(match, `"v`) || !strings.HasSuffix(match, `"`) {
+		return 0, errIfMatchNotValid
+	}
+	tag := match[2 : len(match)-1]
+	end := strings.IndexByte(tag, '-')
+	if end < 1 {
+		return 0, errIfMatchNotValid
+	}
+	version, err := strconv.Atoi(tag[:end])
+	if err != nil || version < 1 {
+		return 0, errIfMatchNotValid
+	}
+	return version, nil
+}
+
+// ConflictResponse answers an update that lost a version check with 409
+// Conflict, the current representation in data and its ETag, so the client can
+// retry on top of it. current is only left out when it can't be read.
+func ConflictResponse(c *fiber.Ctx, err error, current interface{}, version int) error {
+	if current != nil {
+		tag, errs := ETag(current, version)
+		if errs == nil {
+			c.Set(fiber.HeaderETag, tag)
+		}
+	}
+	problem := newProblem(c, err)
+	problem.Data = current
+	return problemResponse(c, problem)
+}
+
+// notModified evaluates If-None-Match, or If-Modified-Since when it is absent,
+// like RFC 9110 section 13.2.2 orders them.
+func notModified(c *fiber.Ctx, tag string, lastModified time.Time) bool {
+	if noneMatch := c.Get(fiber.HeaderIfNoneMatch); noneMatch != "" {
+		return matchETag(noneMatch, tag, true)
+	}
+	modifiedSince := c.Get(fiber.HeaderIfModifiedSince)
+	if modifiedSince == "" || lastModified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(modifiedSince)
+	if err != nil {
+		return false
+	}
+	// the header only has a precision of one second
+	return !lastModified.Truncate(time.Second).After(since)
+}
+
+// matchETag reports whether the comma separated list of entity tags in header
+// holds tag, or is *. Weak comparison ignores the W/ prefix of weak tags.
+func matchETag(header string, tag string, weak bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		}
+		if candidate == tag {
+			return true
+		}
+	}
+	return false
+}
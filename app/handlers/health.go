@@ -0,0 +1,33 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"news/shared/health"
+	"news/shared/logger"
+)
+
+// Healthz tells the process is alive, it checks no dependency.
+func Healthz() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.Status(http.StatusOK).JSON(&fiber.Map{"status": health.StatusUp})
+	}
+}
+
+// Readyz reports the status of every dependency, with 503 when a required one is
+// down. The probe is public, why a dependency is down is only logged.
+func Readyz(checker *health.Checker) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		report := checker.Check(c.Context())
+		for name, result := range report.Checks {
+			if result.Status != health.StatusUp {
+				logger.Ctx(c.Context()).Warn().Str("check", name).Str("error", result.Error).Msg("dependency down")
+			}
+		}
+		status := http.StatusOK
+		if !report.Ready() {
+			status = http.StatusServiceUnavailable
+		}
+		return c.Status(status).JSON(report)
+	}
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"news/domain/audit"
+	"news/shared/IDGEN"
+	"news/shared/failure"
+	"news/shared/idempotency"
+	"news/shared/logger"
+	"time"
+)
+
+const (
+	HeaderIdempotencyKey     = "Idempotency-Key"
+	HeaderIdempotentReplayed = "Idempotent-Replayed"
+
+	maxIdempotencyKey = 255
+	// idempotencyLock is how long a request holds its key, a request still
+	// running after it is taken for lost and its key can be used again.
+	idempotencyLock = time.Minute
+)
+
+var (
+	errIdempotencyKeyNotValid = failure.New(http.StatusBadRequest, "idempotency.key_not_valid",
+		"Idempotency-Key not valid")
+	errIdempotencyKeyReused = failure.New(http.StatusUnprocessableEntity, "idempotency.key_reused",
+		"Idempotency-Key was used with another request")
+	errIdempotencyInProgress = failure.New(http.StatusConflict, "idempotency.in_progress",
+		"a request with this Idempotency-Key is in progress")
+)
+
+// IdempotencyConfig keeps the responses of the requests sent with an
+// Idempotency-Key for TTL, a nil Store turns idempotency off.
+type IdempotencyConfig struct {
+	Store idempotency.Store
+	TTL   time.Duration
+}
+
+// Idempotent answers a retry, a request with the Idempotency-Key and the
+// payload of an earlier request of the same actor, with the response of the
+// earlier request. A key sent with another payload answers 422, and a retry
+// while the first request runs 409. Responses from 500 are not kept, the
+// request runs again on retry. Requests without key always run.
+func Idempotent(config IdempotencyConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(HeaderIdempotencyKey)
+		if config.Store == nil || key == "" {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKey {
+			return ErrorResponse(c, errIdempotencyKeyNotValid)
+		}
+		key = idempotencyScope(c) + ":" + key
+		fingerprint, token := requestFingerprint(c), IDGEN.NewUUID()
+
+		record, err := config.Store.Claim(c.Context(), key, fingerprint, token, idempotencyLock)
+		if err != nil {
+			// a broken store must not take the API down
+			logger.ErrorWithStack(c.Context(), err)
+			return c.Next()
+		}
+		if record != nil {
+			if record.Fingerprint != fingerprint {
+				return ErrorResponse(c, errIdempotencyKeyReused)
+			}
+			if !record.Done() {
+				return ErrorResponse(c, errIdempotencyInProgress)
+			}
+			c.Set(HeaderIdempotentReplayed, "true")
+			c.Set(fiber.HeaderContentType, record.ContentType)
+			return c.Status(record.Status).Send(record.Body)
+		}
+
+		err = c.Next()
+		status := c.Response().StatusCode()
+		if err != nil || status >= http.StatusInternalServerError {
+			errs := config.Store.Release(c.Context(), key, token)
+			if errs != nil {
+				logger.ErrorWithStack(c.Context(), errs)
+			}
+			return err
+		}
+		err = config.Store.Save(c.Context(), key, idempotency.Record{
+			Fingerprint: fingerprint,
+			Status:      status,
+			ContentType: string(c.Response().Header.ContentType()),
+			Body:        append([]byte(nil), c.Response().Body()...),
+		}, config.TTL)
+		if err != nil {
+			logger.ErrorWithStack(c.Context(), err)
+		}
+		return nil
+	}
+}
+
+// idempotencyScope keeps the keys of every actor apart.
+func idempotencyScope(c *fiber.Ctx) string {
+	if metadata, ok := c.Locals(audit.MetadataKey).(audit.Metadata); ok {
+		return metadata.Actor
+	}
+	return AnonymousActor
+}
+
+// requestFingerprint hashes the method, the url and the body of the request.
+func requestFingerprint(c *fiber.Ctx) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.Method() + " " + c.OriginalURL() + "\n"))
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"news/domain/media"
+	"news/shared/failure"
+)
+
+func UploadMedia(service media.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return ErrorResponse(c, failure.BadRequestWithString("file can't be null"))
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return ErrorResponse(c, failure.BadRequestWithString("bad request"))
+		}
+		defer file.Close()
+
+		result, err := service.Upload(c.Context(), fileHeader.Filename, file)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		return SuccessResponse(c, http.StatusCreated, result)
+	}
+}
+
+func GetMedia(service media.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		content, result, err := service.Open(c.Context(), c.Params("id"), c.Params("size"))
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		// media ids are content hashes, the file behind an url never changes
+		c.Set(fiber.HeaderContentType, result.MimeType)
+		c.Set(fiber.HeaderCacheControl, "public, max-age=31536000, immutable")
+		return c.SendStream(content)
+	}
+}
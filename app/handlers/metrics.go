@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
+	"news/shared/metrics"
+	"strconv"
+	"time"
+)
+
+// unmatchedRoute labels the requests no route matched, their paths are unbounded.
+const unmatchedRoute = "unmatched"
+
+// Metrics observes the duration of every request by route pattern and status.
+func Metrics() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+		if err != nil {
+			if handlerErr := c.App().Config().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+		// label values are kept, the method must not point into the buffer fiber reuses
+		metrics.HTTPRequests.
+			WithLabelValues(utils.CopyString(c.Method()), route(c), strconv.Itoa(c.Response().StatusCode())).
+			Observe(time.Since(start).Seconds())
+		return nil
+	}
+}
+
+// route is the pattern of the route that answered, middlewares registered with
+// Use have the "/" pattern whatever the path.
+func route(c *fiber.Ctx) string {
+	path := c.Route().Path
+	if path == "/" && c.Path() != "/" {
+		return unmatchedRoute
+	}
+	return path
+}
+
+// GetMetrics serves the metrics in the Prometheus text format.
+func GetMetrics() fiber.Handler {
+	handler := fasthttpadaptor.NewFastHTTPHandler(promhttp.HandlerFor(metrics.Registry, promhttp.HandlerOpts{}))
+	return func(c *fiber.Ctx) error {
+		handler(c.Context())
+		return nil
+	}
+}
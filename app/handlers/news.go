@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/gofiber/fiber/v2"
 	"github.com/rs/zerolog/log"
 	"net/http"
@@ -8,17 +9,19 @@ import (
 	"news/domain/entities"
 	"news/domain/news"
 	"news/shared/failure"
+	"news/shared/patch"
+	"strings"
 )
 
 func AddNews(service news.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var requestBody entities.NewsDto
-		err := c.BodyParser(&requestBody)
+		err := parseBody(c, &requestBody)
 		if err != nil {
 			log.Trace().Err(err)
 			return ErrorResponse(c, err)
 		}
-		err = requestBody.Validate()
+		err = requestBody.ValidateCreate()
 		if err != nil {
 			log.Trace().Err(err)
 			return ErrorResponse(c, err)
@@ -33,11 +36,34 @@ func AddNews(service news.Service) fiber.Handler {
 
 func GetNewsBySlug(service news.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		result, err := service.GetBySlug(c.Context(), c.Params("slug"))
+		lang := c.Query("lang")
+		err := entities.ValidateLanguage(lang)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		result, err := service.GetBySlug(c.Context(), c.Params("slug"), lang)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		return ConditionalResponse(c, result, result.Version, result.UpdatedAt)
+	}
+}
+
+func SearchNews(service news.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		lang := c.Query("lang")
+		err := entities.ValidateLanguage(lang)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		result, err := service.Search(c.Context(), c.Query("q"), lang)
 		if err != nil {
 			return ErrorResponse(c, err)
 		}
-		return SuccessResponse(c, http.StatusOK, result)
+		if !withContent(c) {
+			result = result.WithoutContent()
+		}
+		return listResponse(c, result)
 	}
 }
 
@@ -47,11 +73,19 @@ func GetNewsByTopic(service news.Service) fiber.Handler {
 		if err != nil {
 			return ErrorResponse(c, failure.BadRequestWithString("bad request"))
 		}
-		result, err := service.GetByTopic(c.Context(), topic)
+		lang := c.Query("lang")
+		err = entities.ValidateLanguage(lang)
 		if err != nil {
 			return ErrorResponse(c, err)
 		}
-		return SuccessResponse(c, http.StatusOK, result)
+		result, err := service.GetByTopic(c.Context(), topic, lang)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		if !withContent(c) {
+			result = result.WithoutContent()
+		}
+		return listResponse(c, result)
 	}
 }
 
@@ -61,28 +95,62 @@ func GetNewsByStatus(service news.Service) fiber.Handler {
 		if err != nil {
 			return ErrorResponse(c, failure.BadRequestWithString("bad request"))
 		}
-		result, err := service.GetByStatus(c.Context(), status)
+		lang := c.Query("lang")
+		err = entities.ValidateLanguage(lang)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		result, err := service.GetByStatus(c.Context(), status, lang)
 		if err != nil {
 			return ErrorResponse(c, err)
 		}
-		return SuccessResponse(c, http.StatusOK, result)
+		if !withContent(c) {
+			result = result.WithoutContent()
+		}
+		return listResponse(c, result)
 	}
 }
 
 func GetAllNews(service news.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		result, err := service.GetAll(c.Context())
+		lang := c.Query("lang")
+		err := entities.ValidateLanguage(lang)
 		if err != nil {
 			return ErrorResponse(c, err)
 		}
-		return SuccessResponse(c, http.StatusOK, result)
+		result, err := service.GetAll(c.Context(), lang)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		if !withContent(c) {
+			result = result.WithoutContent()
+		}
+		return listResponse(c, result)
+	}
+}
+
+func GetNewsTranslations(service news.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		lang := c.Query("lang")
+		err := entities.ValidateLanguage(lang)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		result, err := service.GetTranslations(c.Context(), c.Params("slug"), lang)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		if !withContent(c) {
+			result = result.WithoutContent()
+		}
+		return listResponse(c, result)
 	}
 }
 
 func UpdateNews(service news.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var requestBody entities.NewsDto
-		err := c.BodyParser(&requestBody)
+		err := parseBody(c, &requestBody)
 		if err != nil {
 			log.Trace().Err(err)
 			return ErrorResponse(c, err)
@@ -92,11 +160,36 @@ func UpdateNews(service news.Service) fiber.Handler {
 			log.Trace().Err(err)
 			return ErrorResponse(c, err)
 		}
+		requestBody.Version, err = ExpectedVersion(c, requestBody.Version)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
 		requestBody.ID = c.Params("id")
 		err = service.Update(c.Context(), &requestBody)
+		if err != nil {
+			return updateError(c, service, requestBody.ID, err)
+		}
+		return SuccessResponse(c, http.StatusOK, &fiber.Map{"message": "updated"})
+	}
+}
+
+// PatchNews applies a merge patch or a JSON patch, picked by Content-Type, to the
+// news. The patched news is validated, not the patch.
+func PatchNews(service news.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		p, err := patch.Parse(c.Get(fiber.HeaderContentType), c.Body())
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		version, err := ExpectedVersion(c, 0)
 		if err != nil {
 			return ErrorResponse(c, err)
 		}
+		id := c.Params("id")
+		err = service.Patch(c.Context(), id, version, p)
+		if err != nil {
+			return updateError(c, service, id, err)
+		}
 		return SuccessResponse(c, http.StatusOK, &fiber.Map{"message": "updated"})
 	}
 }
@@ -110,3 +203,26 @@ func DeleteNews(service news.Service) fiber.Handler {
 		return SuccessResponse(c, http.StatusOK, &fiber.Map{"message": "news has been deleted"})
 	}
 }
+
+// updateError answers a failed update of the news id, with the current news
+// when another write came first.
+func updateError(c *fiber.Ctx, service news.Service, id string, err error) error {
+	if !errors.Is(err, news.ErrVersionConflict) {
+		return ErrorResponse(c, err)
+	}
+	current, errs := service.GetByID(c.Context(), id)
+	if errs != nil {
+		return ConflictResponse(c, err, nil, 0)
+	}
+	return ConflictResponse(c, err, current, current.Version)
+}
+
+// withContent reports whether a list request opted in to full content with ?fields=content.
+func withContent(c *fiber.Ctx) bool {
+	for _, field := range strings.Split(c.Query("fields"), ",") {
+		if strings.TrimSpace(field) == "content" {
+			return true
+		}
+	}
+	return false
+}
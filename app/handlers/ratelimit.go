@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"math"
+	"news/shared/failure"
+	"news/shared/logger"
+	"news/shared/ratelimit"
+	"strconv"
+	"time"
+)
+
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+
+	// RateLimitByIP, RateLimitByAPIKey and RateLimitByUser pick whose budget a request uses,
+	// requests without a valid API key fall back to their IP.
+	RateLimitByIP     = "ip"
+	RateLimitByAPIKey = "api_key"
+	RateLimitByUser   = "user"
+)
+
+// RateLimitConfig gives every client a budget for reads (GET, HEAD and OPTIONS)
+// and one for writes, a nil Limiter turns rate limiting off. Keys are the API
+// keys Authenticate accepts, only their callers are told apart by key or user.
+type RateLimitConfig struct {
+	Limiter ratelimit.Limiter
+	KeyBy   string
+	Keys    APIKeys
+	Read    ratelimit.Budget
+	Write   ratelimit.Budget
+}
+
+// RateLimit answers 429 once the client used its budget. It runs before
+// Authenticate, so the guesses of an API key use the budget of their IP.
+func RateLimit(config RateLimitConfig) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if config.Limiter == nil {
+			return c.Next()
+		}
+		budget, kind := config.Write, "write"
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			budget, kind = config.Read, "read"
+		}
+		if budget.Limit <= 0 || budget.Window <= 0 {
+			return c.Next()
+		}
+
+		result, err := config.Limiter.Allow(c.Context(), kind+":"+rateLimitKey(c, config), budget)
+		if err != nil {
+			// a broken limiter must not take the API down
+			logger.ErrorWithStack(c.Context(), err)
+			return c.Next()
+		}
+		c.Set(HeaderRateLimitLimit, strconv.Itoa(result.Limit))
+		c.Set(HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+		c.Set(HeaderRateLimitReset, seconds(result.Reset))
+		if !result.Allowed {
+			c.Set(fiber.HeaderRetryAfter, seconds(result.RetryAfter))
+			return ErrorResponse(c, failure.TooManyRequests("too many requests"))
+		}
+		return c.Next()
+	}
+}
+
+// rateLimitKey only trusts a key it verified, a client sending made up keys
+// would otherwise get a new budget with every one.
+func rateLimitKey(c *fiber.Ctx, config RateLimitConfig) string {
+	if key := c.Get(HeaderAPIKey); key != "" && config.KeyBy != RateLimitByIP {
+		// keys are secrets, the limiter only sees their hash
+		hash := hashAPIKey(key)
+		if actor, ok := config.Keys[hash]; ok {
+			if config.KeyBy == RateLimitByUser {
+				return "user:" + actor
+			}
+			return "key:" + hash
+		}
+	}
+	return "ip:" + c.IP()
+}
+
+// seconds rounds up, a client waiting the announced time is never early.
+func seconds(duration time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(duration.Seconds())))
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"news/shared/IDGEN"
+	"news/shared/logger"
+	"time"
+)
+
+const (
+	HeaderRequestID = "X-Request-ID"
+
+	// LocalsRequestID is the c.Locals key of the request id.
+	LocalsRequestID = "request_id"
+	// maxRequestID is the size of the request_id column, longer ids are replaced.
+	maxRequestID = 64
+)
+
+// RequestID keeps the X-Request-ID of the caller or generates one, sends it back
+// and gives the request a logger that adds it to every line. It runs first.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := c.Get(HeaderRequestID)
+		if requestID == "" || len(requestID) > maxRequestID {
+			requestID = IDGEN.NewUUID()
+		} else {
+			// fiber reuses the header buffer for the next request
+			requestID = utils.CopyString(requestID)
+		}
+		c.Set(HeaderRequestID, requestID)
+		c.Locals(LocalsRequestID, requestID)
+		requestLogger := log.Logger.With().Str("request_id", requestID).Logger()
+		c.Locals(logger.ContextKey, &requestLogger)
+		return c.Next()
+	}
+}
+
+// RequestIDFrom returns the id RequestID gave the request.
+func RequestIDFrom(c *fiber.Ctx) string {
+	requestID, _ := c.Locals(LocalsRequestID).(string)
+	return requestID
+}
+
+// AccessLog writes one line per request once the response is ready, with the
+// fields the request added to its logger like the cache hit or miss.
+func AccessLog() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		err := c.Next()
+		if err != nil {
+			// the response is built by the error handler, log what the client gets
+			if handlerErr := c.App().Config().ErrorHandler(c, err); handlerErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+		status := c.Response().StatusCode()
+		var event *zerolog.Event
+		switch {
+		case status >= fiber.StatusInternalServerError:
+			event = logger.Ctx(c.Context()).Error()
+		case status >= fiber.StatusBadRequest:
+			event = logger.Ctx(c.Context()).Warn()
+		default:
+			event = logger.Ctx(c.Context()).Info()
+		}
+		event.
+			Str("method", c.Method()).
+			Str("path", c.Path()).
+			Str("route", route(c)).
+			Int("status", status).
+			Float64("latency_ms", float64(time.Since(start).Microseconds())/1000).
+			Int("bytes", len(c.Response().Body())).
+			Str("ip", c.IP()).
+			Msg("request")
+		return nil
+	}
+}
@@ -1,19 +1,19 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/gofiber/fiber/v2"
 	"net/http"
 	"news/domain/entities"
 	"news/domain/tag"
-	"news/shared/failure"
 )
 
 func AddTag(service tag.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var requestBody entities.CreateTag
-		err := c.BodyParser(&requestBody)
+		err := parseBody(c, &requestBody)
 		if err != nil {
-			return ErrorResponse(c, failure.BadRequestWithString("bad request"))
+			return ErrorResponse(c, err)
 		}
 
 		err = requestBody.Validate()
@@ -35,16 +35,16 @@ func GetAllTag(service tag.Service) fiber.Handler {
 		if err != nil {
 			return ErrorResponse(c, err)
 		}
-		return SuccessResponse(c, http.StatusOK, result)
+		return listResponse(c, result)
 	}
 }
 
 func UpdateTag(service tag.Service) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var requestBody entities.TagDto
-		err := c.BodyParser(&requestBody)
+		err := parseBody(c, &requestBody)
 		if err != nil {
-			return ErrorResponse(c, failure.BadRequestWithString("bad request"))
+			return ErrorResponse(c, err)
 		}
 
 		err = requestBody.Validate()
@@ -52,8 +52,19 @@ func UpdateTag(service tag.Service) fiber.Handler {
 			return ErrorResponse(c, err)
 		}
 
+		requestBody.Version, err = ExpectedVersion(c, requestBody.Version)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
 		requestBody.ID = c.Params("id")
 		result, err := service.Update(c.Context(), &requestBody)
+		if errors.Is(err, tag.ErrVersionConflict) {
+			current, errs := service.GetByID(c.Context(), requestBody.ID)
+			if errs != nil {
+				return ConflictResponse(c, err, nil, 0)
+			}
+			return ConflictResponse(c, err, current, current.Version)
+		}
 		if err != nil {
 			return ErrorResponse(c, err)
 		}
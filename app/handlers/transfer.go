@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"news/domain/news"
+	"news/shared/logger"
+	"strconv"
+)
+
+var contentTypes = map[string]string{
+	news.FormatJSONL: "application/x-ndjson",
+	news.FormatCSV:   "text/csv; charset=utf-8",
+}
+
+func ExportNews(transfer news.Transfer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		format := c.Query("format", news.FormatJSONL)
+		err := news.ValidateFormat(format)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		c.Set(fiber.HeaderContentType, contentTypes[format])
+		c.Set(fiber.HeaderContentDisposition, `attachment; filename="news.`+format+`"`)
+		// the status is already sent once streaming starts, failures can only be logged.
+		// The writer runs after the handler returned, when c is reused, so it keeps the request logger only.
+		ctx := logger.WithContext(context.Background(), logger.Ctx(c.Context()))
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			err := transfer.Export(ctx, format, w)
+			if err != nil {
+				logger.ErrorWithStack(ctx, err)
+			}
+		})
+		return nil
+	}
+}
+
+func ImportNews(transfer news.Transfer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		format := c.Query("format", news.FormatJSONL)
+		report, err := transfer.Import(c.Context(), format, bytes.NewReader(c.Body()), dryRun(c))
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		return SuccessResponse(c, http.StatusOK, report)
+	}
+}
+
+func dryRun(c *fiber.Ctx) bool {
+	value, _ := strconv.ParseBool(c.Query("dry_run"))
+	return value
+}
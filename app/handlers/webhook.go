@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"news/domain/entities"
+	"news/domain/webhook"
+)
+
+func AddWebhook(service webhook.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var requestBody entities.CreateWebhook
+		err := parseBody(c, &requestBody)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+
+		err = requestBody.Validate()
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+
+		result, err := service.Create(c.Context(), &requestBody)
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		return SuccessResponse(c, http.StatusCreated, result)
+	}
+}
+
+func GetAllWebhook(service webhook.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		result, err := service.GetAll(c.Context())
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		return SuccessResponse(c, http.StatusOK, result)
+	}
+}
+
+func DeleteWebhook(service webhook.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		err := service.Delete(c.Context(), c.Params("id"))
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		return SuccessResponse(c, http.StatusOK, &fiber.Map{
+			"message": "success",
+		})
+	}
+}
+
+func GetWebhookDeliveries(service webhook.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		result, err := service.GetDeliveries(c.Context(), c.Params("id"))
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		return SuccessResponse(c, http.StatusOK, result)
+	}
+}
+
+func RedeliverWebhook(service webhook.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		result, err := service.Redeliver(c.Context(), c.Params("id"), c.Params("deliveryId"))
+		if err != nil {
+			return ErrorResponse(c, err)
+		}
+		return SuccessResponse(c, http.StatusOK, result)
+	}
+}
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"news/app/handlers"
+	"news/domain/news"
+)
+
+func AdminNewsRouter(app fiber.Router, transfer news.Transfer) {
+	app.Get("/export", handlers.ExportNews(transfer))
+	app.Post("/import", handlers.ImportNews(transfer))
+}
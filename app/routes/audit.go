@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"news/app/handlers"
+	"news/domain/audit"
+)
+
+func AuditRouter(app fiber.Router, service audit.Service) {
+	app.Get("/", handlers.GetAuditEntries(service))
+}
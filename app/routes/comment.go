@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"news/app/handlers"
+	"news/domain/comment"
+	"news/domain/entities"
+)
+
+func NewsCommentRouter(app fiber.Router, service comment.Service) {
+	app.Get("/", handlers.GetCommentsBySlug(service))
+	app.Post("/", handlers.AddComment(service))
+}
+
+func CommentRouter(app fiber.Router, service comment.Service) {
+	app.Get("/moderation", handlers.GetModerationQueue(service))
+	app.Post("/:id/approve", handlers.ModerateComment(service, entities.CommentApproved))
+	app.Post("/:id/reject", handlers.ModerateComment(service, entities.CommentRejected))
+	app.Post("/:id/spam", handlers.ModerateComment(service, entities.CommentSpam))
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"news/app/handlers"
+	"news/domain/media"
+)
+
+func MediaRouter(app fiber.Router, service media.Service) {
+	app.Post("/", handlers.UploadMedia(service))
+	app.Get("/:id", handlers.GetMedia(service))
+	app.Get("/:id/thumbnails/:size", handlers.GetMedia(service))
+}
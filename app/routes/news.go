@@ -6,12 +6,16 @@ import (
 	"news/domain/news"
 )
 
-func NewsRouter(app fiber.Router, service news.Service) {
-	app.Get("/", handlers.GetAllNews(service))
-	app.Get("/status/:status", handlers.GetNewsByStatus(service))
-	app.Get("/topic/:topic", handlers.GetNewsByTopic(service))
-	app.Get("/:slug", handlers.GetNewsBySlug(service))
-	app.Post("/", handlers.AddNews(service))
-	app.Patch("/:id", handlers.UpdateNews(service))
+func NewsRouter(app fiber.Router, service news.Service, cache handlers.CachePolicies,
+	idempotency handlers.IdempotencyConfig) {
+	app.Get("/", handlers.CacheControl(cache.NewsList), handlers.GetAllNews(service))
+	app.Get("/status/:status", handlers.CacheControl(cache.NewsList), handlers.GetNewsByStatus(service))
+	app.Get("/topic/:topic", handlers.CacheControl(cache.NewsList), handlers.GetNewsByTopic(service))
+	app.Get("/search", handlers.CacheControl(cache.NewsList), handlers.SearchNews(service))
+	app.Get("/:slug/translations", handlers.CacheControl(cache.NewsList), handlers.GetNewsTranslations(service))
+	app.Get("/:slug", handlers.CacheControl(cache.News), handlers.GetNewsBySlug(service))
+	app.Post("/", handlers.Idempotent(idempotency), handlers.AddNews(service))
+	app.Put("/:id", handlers.UpdateNews(service))
+	app.Patch("/:id", handlers.PatchNews(service))
 	app.Delete("/:id", handlers.DeleteNews(service))
 }
@@ -6,10 +6,11 @@ import (
 	"news/domain/tag"
 )
 
-func TagRouter(app fiber.Router, service tag.Service) {
-	app.Get("/", handlers.GetAllTag(service))
+func TagRouter(app fiber.Router, service tag.Service, cache handlers.CachePolicies,
+	idempotency handlers.IdempotencyConfig) {
+	app.Get("/", handlers.CacheControl(cache.Tags), handlers.GetAllTag(service))
 	//app.Get("/:like", handlers.GetAllTag(service))
-	app.Post("/", handlers.AddTag(service))
+	app.Post("/", handlers.Idempotent(idempotency), handlers.AddTag(service))
 	app.Put("/:id", handlers.UpdateTag(service))
 	app.Delete("/:id", handlers.DeleteTag(service))
 }
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"news/app/handlers"
+	"news/domain/webhook"
+)
+
+func AdminWebhookRouter(app fiber.Router, service webhook.Service) {
+	app.Get("/", handlers.GetAllWebhook(service))
+	app.Post("/", handlers.AddWebhook(service))
+	app.Delete("/:id", handlers.DeleteWebhook(service))
+	app.Get("/:id/deliveries", handlers.GetWebhookDeliveries(service))
+	app.Post("/:id/deliveries/:deliveryId/redeliver", handlers.RedeliverWebhook(service))
+}
@@ -1,14 +1,7 @@
 package configs
 
-import (
-	"sync"
-
-	"github.com/rs/zerolog/log"
-	"github.com/spf13/viper"
-)
-
-// Config is a struct that will receive configuration options via environment
-// variables.
+// Config is a struct that will receive configuration options, see Load for
+// where they come from.
 type Config struct {
 	// App struct {
 	// 	CORS struct {
@@ -24,6 +17,11 @@ type Config struct {
 	// 	URL      string `mapstructure:"URL"`
 	// }
 
+	Auth struct {
+		// APIKeys authenticate the callers of the admin routes, as actor:key.
+		APIKeys []string `mapstructure:"API_KEYS"`
+	}
+
 	Cache struct {
 		Redis struct {
 			Primary struct {
@@ -37,8 +35,16 @@ type Config struct {
 		}
 	}
 
+	Comment struct {
+		Moderation struct {
+			BlockedWords []string `mapstructure:"BLOCKED_WORDS"`
+			AutoApprove  bool     `mapstructure:"AUTO_APPROVE"`
+		}
+	}
+
 	DB struct {
-		MySQL struct {
+		Driver string `mapstructure:"DRIVER"`
+		MySQL  struct {
 			Host     string `mapstructure:"HOST"`
 			Port     string `mapstructure:"PORT"`
 			Username string `mapstructure:"USER"`
@@ -46,36 +52,100 @@ type Config struct {
 			Name     string `mapstructure:"NAME"`
 			Timezone string `mapstructure:"TIMEZONE"`
 		}
+		Postgres struct {
+			Host     string `mapstructure:"HOST"`
+			Port     string `mapstructure:"PORT"`
+			Username string `mapstructure:"USER"`
+			Password string `mapstructure:"PASSWORD"`
+			Name     string `mapstructure:"NAME"`
+			SSLMode  string `mapstructure:"SSL_MODE"`
+			Timezone string `mapstructure:"TIMEZONE"`
+		}
+		SQLite struct {
+			Path string `mapstructure:"PATH"`
+		}
+		MigrateOnStart bool `mapstructure:"MIGRATE_ON_START"`
 	}
 
-	Server struct {
-		Env      string `mapstructure:"ENV"`
-		LogLevel string `mapstructure:"LOG_LEVEL"`
-		Port     string `mapstructure:"PORT"`
+	Media struct {
+		Path         string   `mapstructure:"PATH"`
+		MaxSize      int64    `mapstructure:"MAX_SIZE"`
+		MaxPixels    int64    `mapstructure:"MAX_PIXELS"`
+		AllowedTypes []string `mapstructure:"ALLOWED_TYPES"`
+		Thumbnail    struct {
+			Sizes []string `mapstructure:"SIZES"`
+		}
 	}
-}
 
-var (
-	conf Config
-	once sync.Once
-)
+	Outbox struct {
+		// Interval between two relay passes, in milliseconds.
+		Interval  int `mapstructure:"INTERVAL"`
+		BatchSize int `mapstructure:"BATCH_SIZE"`
+		// MaxAttempts an event is published before it is dead.
+		MaxAttempts int `mapstructure:"MAX_ATTEMPTS"`
+	}
+
+	Webhook struct {
+		MaxAttempts int `mapstructure:"MAX_ATTEMPTS"`
+		// Backoff before the first retry in milliseconds, doubled after every attempt.
+		Backoff int `mapstructure:"BACKOFF"`
+		// Timeout of a delivery request in milliseconds.
+		Timeout int `mapstructure:"TIMEOUT"`
+		// Interval between two dispatcher passes in milliseconds.
+		Interval int `mapstructure:"INTERVAL"`
+	}
+
+	// Retention of the published outbox events and finished webhook deliveries,
+	// in milliseconds, a purger deletes older ones every Interval.
+	Retention struct {
+		Interval   int `mapstructure:"INTERVAL"`
+		Outbox     int `mapstructure:"OUTBOX"`
+		Deliveries int `mapstructure:"DELIVERIES"`
+	}
 
-// Get are responsible to load env and get data an return the struct
-func Get() Config {
-	viper.SetConfigFile(".env")
-	err := viper.ReadInConfig()
+	// HTTPCache holds the Cache-Control header of the news and tag reads, empty sends none.
+	HTTPCache struct {
+		News     string `mapstructure:"NEWS"`
+		NewsList string `mapstructure:"NEWS_LIST"`
+		Tags     string `mapstructure:"TAGS"`
+	} `mapstructure:"HTTP_CACHE"`
 
-	if err != nil {
-		log.Fatal().Err(err).Msg("Failed reading config file")
+	Idempotency struct {
+		// TTL is how long the response of a request with an Idempotency-Key is
+		// replayed, in milliseconds.
+		TTL int `mapstructure:"TTL"`
 	}
 
-	once.Do(func() {
-		log.Info().Msg("Service configuration initialized.")
-		err = viper.Unmarshal(&conf)
-		if err != nil {
-			log.Fatal().Err(err).Msg("")
+	Health struct {
+		// Timeout of every dependency ping in milliseconds.
+		Timeout int `mapstructure:"TIMEOUT"`
+		// Required are the dependencies, database or redis, that fail readiness when down.
+		Required []string `mapstructure:"REQUIRED"`
+	}
+
+	RateLimit struct {
+		Enabled bool `mapstructure:"ENABLED"`
+		// KeyBy is ip, api_key (X-API-Key header) or user (the authenticated actor).
+		KeyBy string `mapstructure:"KEY_BY"`
+		Read  struct {
+			Limit int `mapstructure:"LIMIT"`
+			// Window in milliseconds.
+			Window int `mapstructure:"WINDOW"`
 		}
-	})
+		Write struct {
+			Limit  int `mapstructure:"LIMIT"`
+			Window int `mapstructure:"WINDOW"`
+		}
+	} `mapstructure:"RATE_LIMIT"`
 
-	return conf
+	Server struct {
+		Env      string `mapstructure:"ENV"`
+		LogLevel string `mapstructure:"LOG_LEVEL"`
+		Port     string `mapstructure:"PORT"`
+		// ProxyHeader holds the client IP on the requests of TrustedProxies, IPs or CIDR ranges.
+		ProxyHeader    string   `mapstructure:"PROXY_HEADER"`
+		TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"`
+		// ShutdownTimeout bounds the drain of the server and the stop of the workers, in milliseconds.
+		ShutdownTimeout int `mapstructure:"SHUTDOWN_TIMEOUT"`
+	}
 }
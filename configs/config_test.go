@@ -0,0 +1,107 @@
+package configs_test
+
+import (
+	"bytes"
+	"github.com/magiconair/properties/assert"
+	"news/configs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, name string, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	err := os.WriteFile(path, []byte(content), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadLayers(t *testing.T) {
+	path := writeFile(t, "news.env", "SERVER.PORT=9000\nSERVER.ENV=staging\nDB.DRIVER=sqlite\n"+
+		"HEALTH.REQUIRED=database\nCACHE.REDIS.PRIMARY.HOST=localhost\n")
+	t.Setenv("SERVER_ENV", "production")
+	t.Setenv("SERVER_PORT", "9100")
+
+	conf, rest, err := configs.Load([]string{"--config", path, "--server.port", "9200", "migrate", "down",
+		"--steps", "2"})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, rest, []string{"migrate", "down", "--steps", "2"})
+	// flag over environment over file over defaults
+	assert.Equal(t, conf.Server.Port, "9200")
+	assert.Equal(t, conf.Server.Env, "production")
+	assert.Equal(t, conf.DB.Driver, "sqlite")
+	assert.Equal(t, conf.Server.LogLevel, "info")
+	assert.Equal(t, conf.Health.Required, []string{"database"})
+	assert.Equal(t, conf.Media.AllowedTypes, []string{"image/jpeg", "image/png", "image/gif", "image/webp"})
+	assert.Equal(t, conf.Validate(), nil)
+}
+
+func TestLoadFile(t *testing.T) {
+	// the default file may be missing, the tests run where there is none
+	conf, _, err := configs.Load(nil)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, conf.Server.Port, "8000")
+
+	_, _, err = configs.Load([]string{"--config", filepath.Join(t.TempDir(), "missing.env")})
+	assert.Equal(t, err != nil, true)
+
+	path := writeFile(t, "news.yaml", "db:\n  driver: memory\nserver:\n  port: \"9300\"\n")
+	conf, _, err = configs.Load([]string{"--config", path})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, conf.DB.Driver, "memory")
+	assert.Equal(t, conf.Server.Port, "9300")
+}
+
+func TestValidate(t *testing.T) {
+	conf, _, err := configs.Load([]string{"--server.port", "70000", "--cache.redis.expired.news", "0",
+		"--rate_limit.key_by", "cookie", "--health.required", "database,queue", "--auth.api_keys", "s3cr3t",
+		"--server.proxy_header", "X-Real-IP"})
+	assert.Equal(t, err, nil)
+
+	err = conf.Validate()
+	errs, ok := err.(configs.ValidationError)
+	assert.Equal(t, ok, true)
+	assert.Equal(t, []string(errs), []string{
+		"AUTH.API_KEYS must be actor:key pairs",
+		"DB.MYSQL.HOST is required",
+		"DB.MYSQL.USER is required",
+		"DB.MYSQL.NAME is required",
+		"CACHE.REDIS.PRIMARY.HOST is required",
+		"CACHE.REDIS.EXPIRED.NEWS must be positive, got 0",
+		`HEALTH.REQUIRED must be one of database, redis, got "queue"`,
+		`RATE_LIMIT.KEY_BY must be one of ip, api_key, user, got "cookie"`,
+		`SERVER.PORT must be a port between 1 and 65535, got "70000"`,
+		"SERVER.TRUSTED_PROXIES is required with SERVER.PROXY_HEADER",
+	})
+}
+
+func TestWrite(t *testing.T) {
+	t.Setenv("DB_MYSQL_PASSWORD", "hunter2")
+	t.Setenv("AUTH_API_KEYS", "editor:s3cr3t")
+	conf, _, err := configs.Load([]string{"--db.driver", "memory"})
+	assert.Equal(t, err, nil)
+
+	var output bytes.Buffer
+	err = configs.Write(&output, conf)
+	assert.Equal(t, err, nil)
+	lines := strings.Split(output.String(), "\n")
+	assert.Equal(t, contains(lines, "DB.DRIVER=memory"), true)
+	assert.Equal(t, contains(lines, "DB.MYSQL.PASSWORD="+configs.Redacted), true)
+	assert.Equal(t, contains(lines, "DB.POSTGRES.PASSWORD="), true)
+	assert.Equal(t, contains(lines, "HEALTH.REQUIRED=database,redis"), true)
+	assert.Equal(t, contains(lines, "AUTH.API_KEYS="+configs.Redacted), true)
+	assert.Equal(t, strings.Contains(output.String(), "hunter2"), false)
+	assert.Equal(t, strings.Contains(output.String(), "s3cr3t"), false)
+}
+
+func contains(lines []string, line string) bool {
+	for _, l := range lines {
+		if l == line {
+			return true
+		}
+	}
+	return false
+}
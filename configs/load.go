@@ -0,0 +1,138 @@
+package configs
+
+import (
+	"errors"
+	"io/fs"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// DefaultPath is the config file read when --config is not given, it may be missing.
+const DefaultPath = ".env"
+
+// defaults are the values of the settings no source sets. Hosts, users and
+// database names have none, a deployment must say where its dependencies are.
+var defaults = map[string]interface{}{
+	"CACHE.REDIS.PRIMARY.PORT": "6379",
+	"CACHE.REDIS.EXPIRED.NEWS": 10,
+	"DB.DRIVER":                "mysql",
+	"DB.MYSQL.PORT":            "3306",
+	"DB.MYSQL.TIMEZONE":        "UTC",
+	"DB.POSTGRES.PORT":         "5432",
+	"DB.POSTGRES.SSL_MODE":     "disable",
+	"DB.POSTGRES.TIMEZONE":     "UTC",
+	"DB.SQLITE.PATH":           "./news.db",
+	"MEDIA.PATH":               "./uploads",
+	"MEDIA.MAX_SIZE":           2097152,
+	"MEDIA.MAX_PIXELS":         40000000,
+	"MEDIA.ALLOWED_TYPES":      []string{"image/jpeg", "image/png", "image/gif", "image/webp"},
+	"MEDIA.THUMBNAIL.SIZES":    []string{"150x150", "640x360"},
+	"OUTBOX.INTERVAL":          1000,
+	"OUTBOX.BATCH_SIZE":        100,
+	"OUTBOX.MAX_ATTEMPTS":      10,
+	"WEBHOOK.MAX_ATTEMPTS":     8,
+	"WEBHOOK.BACKOFF":          1000,
+	"WEBHOOK.TIMEOUT":          5000,
+	"WEBHOOK.INTERVAL":         1000,
+	"RETENTION.INTERVAL":       3600000,
+	"RETENTION.OUTBOX":         604800000,
+	"RETENTION.DELIVERIES":     2592000000,
+	"HTTP_CACHE.NEWS":          "public, max-age=60",
+	"HTTP_CACHE.NEWS_LIST":     "public, no-cache",
+	"HTTP_CACHE.TAGS":          "public, max-age=300",
+	"IDEMPOTENCY.TTL":          86400000,
+	"HEALTH.TIMEOUT":           1000,
+	"HEALTH.REQUIRED":          []string{"database", "redis"},
+	"RATE_LIMIT.ENABLED":       true,
+	"RATE_LIMIT.KEY_BY":        "ip",
+	"RATE_LIMIT.READ.LIMIT":    300,
+	"RATE_LIMIT.READ.WINDOW":   60000,
+	"RATE_LIMIT.WRITE.LIMIT":   60,
+	"RATE_LIMIT.WRITE.WINDOW":  60000,
+	"SERVER.ENV":               "development",
+	"SERVER.LOG_LEVEL":         "info",
+	"SERVER.PORT":              "8000",
+	"SERVER.SHUTDOWN_TIMEOUT":  10000,
+}
+
+// Load resolves the configuration from, by increasing priority, the defaults,
+// the config file, the environment and the command line flags. The file is
+// DefaultPath or the one given with --config, in the .env format unless its
+// extension is one viper reads like .yaml or .json. Environment variables and
+// flags are named after the keys: DB.MYSQL.HOST is read from DB_MYSQL_HOST and
+// --db.mysql.host. Flags stop at the first argument that is not one, Load
+// returns it and the arguments after it.
+func Load(args []string) (conf Config, rest []string, err error) {
+	v := viper.New()
+	flags := pflag.NewFlagSet("news", pflag.ContinueOnError)
+	flags.SetInterspersed(false)
+	path := flags.String("config", DefaultPath, "config file")
+	settings(reflect.ValueOf(&conf).Elem(), "", func(key string, value reflect.Value) {
+		v.SetDefault(key, value.Interface())
+		if value, ok := defaults[key]; ok {
+			v.SetDefault(key, value)
+		}
+		name := strings.ToLower(key)
+		flags.String(name, "", "overrides "+key)
+		// the error only tells the flag is nil
+		_ = v.BindPFlag(key, flags.Lookup(name))
+	})
+	err = flags.Parse(args)
+	if err != nil {
+		return
+	}
+
+	v.SetConfigFile(*path)
+	if !contains(viper.SupportedExts, strings.TrimPrefix(filepath.Ext(*path), ".")) {
+		v.SetConfigType("env")
+	}
+	err = v.ReadInConfig()
+	if errors.Is(err, fs.ErrNotExist) && !flags.Changed("config") {
+		err = nil
+		*path = ""
+	}
+	if err != nil {
+		return
+	}
+	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	v.AutomaticEnv()
+
+	err = v.Unmarshal(&conf)
+	if err != nil {
+		return
+	}
+	log.Info().Str("file", *path).Msg("Service configuration initialized.")
+	return conf, flags.Args(), nil
+}
+
+// settings calls visit with the key and the value of every setting of a
+// config struct, in the order of its fields. A field without mapstructure tag
+// is keyed by its upper cased name, like viper matches it.
+func settings(v reflect.Value, prefix string, visit func(key string, value reflect.Value)) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		key := field.Tag.Get("mapstructure")
+		if key == "" {
+			key = strings.ToUpper(field.Name)
+		}
+		if field.Type.Kind() == reflect.Struct {
+			settings(v.Field(i), prefix+key+".", visit)
+			continue
+		}
+		visit(prefix+key, v.Field(i))
+	}
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,40 @@
+package configs
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// Redacted replaces the value of secrets when they are set.
+const Redacted = "******"
+
+// Write writes every setting of c as KEY=VALUE lines, the format of the .env
+// file, with secrets like passwords redacted.
+func Write(w io.Writer, c Config) (err error) {
+	settings(reflect.ValueOf(c), "", func(key string, value reflect.Value) {
+		if err != nil {
+			return
+		}
+		text := fmt.Sprint(value.Interface())
+		if value.Kind() == reflect.Slice {
+			text = strings.Join(value.Interface().([]string), ",")
+		}
+		if secret(key) && text != "" {
+			text = Redacted
+		}
+		_, err = fmt.Fprintf(w, "%s=%s\n", key, text)
+	})
+	return
+}
+
+func secret(key string) bool {
+	name := key[strings.LastIndex(key, ".")+1:]
+	for _, word := range []string{"PASSWORD", "SECRET", "TOKEN", "API_KEY"} {
+		if strings.Contains(name, word) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package configs
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// ValidationError lists every invalid setting of a config.
+type ValidationError []string
+
+func (e ValidationError) Error() string {
+	return "invalid configuration: " + strings.Join(e, "; ")
+}
+
+// Validate checks the settings the app needs to start and returns a
+// ValidationError with every problem it finds, not only the first one.
+func (c Config) Validate() error {
+	var errs ValidationError
+	invalid := func(key string, format string, a ...interface{}) {
+		errs = append(errs, key+" "+fmt.Sprintf(format, a...))
+	}
+	required := func(key string, value string) bool {
+		if strings.TrimSpace(value) == "" {
+			invalid(key, "is required")
+			return false
+		}
+		return true
+	}
+	port := func(key string, value string) {
+		if !required(key, value) {
+			return
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > 65535 {
+			invalid(key, "must be a port between 1 and 65535, got %q", value)
+		}
+	}
+	positive := func(key string, value int64) {
+		if value <= 0 {
+			invalid(key, "must be positive, got %d", value)
+		}
+	}
+	oneOf := func(key string, value string, allowed ...string) {
+		if !contains(allowed, value) {
+			invalid(key, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+		}
+	}
+
+	for _, pair := range c.Auth.APIKeys {
+		// the pair may be a key alone, it is not printed
+		actor, key, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || actor == "" || key == "" {
+			invalid("AUTH.API_KEYS", "must be actor:key pairs")
+			break
+		}
+	}
+
+	oneOf("DB.DRIVER", c.DB.Driver, "mysql", "postgres", "sqlite", "memory")
+	switch c.DB.Driver {
+	case "mysql":
+		required("DB.MYSQL.HOST", c.DB.MySQL.Host)
+		port("DB.MYSQL.PORT", c.DB.MySQL.Port)
+		required("DB.MYSQL.USER", c.DB.MySQL.Username)
+		required("DB.MYSQL.NAME", c.DB.MySQL.Name)
+	case "postgres":
+		required("DB.POSTGRES.HOST", c.DB.Postgres.Host)
+		port("DB.POSTGRES.PORT", c.DB.Postgres.Port)
+		required("DB.POSTGRES.USER", c.DB.Postgres.Username)
+		required("DB.POSTGRES.NAME", c.DB.Postgres.Name)
+		oneOf("DB.POSTGRES.SSL_MODE", c.DB.Postgres.SSLMode,
+			"disable", "allow", "prefer", "require", "verify-ca", "verify-full")
+	case "sqlite":
+		required("DB.SQLITE.PATH", c.DB.SQLite.Path)
+	}
+	// redis is only used next to a database
+	if c.DB.Driver != "memory" {
+		required("CACHE.REDIS.PRIMARY.HOST", c.Cache.Redis.Primary.Host)
+		port("CACHE.REDIS.PRIMARY.PORT", c.Cache.Redis.Primary.Port)
+	}
+	positive("CACHE.REDIS.EXPIRED.NEWS", int64(c.Cache.Redis.Expired.News))
+
+	required("MEDIA.PATH", c.Media.Path)
+	positive("MEDIA.MAX_SIZE", c.Media.MaxSize)
+	positive("MEDIA.MAX_PIXELS", c.Media.MaxPixels)
+
+	positive("OUTBOX.INTERVAL", int64(c.Outbox.Interval))
+	positive("OUTBOX.BATCH_SIZE", int64(c.Outbox.BatchSize))
+	positive("OUTBOX.MAX_ATTEMPTS", int64(c.Outbox.MaxAttempts))
+	positive("WEBHOOK.MAX_ATTEMPTS", int64(c.Webhook.MaxAttempts))
+	positive("WEBHOOK.BACKOFF", int64(c.Webhook.Backoff))
+	positive("WEBHOOK.TIMEOUT", int64(c.Webhook.Timeout))
+	positive("WEBHOOK.INTERVAL", int64(c.Webhook.Interval))
+	positive("RETENTION.INTERVAL", int64(c.Retention.Interval))
+	positive("RETENTION.OUTBOX", int64(c.Retention.Outbox))
+	positive("RETENTION.DELIVERIES", int64(c.Retention.Deliveries))
+
+	positive("IDEMPOTENCY.TTL", int64(c.Idempotency.TTL))
+	positive("HEALTH.TIMEOUT", int64(c.Health.Timeout))
+	for _, name := range c.Health.Required {
+		oneOf("HEALTH.REQUIRED", strings.TrimSpace(name), "database", "redis")
+	}
+
+	if c.RateLimit.Enabled {
+		oneOf("RATE_LIMIT.KEY_BY", c.RateLimit.KeyBy, "ip", "api_key", "user")
+		if c.RateLimit.Read.Limit < 0 {
+			invalid("RATE_LIMIT.READ.LIMIT", "must not be negative, got %d", c.RateLimit.Read.Limit)
+		}
+		if c.RateLimit.Write.Limit < 0 {
+			invalid("RATE_LIMIT.WRITE.LIMIT", "must not be negative, got %d", c.RateLimit.Write.Limit)
+		}
+		positive("RATE_LIMIT.READ.WINDOW", int64(c.RateLimit.Read.Window))
+		positive("RATE_LIMIT.WRITE.WINDOW", int64(c.RateLimit.Write.Window))
+	}
+
+	port("SERVER.PORT", c.Server.Port)
+	if required("SERVER.LOG_LEVEL", c.Server.LogLevel) {
+		_, err := zerolog.ParseLevel(c.Server.LogLevel)
+		if err != nil {
+			invalid("SERVER.LOG_LEVEL", "is not a log level, got %q", c.Server.LogLevel)
+		}
+	}
+	positive("SERVER.SHUTDOWN_TIMEOUT", int64(c.Server.ShutdownTimeout))
+	if c.Server.ProxyHeader != "" && len(c.Server.TrustedProxies) == 0 {
+		invalid("SERVER.TRUSTED_PROXIES", "is required with SERVER.PROXY_HEADER")
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		_, _, err := net.ParseCIDR(proxy)
+		if net.ParseIP(proxy) == nil && err != nil {
+			invalid("SERVER.TRUSTED_PROXIES", "must be IPs or CIDR ranges, got %q", proxy)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package audit
+
+import "context"
+
+// MetadataKey holds the Metadata of a request. Handlers pass c.Context() on to the
+// services and fasthttp answers Value for string keys with the values set by
+// c.Locals, so the HTTP middleware stores it with c.Locals(audit.MetadataKey, metadata).
+const MetadataKey = "audit.metadata"
+
+// SystemActor is the actor of writes made without request metadata, like the CLI.
+const SystemActor = "system"
+
+// Metadata tells who made a write and from where.
+type Metadata struct {
+	Actor     string
+	IP        string
+	RequestID string
+}
+
+// NewContext returns a copy of ctx carrying the metadata, for callers outside fiber.
+func NewContext(ctx context.Context, metadata Metadata) context.Context {
+	return context.WithValue(ctx, MetadataKey, metadata)
+}
+
+func FromContext(ctx context.Context) Metadata {
+	metadata, ok := ctx.Value(MetadataKey).(Metadata)
+	if !ok || metadata.Actor == "" {
+		metadata.Actor = SystemActor
+	}
+	return metadata
+}
@@ -0,0 +1,28 @@
+package audit
+
+import (
+	"context"
+	"news/domain/entities"
+	"news/shared/metrics"
+	"time"
+)
+
+// instrumentedRepository observes the duration of every call of the wrapped
+// repository in the repository_query_duration_seconds metric.
+type instrumentedRepository struct {
+	repo Repository
+}
+
+func NewInstrumentedRepository(repo Repository) *instrumentedRepository {
+	return &instrumentedRepository{repo: repo}
+}
+
+func (r *instrumentedRepository) CreateEntry(ctx context.Context, entry *entities.AuditEntry) error {
+	defer metrics.ObserveQuery("audit", "CreateEntry", time.Now())
+	return r.repo.CreateEntry(ctx, entry)
+}
+
+func (r *instrumentedRepository) GetEntries(ctx context.Context, filter *entities.AuditFilter) (*entities.AuditEntries, error) {
+	defer metrics.ObserveQuery("audit", "GetEntries", time.Now())
+	return r.repo.GetEntries(ctx, filter)
+}
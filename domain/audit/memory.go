@@ -0,0 +1,55 @@
+package audit
+
+import (
+	"context"
+	"news/domain/entities"
+	"news/shared/failure"
+	"sort"
+	"sync"
+)
+
+// memoryRepository keeps the audit log in a slice, it behaves like the SQL repository.
+type memoryRepository struct {
+	mu      sync.RWMutex
+	entries entities.AuditEntries
+}
+
+func NewMemoryRepository() *memoryRepository {
+	return &memoryRepository{}
+}
+
+func (r *memoryRepository) CreateEntry(ctx context.Context, entry *entities.AuditEntry) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stored := range r.entries {
+		if stored.ID == entry.ID {
+			return failure.InternalServerError
+		}
+	}
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+func (r *memoryRepository) GetEntries(ctx context.Context, filter *entities.AuditFilter) (*entities.AuditEntries, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	entries := entities.AuditEntries{}
+	for _, entry := range r.entries {
+		if filter.Match(entry) {
+			entries = append(entries, entry)
+		}
+	}
+	if len(entries) < 1 {
+		return nil, ErrNotFound
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].CreatedAt.Equal(entries[j].CreatedAt) {
+			return entries[i].ID > entries[j].ID
+		}
+		return entries[i].CreatedAt.After(entries[j].CreatedAt)
+	})
+	if len(entries) > filter.Limit {
+		entries = entries[:filter.Limit]
+	}
+	return &entries, nil
+}
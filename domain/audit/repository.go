@@ -0,0 +1,115 @@
+package audit
+
+//go:generate go run github.com/golang/mock/mockgen -source repository.go -destination mock/repository_mock.go -package audit_mock
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"net/http"
+	"news/domain/entities"
+	"news/shared/database"
+	"news/shared/failure"
+	"news/shared/logger"
+	"strings"
+)
+
+// Repository is append-only, entries are never updated or deleted.
+type Repository interface {
+	CreateEntry(ctx context.Context, entry *entities.AuditEntry) error
+	GetEntries(ctx context.Context, filter *entities.AuditFilter) (*entities.AuditEntries, error)
+}
+
+// Recorder stores entries written without a SQL transaction, by the memory repositories.
+type Recorder interface {
+	CreateEntry(ctx context.Context, entry *entities.AuditEntry) error
+}
+
+const auditColumns = "id, actor, action, entity_type, entity_id, before_snapshot, after_snapshot, ip, request_id, `createdAt`"
+
+var (
+	ErrNotFound = failure.New(http.StatusNotFound, "audit.not_found", "audit entry not found")
+)
+
+type repository struct {
+	DB *sqlx.DB
+}
+
+func NewRepository(DB *sqlx.DB) *repository {
+	return &repository{DB: DB}
+}
+
+const insertEntryQuery = "INSERT INTO `audit_log`(" + auditColumns + ") VALUES (:id, :actor, :action, :entity_type, " +
+	":entity_id, :before_snapshot, :after_snapshot, :ip, :request_id, :createdAt)"
+
+func (r *repository) CreateEntry(ctx context.Context, entry *entities.AuditEntry) (err error) {
+	stmt, err := r.DB.PrepareNamedContext(ctx, database.Rebind(r.DB, insertEntryQuery))
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
+		return
+	}
+	_, err = stmt.ExecContext(ctx, entry)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
+	}
+	return
+}
+
+// Insert writes entry in tx, it is committed or rolled back with the write it records.
+func Insert(ctx context.Context, tx *sqlx.Tx, entry *entities.AuditEntry) (err error) {
+	stmt, err := tx.PrepareNamedContext(ctx, database.Rebind(tx, insertEntryQuery))
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+	_, err = stmt.ExecContext(ctx, entry)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+	return
+}
+
+// GetEntries returns the entries matching the filter, newest first.
+func (r *repository) GetEntries(ctx context.Context, filter *entities.AuditFilter) (entries *entities.AuditEntries, err error) {
+	var conditions []string
+	var args []interface{}
+	add := func(condition string, arg interface{}) {
+		conditions = append(conditions, condition)
+		args = append(args, arg)
+	}
+	if filter.EntityType != "" {
+		add("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != "" {
+		add("entity_id = ?", filter.EntityID)
+	}
+	if filter.Actor != "" {
+		add("actor = ?", filter.Actor)
+	}
+	if filter.From != nil {
+		add("`createdAt` >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		add("`createdAt` < ?", *filter.To)
+	}
+	query := "SELECT " + auditColumns + " FROM `audit_log` "
+	if len(conditions) > 0 {
+		query += "WHERE " + strings.Join(conditions, " AND ") + " "
+	}
+	query += "ORDER BY `createdAt` desc, id desc LIMIT ?"
+	args = append(args, filter.Limit)
+
+	entries = new(entities.AuditEntries)
+	err = r.DB.SelectContext(ctx, entries, database.Rebind(r.DB, query), args...)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
+		return
+	}
+	if len(*entries) < 1 {
+		err = ErrNotFound
+	}
+	return
+}
@@ -0,0 +1,80 @@
+package audit_test
+
+import (
+	"context"
+	"github.com/magiconair/properties/assert"
+	"news/domain/audit"
+	"news/domain/entities"
+	"news/infras/dbtest"
+	"testing"
+	"time"
+)
+
+func repositoryContract(t *testing.T, newRepo func(t *testing.T) audit.Repository) {
+	ctx := context.Background()
+	baseTime := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	newEntry := func(id string, actor string, entityType string, entityID string, minutes int) *entities.AuditEntry {
+		after := `{"id":"` + entityID + `"}`
+		return &entities.AuditEntry{ID: id, Actor: actor, Action: entities.AuditCreate, EntityType: entityType,
+			EntityID: entityID, After: &after, IP: "10.0.0.1", RequestID: "request-" + id,
+			CreatedAt: baseTime.Add(time.Duration(minutes) * time.Minute)}
+	}
+	ids := func(entries *entities.AuditEntries) (res []string) {
+		for _, entry := range *entries {
+			res = append(res, entry.ID)
+		}
+		return
+	}
+
+	t.Run("filters", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.GetEntries(ctx, &entities.AuditFilter{Limit: 10})
+		assert.Equal(t, err, audit.ErrNotFound)
+
+		assert.Equal(t, repo.CreateEntry(ctx, newEntry("entry1", "budi", entities.AuditNews, "news1", 0)), nil)
+		assert.Equal(t, repo.CreateEntry(ctx, newEntry("entry2", "sari", entities.AuditNews, "news1", 1)), nil)
+		assert.Equal(t, repo.CreateEntry(ctx, newEntry("entry3", "budi", entities.AuditTag, "tag1", 2)), nil)
+		assert.Equal(t, repo.CreateEntry(ctx, newEntry("entry1", "budi", entities.AuditTag, "tag1", 3)) != nil, true)
+
+		entries, err := repo.GetEntries(ctx, &entities.AuditFilter{Limit: 10})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids(entries), []string{"entry3", "entry2", "entry1"})
+		assert.Equal(t, (*entries)[0].Before == nil, true)
+		assert.Equal(t, *(*entries)[0].After, `{"id":"tag1"}`)
+		assert.Equal(t, (*entries)[0].RequestID, "request-entry3")
+
+		entries, err = repo.GetEntries(ctx, &entities.AuditFilter{EntityType: entities.AuditNews, EntityID: "news1", Limit: 10})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids(entries), []string{"entry2", "entry1"})
+
+		entries, err = repo.GetEntries(ctx, &entities.AuditFilter{Actor: "budi", Limit: 1})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids(entries), []string{"entry3"})
+
+		from, to := baseTime.Add(time.Minute), baseTime.Add(2*time.Minute)
+		entries, err = repo.GetEntries(ctx, &entities.AuditFilter{From: &from, To: &to, Limit: 10})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids(entries), []string{"entry2"})
+
+		_, err = repo.GetEntries(ctx, &entities.AuditFilter{Actor: "nobody", Limit: 10})
+		assert.Equal(t, err, audit.ErrNotFound)
+	})
+}
+
+func TestMemoryRepository(t *testing.T) {
+	repositoryContract(t, func(t *testing.T) audit.Repository {
+		return audit.NewMemoryRepository()
+	})
+}
+
+func TestRepository(t *testing.T) {
+	for _, driver := range dbtest.Drivers() {
+		driver := driver
+		t.Run(driver, func(t *testing.T) {
+			repositoryContract(t, func(t *testing.T) audit.Repository {
+				return audit.NewRepository(dbtest.Open(t, driver))
+			})
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"news/domain/entities"
+	"news/shared/failure"
+	"news/shared/logger"
+	"strings"
+)
+
+type Service interface {
+	GetEntries(ctx context.Context, filter *entities.AuditFilter) (*[]entities.AuditEntryDto, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) *service {
+	return &service{repo: repo}
+}
+
+// NewEntry returns the entry of a write made by the actor of ctx, before and
+// after are marshalled to JSON and left empty when nil. The repositories store
+// it with the write, see Insert.
+func NewEntry(ctx context.Context, action string, entityType string, entityID string, before interface{},
+	after interface{}) (*entities.AuditEntry, error) {
+	beforeSnapshot, err := snapshot(before)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return nil, failure.InternalServerError.Wrap(err)
+	}
+	afterSnapshot, err := snapshot(after)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return nil, failure.InternalServerError.Wrap(err)
+	}
+	// fiber hands out params and headers backed by buffers it reuses for the next request
+	metadata := FromContext(ctx)
+	return entities.NewAuditEntry(strings.Clone(metadata.Actor), action, entityType, strings.Clone(entityID),
+		beforeSnapshot, afterSnapshot, strings.Clone(metadata.IP), strings.Clone(metadata.RequestID)), nil
+}
+
+func snapshot(value interface{}) (*string, error) {
+	if value == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	result := string(data)
+	return &result, nil
+}
+
+func (s service) GetEntries(ctx context.Context, filter *entities.AuditFilter) (result *[]entities.AuditEntryDto, err error) {
+	entries, err := s.repo.GetEntries(ctx, filter)
+	if err != nil {
+		if failure.GetStatus(err) == http.StatusNotFound {
+			return &[]entities.AuditEntryDto{}, nil
+		}
+		return
+	}
+	result = entries.ToAuditEntriesDto()
+	return
+}
@@ -0,0 +1,43 @@
+package comment
+
+import (
+	"context"
+	"news/domain/entities"
+	"news/shared/metrics"
+	"time"
+)
+
+// instrumentedRepository observes the duration of every call of the wrapped
+// repository in the repository_query_duration_seconds metric.
+type instrumentedRepository struct {
+	repo Repository
+}
+
+func NewInstrumentedRepository(repo Repository) *instrumentedRepository {
+	return &instrumentedRepository{repo: repo}
+}
+
+func (r *instrumentedRepository) CreateComment(ctx context.Context, comment *entities.Comment) error {
+	defer metrics.ObserveQuery("comment", "CreateComment", time.Now())
+	return r.repo.CreateComment(ctx, comment)
+}
+
+func (r *instrumentedRepository) GetCommentByID(ctx context.Context, id string) (*entities.Comment, error) {
+	defer metrics.ObserveQuery("comment", "GetCommentByID", time.Now())
+	return r.repo.GetCommentByID(ctx, id)
+}
+
+func (r *instrumentedRepository) GetCommentsByNewsID(ctx context.Context, newsID string, status entities.CommentStatus) (*entities.Comments, error) {
+	defer metrics.ObserveQuery("comment", "GetCommentsByNewsID", time.Now())
+	return r.repo.GetCommentsByNewsID(ctx, newsID, status)
+}
+
+func (r *instrumentedRepository) GetCommentsByStatus(ctx context.Context, status entities.CommentStatus) (*entities.Comments, error) {
+	defer metrics.ObserveQuery("comment", "GetCommentsByStatus", time.Now())
+	return r.repo.GetCommentsByStatus(ctx, status)
+}
+
+func (r *instrumentedRepository) UpdateCommentStatus(ctx context.Context, id string, status entities.CommentStatus) (*entities.Comment, error) {
+	defer metrics.ObserveQuery("comment", "UpdateCommentStatus", time.Now())
+	return r.repo.UpdateCommentStatus(ctx, id, status)
+}
@@ -0,0 +1,97 @@
+package comment
+
+import (
+	"context"
+	"news/domain/entities"
+	"news/shared/failure"
+	"sort"
+	"sync"
+)
+
+// memoryRepository keeps comments in a map, it behaves like the SQL repository.
+type memoryRepository struct {
+	mu       sync.RWMutex
+	comments map[string]entities.Comment
+}
+
+func NewMemoryRepository() *memoryRepository {
+	return &memoryRepository{comments: map[string]entities.Comment{}}
+}
+
+func (r *memoryRepository) CreateComment(ctx context.Context, comment *entities.Comment) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.comments[comment.ID]; ok {
+		return failure.InternalServerError
+	}
+	r.comments[comment.ID] = *comment
+	return nil
+}
+
+func (r *memoryRepository) GetCommentByID(ctx context.Context, id string) (comment *entities.Comment, err error) {
+	comments, err := r.selectComment(func(comment entities.Comment) bool {
+		return comment.ID == id
+	})
+	if err != nil {
+		return
+	}
+	return &(*comments)[0], nil
+}
+
+func (r *memoryRepository) GetCommentsByNewsID(ctx context.Context, newsID string, status entities.CommentStatus) (*entities.Comments, error) {
+	return r.selectComment(func(comment entities.Comment) bool {
+		return comment.NewsID == newsID && comment.Status == status
+	})
+}
+
+func (r *memoryRepository) GetCommentsByStatus(ctx context.Context, status entities.CommentStatus) (*entities.Comments, error) {
+	return r.selectComment(func(comment entities.Comment) bool {
+		return comment.Status == status
+	})
+}
+
+func (r *memoryRepository) UpdateCommentStatus(ctx context.Context, id string, status entities.CommentStatus) (*entities.Comment, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.comments[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	stored.Status = status
+	r.comments[id] = stored
+	return &stored, nil
+}
+
+// CountComments returns how many comments of the news have the status.
+func (r *memoryRepository) CountComments(newsID string, status entities.CommentStatus) (count int) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, comment := range r.comments {
+		if comment.NewsID == newsID && comment.Status == status {
+			count++
+		}
+	}
+	return
+}
+
+// selectComment returns the matching comments oldest first, or NotFound when none match.
+func (r *memoryRepository) selectComment(match func(comment entities.Comment) bool) (*entities.Comments, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	comments := entities.Comments{}
+	for _, comment := range r.comments {
+		if match(comment) {
+			comments = append(comments, comment)
+		}
+	}
+	if len(comments) < 1 {
+		return nil, ErrNotFound
+	}
+	sort.SliceStable(comments, func(i, j int) bool {
+		if comments[i].CreatedAt.Equal(comments[j].CreatedAt) {
+			return comments[i].ID < comments[j].ID
+		}
+		return comments[i].CreatedAt.Before(comments[j].CreatedAt)
+	})
+	return &comments, nil
+}
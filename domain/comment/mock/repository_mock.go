@@ -0,0 +1,110 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package comment_mock is a generated GoMock package.
+package comment_mock
+
+import (
+	context "context"
+	entities "news/domain/entities"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateComment mocks base method.
+func (m *MockRepository) CreateComment(ctx context.Context, comment *entities.Comment) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateComment", ctx, comment)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateComment indicates an expected call of CreateComment.
+func (mr *MockRepositoryMockRecorder) CreateComment(ctx, comment interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateComment", reflect.TypeOf((*MockRepository)(nil).CreateComment), ctx, comment)
+}
+
+// GetCommentByID mocks base method.
+func (m *MockRepository) GetCommentByID(ctx context.Context, id string) (*entities.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommentByID", ctx, id)
+	ret0, _ := ret[0].(*entities.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommentByID indicates an expected call of GetCommentByID.
+func (mr *MockRepositoryMockRecorder) GetCommentByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentByID", reflect.TypeOf((*MockRepository)(nil).GetCommentByID), ctx, id)
+}
+
+// GetCommentsByNewsID mocks base method.
+func (m *MockRepository) GetCommentsByNewsID(ctx context.Context, newsID string, status entities.CommentStatus) (*entities.Comments, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommentsByNewsID", ctx, newsID, status)
+	ret0, _ := ret[0].(*entities.Comments)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommentsByNewsID indicates an expected call of GetCommentsByNewsID.
+func (mr *MockRepositoryMockRecorder) GetCommentsByNewsID(ctx, newsID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentsByNewsID", reflect.TypeOf((*MockRepository)(nil).GetCommentsByNewsID), ctx, newsID, status)
+}
+
+// GetCommentsByStatus mocks base method.
+func (m *MockRepository) GetCommentsByStatus(ctx context.Context, status entities.CommentStatus) (*entities.Comments, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCommentsByStatus", ctx, status)
+	ret0, _ := ret[0].(*entities.Comments)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCommentsByStatus indicates an expected call of GetCommentsByStatus.
+func (mr *MockRepositoryMockRecorder) GetCommentsByStatus(ctx, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCommentsByStatus", reflect.TypeOf((*MockRepository)(nil).GetCommentsByStatus), ctx, status)
+}
+
+// UpdateCommentStatus mocks base method.
+func (m *MockRepository) UpdateCommentStatus(ctx context.Context, id string, status entities.CommentStatus) (*entities.Comment, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCommentStatus", ctx, id, status)
+	ret0, _ := ret[0].(*entities.Comment)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCommentStatus indicates an expected call of UpdateCommentStatus.
+func (mr *MockRepositoryMockRecorder) UpdateCommentStatus(ctx, id, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCommentStatus", reflect.TypeOf((*MockRepository)(nil).UpdateCommentStatus), ctx, id, status)
+}
@@ -0,0 +1,47 @@
+package comment
+
+import (
+	"context"
+	"news/domain/entities"
+	"strings"
+	"unicode"
+)
+
+// Moderator decides the initial status of a freshly submitted comment.
+type Moderator interface {
+	Moderate(ctx context.Context, comment *entities.Comment) entities.CommentStatus
+}
+
+type wordListModerator struct {
+	blocked     map[string]bool
+	autoApprove bool
+}
+
+// NewWordListModerator flags comments containing any of the blocked words as spam.
+// Clean comments are approved when autoApprove is set, otherwise they wait in the
+// moderation queue.
+func NewWordListModerator(blockedWords []string, autoApprove bool) *wordListModerator {
+	blocked := map[string]bool{}
+	for _, word := range blockedWords {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if word != "" {
+			blocked[word] = true
+		}
+	}
+	return &wordListModerator{blocked: blocked, autoApprove: autoApprove}
+}
+
+func (m *wordListModerator) Moderate(ctx context.Context, comment *entities.Comment) entities.CommentStatus {
+	words := strings.FieldsFunc(strings.ToLower(comment.Author+" "+comment.Content), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	for _, word := range words {
+		if m.blocked[word] {
+			return entities.CommentSpam
+		}
+	}
+	if m.autoApprove {
+		return entities.CommentApproved
+	}
+	return entities.CommentPending
+}
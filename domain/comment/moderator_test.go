@@ -0,0 +1,60 @@
+package comment_test
+
+import (
+	"context"
+	"github.com/magiconair/properties/assert"
+	"news/domain/comment"
+	"news/domain/entities"
+	"testing"
+)
+
+func TestWordListModerator(t *testing.T) {
+	sliceTest := []struct {
+		testTitle   string
+		words       []string
+		autoApprove bool
+		input       entities.Comment
+		expected    entities.CommentStatus
+	}{
+		{
+			testTitle: "clean comment waits for moderation",
+			words:     []string{"casino"},
+			input:     entities.Comment{Author: "budi", Content: "artikel yang bagus"},
+			expected:  entities.CommentPending,
+		},
+		{
+			testTitle:   "clean comment auto approved",
+			words:       []string{"casino"},
+			autoApprove: true,
+			input:       entities.Comment{Author: "budi", Content: "artikel yang bagus"},
+			expected:    entities.CommentApproved,
+		},
+		{
+			testTitle:   "blocked word is spam",
+			words:       []string{" Casino "},
+			autoApprove: true,
+			input:       entities.Comment{Author: "budi", Content: "Visit my CASINO, now!"},
+			expected:    entities.CommentSpam,
+		},
+		{
+			testTitle: "blocked word in author is spam",
+			words:     []string{"casino"},
+			input:     entities.Comment{Author: "casino", Content: "artikel yang bagus"},
+			expected:  entities.CommentSpam,
+		},
+		{
+			testTitle: "partial word is not blocked",
+			words:     []string{"casino"},
+			input:     entities.Comment{Author: "budi", Content: "casinos"},
+			expected:  entities.CommentPending,
+		},
+	}
+
+	for _, test := range sliceTest {
+		t.Run(test.testTitle, func(t *testing.T) {
+			moderator := comment.NewWordListModerator(test.words, test.autoApprove)
+			actual := moderator.Moderate(context.Background(), &test.input)
+			assert.Equal(t, actual, test.expected)
+		})
+	}
+}
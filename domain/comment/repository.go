@@ -0,0 +1,102 @@
+package comment
+
+//go:generate go run github.com/golang/mock/mockgen -source repository.go -destination mock/repository_mock.go -package comment_mock
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"net/http"
+	"news/domain/entities"
+	"news/shared/database"
+	"news/shared/failure"
+	"news/shared/logger"
+)
+
+type Repository interface {
+	CreateComment(ctx context.Context, comment *entities.Comment) error
+	GetCommentByID(ctx context.Context, id string) (*entities.Comment, error)
+	GetCommentsByNewsID(ctx context.Context, newsID string, status entities.CommentStatus) (*entities.Comments, error)
+	GetCommentsByStatus(ctx context.Context, status entities.CommentStatus) (*entities.Comments, error)
+	UpdateCommentStatus(ctx context.Context, id string, status entities.CommentStatus) (*entities.Comment, error)
+}
+
+var (
+	ErrNotFound = failure.New(http.StatusNotFound, "comment.not_found", "comment not found")
+	// ErrTransitionNotAllowed is returned by moderation moving a comment to a status its own can't move to.
+	ErrTransitionNotAllowed = failure.New(http.StatusConflict, "comment.transition_not_allowed",
+		"comment can't be moved to this status")
+)
+
+type repository struct {
+	DB *sqlx.DB
+}
+
+func NewRepository(DB *sqlx.DB) *repository {
+	return &repository{DB: DB}
+}
+
+func (r *repository) CreateComment(ctx context.Context, comment *entities.Comment) (err error) {
+	query := "INSERT INTO `comments`(`id`, `news_id`, `parent_id`, `author`, `content`, `status`, `createdAt`) " +
+		"VALUES (:id, :news_id, :parent_id, :author, :content, :status, :createdAt)"
+	stmt, err := r.DB.PrepareNamedContext(ctx, database.Rebind(r.DB, query))
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
+		return
+	}
+	_, err = stmt.ExecContext(ctx, comment)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
+		return
+	}
+	return
+}
+
+func (r *repository) GetCommentByID(ctx context.Context, id string) (comment *entities.Comment, err error) {
+	comments, err := r.selectComment(ctx, "WHERE id = ?", id)
+	if err != nil {
+		return
+	}
+	comment = &(*comments)[0]
+	return
+}
+
+func (r *repository) GetCommentsByNewsID(ctx context.Context, newsID string, status entities.CommentStatus) (*entities.Comments, error) {
+	return r.selectComment(ctx, "WHERE news_id = ? AND status = ?", newsID, status)
+}
+
+func (r *repository) GetCommentsByStatus(ctx context.Context, status entities.CommentStatus) (*entities.Comments, error) {
+	return r.selectComment(ctx, "WHERE status = ?", status)
+}
+
+func (r *repository) UpdateCommentStatus(ctx context.Context, id string, status entities.CommentStatus) (comment *entities.Comment, err error) {
+	comment, err = r.GetCommentByID(ctx, id)
+	if err != nil {
+		return
+	}
+	comment.Status = status
+	_, err = r.DB.ExecContext(ctx, database.Rebind(r.DB, "UPDATE `comments` SET status = ? WHERE id = ?"), comment.Status, comment.ID)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
+		return
+	}
+	return
+}
+
+func (r *repository) selectComment(ctx context.Context, where string, args ...interface{}) (comments *entities.Comments, err error) {
+	comments = new(entities.Comments)
+	query := "SELECT `id`, `news_id`, `parent_id`, `author`, `content`, `status`, `createdAt` FROM `comments` " +
+		where + " ORDER BY `createdAt` asc"
+	err = r.DB.SelectContext(ctx, comments, database.Rebind(r.DB, query), args...)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
+		return
+	}
+	if len(*comments) < 1 {
+		err = ErrNotFound
+	}
+	return
+}
@@ -0,0 +1,120 @@
+package comment
+
+import (
+	"context"
+	"net/http"
+	"news/domain/entities"
+	"news/domain/news"
+	"news/shared/failure"
+	"news/shared/logger"
+)
+
+type Service interface {
+	Create(ctx context.Context, slug string, lang string, dto *entities.CreateComment) (*entities.CommentDto, error)
+	GetBySlug(ctx context.Context, slug string, lang string) (*[]entities.CommentDto, error)
+	GetModerationQueue(ctx context.Context) (*[]entities.CommentDto, error)
+	Moderate(ctx context.Context, id string, status entities.CommentStatus) (*entities.CommentDto, error)
+}
+
+type service struct {
+	repo      Repository
+	newsRepo  news.Repository
+	moderator Moderator
+	// newsCache holds the news with their comment count, it is invalidated when the approved comments change.
+	newsCache news.Cache
+}
+
+func NewService(repo Repository, newsRepo news.Repository, moderator Moderator, newsCache news.Cache) *service {
+	return &service{repo: repo, newsRepo: newsRepo, moderator: moderator, newsCache: newsCache}
+}
+
+func (s service) Create(ctx context.Context, slug string, lang string, dto *entities.CreateComment) (result *entities.CommentDto, err error) {
+	newsEntity, err := s.newsRepo.GetNewsBySlug(ctx, slug, lang)
+	if err != nil {
+		return
+	}
+
+	comment := dto.ToComment(newsEntity.ID)
+	if comment.IsReply() {
+		parent, errs := s.repo.GetCommentByID(ctx, *comment.ParentID)
+		if errs != nil || parent.NewsID != newsEntity.ID || parent.Status != entities.CommentApproved {
+			err = failure.BadRequestWithString("parent comment not found")
+			return
+		}
+		if parent.IsReply() {
+			err = failure.BadRequestWithString("can't reply to a reply")
+			return
+		}
+	}
+	comment.Status = s.moderator.Moderate(ctx, comment)
+
+	err = s.repo.CreateComment(ctx, comment)
+	if err != nil {
+		return
+	}
+	if comment.Status == entities.CommentApproved {
+		s.invalidate(ctx, newsEntity)
+	}
+	result = comment.ToDto()
+	return
+}
+
+func (s service) GetBySlug(ctx context.Context, slug string, lang string) (result *[]entities.CommentDto, err error) {
+	newsEntity, err := s.newsRepo.GetNewsBySlug(ctx, slug, lang)
+	if err != nil {
+		return
+	}
+
+	comments, err := s.repo.GetCommentsByNewsID(ctx, newsEntity.ID, entities.CommentApproved)
+	if err != nil {
+		if failure.GetStatus(err) == http.StatusNotFound {
+			return &[]entities.CommentDto{}, nil
+		}
+		return
+	}
+	result = comments.ToThreadDto()
+	return
+}
+
+func (s service) GetModerationQueue(ctx context.Context) (result *[]entities.CommentDto, err error) {
+	comments, err := s.repo.GetCommentsByStatus(ctx, entities.CommentPending)
+	if err != nil {
+		return
+	}
+	result = comments.ToCommentsDto()
+	return
+}
+
+func (s service) Moderate(ctx context.Context, id string, status entities.CommentStatus) (result *entities.CommentDto, err error) {
+	current, err := s.repo.GetCommentByID(ctx, id)
+	if err != nil {
+		return
+	}
+	if !current.CanMoveTo(status) {
+		err = ErrTransitionNotAllowed
+		return
+	}
+	comment, err := s.repo.UpdateCommentStatus(ctx, id, status)
+	if err != nil {
+		return
+	}
+	if current.Status == entities.CommentApproved || status == entities.CommentApproved {
+		newsEntity, errs := s.newsRepo.GetNewsByID(ctx, comment.NewsID)
+		if errs != nil {
+			logger.ErrorWithStack(ctx, errs)
+		} else {
+			s.invalidate(ctx, newsEntity)
+		}
+	}
+	result = comment.ToDto()
+	return
+}
+
+// invalidate drops the cached reads of newsEntity, their comment count changed.
+// It is best effort, the cache expires them anyway.
+func (s service) invalidate(ctx context.Context, newsEntity *entities.News) {
+	err := s.newsCache.Delete(ctx, news.CacheKeys(newsEntity)...)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+	}
+}
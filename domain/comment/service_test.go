@@ -0,0 +1,136 @@
+package comment_test
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"news/domain/comment"
+	comment_mock "news/domain/comment/mock"
+	"news/domain/entities"
+	"news/domain/news"
+	news_mock "news/domain/news/mock"
+	"testing"
+)
+
+func newNews() *entities.News {
+	return &entities.News{ID: "news1", Slug: "derby-day", Topic: "sport", Status: entities.NewsPublish,
+		Language: entities.DefaultLanguage}
+}
+
+func TestServiceCreate(t *testing.T) {
+	sliceTest := []struct {
+		testTitle   string
+		autoApprove bool
+		invalidated bool
+	}{
+		{testTitle: "pending comment keeps the cached news"},
+		{testTitle: "approved comment invalidates the cached news", autoApprove: true, invalidated: true},
+	}
+	for _, test := range sliceTest {
+		t.Run(test.testTitle, func(t *testing.T) {
+			ctx := context.Background()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := comment_mock.NewMockRepository(ctrl)
+			newsRepo := news_mock.NewMockRepository(ctrl)
+			cache := news_mock.NewMockCache(ctrl)
+			service := comment.NewService(repo, newsRepo, comment.NewWordListModerator(nil, test.autoApprove), cache)
+
+			newsRepo.EXPECT().GetNewsBySlug(ctx, "derby-day", "").Return(newNews(), nil)
+			repo.EXPECT().CreateComment(ctx, gomock.Any()).Return(nil)
+			if test.invalidated {
+				cache.EXPECT().Delete(ctx, news.CacheKeys(newNews())).Return(nil)
+			}
+			result, err := service.Create(ctx, "derby-day", "", &entities.CreateComment{Author: "budi", Content: "what a game"})
+			assert.Equal(t, err, nil)
+			assert.Equal(t, result.NewsID, "news1")
+		})
+	}
+}
+
+func TestServiceCreateReply(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := comment_mock.NewMockRepository(ctrl)
+	newsRepo := news_mock.NewMockRepository(ctrl)
+	service := comment.NewService(repo, newsRepo, comment.NewWordListModerator(nil, false),
+		news_mock.NewMockCache(ctrl))
+
+	parent, reply := "comment1", "comment2"
+	newsRepo.EXPECT().GetNewsBySlug(ctx, "derby-day", "").Return(newNews(), nil).Times(2)
+	repo.EXPECT().GetCommentByID(ctx, parent).Return(&entities.Comment{ID: parent, NewsID: "news1",
+		Status: entities.CommentPending}, nil)
+	_, err := service.Create(ctx, "derby-day", "", &entities.CreateComment{ParentID: parent, Author: "budi", Content: "yes"})
+	assert.Equal(t, err.Error(), "parent comment not found")
+
+	repo.EXPECT().GetCommentByID(ctx, reply).Return(&entities.Comment{ID: reply, NewsID: "news1", ParentID: &parent,
+		Status: entities.CommentApproved}, nil)
+	_, err = service.Create(ctx, "derby-day", "", &entities.CreateComment{ParentID: reply, Author: "budi", Content: "yes"})
+	assert.Equal(t, err.Error(), "can't reply to a reply")
+}
+
+func TestServiceModerate(t *testing.T) {
+	sliceTest := []struct {
+		testTitle   string
+		current     entities.CommentStatus
+		status      entities.CommentStatus
+		invalidated bool
+		err         error
+	}{
+		{testTitle: "approve a pending comment", current: entities.CommentPending, status: entities.CommentApproved,
+			invalidated: true},
+		{testTitle: "reject a pending comment", current: entities.CommentPending, status: entities.CommentRejected},
+		{testTitle: "take down an approved comment", current: entities.CommentApproved, status: entities.CommentSpam,
+			invalidated: true},
+		{testTitle: "approve a comment marked as spam", current: entities.CommentSpam, status: entities.CommentApproved,
+			invalidated: true},
+		{testTitle: "approve twice", current: entities.CommentApproved, status: entities.CommentApproved,
+			err: comment.ErrTransitionNotAllowed},
+		{testTitle: "mark a rejected comment as spam", current: entities.CommentRejected, status: entities.CommentSpam,
+			err: comment.ErrTransitionNotAllowed},
+		{testTitle: "back to pending", current: entities.CommentRejected, status: entities.CommentPending,
+			err: comment.ErrTransitionNotAllowed},
+	}
+	for _, test := range sliceTest {
+		t.Run(test.testTitle, func(t *testing.T) {
+			ctx := context.Background()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := comment_mock.NewMockRepository(ctrl)
+			newsRepo := news_mock.NewMockRepository(ctrl)
+			cache := news_mock.NewMockCache(ctrl)
+			service := comment.NewService(repo, newsRepo, comment.NewWordListModerator(nil, false), cache)
+
+			current := &entities.Comment{ID: "comment1", NewsID: "news1", Status: test.current}
+			repo.EXPECT().GetCommentByID(ctx, "comment1").Return(current, nil)
+			if test.err == nil {
+				moderated := *current
+				moderated.Status = test.status
+				repo.EXPECT().UpdateCommentStatus(ctx, "comment1", test.status).Return(&moderated, nil)
+			}
+			if test.invalidated {
+				newsRepo.EXPECT().GetNewsByID(ctx, "news1").Return(newNews(), nil)
+				cache.EXPECT().Delete(ctx, news.CacheKeys(newNews())).Return(nil)
+			}
+			result, err := service.Moderate(ctx, "comment1", test.status)
+			assert.Equal(t, err, test.err)
+			if test.err == nil {
+				assert.Equal(t, result.Status, test.status.String())
+			}
+		})
+	}
+}
+
+func TestServiceModerateNotFound(t *testing.T) {
+	ctx := context.Background()
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	repo := comment_mock.NewMockRepository(ctrl)
+	service := comment.NewService(repo, news_mock.NewMockRepository(ctrl), comment.NewWordListModerator(nil, false),
+		news_mock.NewMockCache(ctrl))
+
+	repo.EXPECT().GetCommentByID(ctx, "missing").Return(nil, comment.ErrNotFound)
+	_, err := service.Moderate(ctx, "missing", entities.CommentApproved)
+	assert.Equal(t, err, comment.ErrNotFound)
+}
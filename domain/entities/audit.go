@@ -0,0 +1,87 @@
+package entities
+
+import (
+	"news/shared/Date"
+	"news/shared/IDGEN"
+	"time"
+)
+
+const (
+	AuditCreate = "create"
+	AuditUpdate = "update"
+	AuditDelete = "delete"
+)
+
+const (
+	AuditNews = "news"
+	AuditTag  = "tag"
+)
+
+// AuditEntities are the entity types written to the audit log.
+var AuditEntities = []string{AuditNews, AuditTag}
+
+// AuditEntry records one write, Before and After are JSON snapshots of the entity
+// and nil when it did not exist before or after the write.
+type AuditEntry struct {
+	ID         string    `db:"id"`
+	Actor      string    `db:"actor"`
+	Action     string    `db:"action"`
+	EntityType string    `db:"entity_type"`
+	EntityID   string    `db:"entity_id"`
+	Before     *string   `db:"before_snapshot"`
+	After      *string   `db:"after_snapshot"`
+	IP         string    `db:"ip"`
+	RequestID  string    `db:"request_id"`
+	CreatedAt  time.Time `db:"createdAt"`
+}
+
+func NewAuditEntry(actor string, action string, entityType string, entityID string, before *string, after *string,
+	ip string, requestID string) *AuditEntry {
+	return &AuditEntry{ID: IDGEN.NewUUID(), Actor: actor, Action: action, EntityType: entityType, EntityID: entityID,
+		Before: before, After: after, IP: ip, RequestID: requestID, CreatedAt: Date.Now()}
+}
+
+func (a *AuditEntry) ToDto() *AuditEntryDto {
+	return &AuditEntryDto{
+		ID:         a.ID,
+		Actor:      a.Actor,
+		Action:     a.Action,
+		EntityType: a.EntityType,
+		EntityID:   a.EntityID,
+		Before:     rawSnapshot(a.Before),
+		After:      rawSnapshot(a.After),
+		IP:         a.IP,
+		RequestID:  a.RequestID,
+		CreatedAt:  a.CreatedAt,
+	}
+}
+
+type AuditEntries []AuditEntry
+
+func (a AuditEntries) ToAuditEntriesDto() *[]AuditEntryDto {
+	result := []AuditEntryDto{}
+	for _, entry := range a {
+		result = append(result, *entry.ToDto())
+	}
+	return &result
+}
+
+// AuditFilter selects audit entries, empty fields match everything. From is
+// inclusive and To exclusive.
+type AuditFilter struct {
+	EntityType string
+	EntityID   string
+	Actor      string
+	From       *time.Time
+	To         *time.Time
+	Limit      int
+}
+
+// Match reports whether the entry is selected by the filter, ignoring Limit.
+func (f *AuditFilter) Match(entry AuditEntry) bool {
+	return (f.EntityType == "" || entry.EntityType == f.EntityType) &&
+		(f.EntityID == "" || entry.EntityID == f.EntityID) &&
+		(f.Actor == "" || entry.Actor == f.Actor) &&
+		(f.From == nil || !entry.CreatedAt.Before(*f.From)) &&
+		(f.To == nil || entry.CreatedAt.Before(*f.To))
+}
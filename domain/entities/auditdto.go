@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"encoding/json"
+	"news/shared/failure"
+	"time"
+)
+
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+type AuditEntryDto struct {
+	ID         string          `json:"id"`
+	Actor      string          `json:"actor"`
+	Action     string          `json:"action"`
+	EntityType string          `json:"entity_type"`
+	EntityID   string          `json:"entity_id"`
+	Before     json.RawMessage `json:"before"`
+	After      json.RawMessage `json:"after"`
+	IP         string          `json:"ip"`
+	RequestID  string          `json:"request_id"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+func rawSnapshot(snapshot *string) json.RawMessage {
+	if snapshot == nil {
+		return json.RawMessage("null")
+	}
+	return json.RawMessage(*snapshot)
+}
+
+// AuditQuery is the query string of the audit endpoint, from and to are RFC 3339 times.
+type AuditQuery struct {
+	EntityType string `query:"entity_type"`
+	EntityID   string `query:"entity_id"`
+	Actor      string `query:"actor"`
+	From       string `query:"from"`
+	To         string `query:"to"`
+	Limit      int    `query:"limit"`
+}
+
+func (q *AuditQuery) ToFilter() (*AuditFilter, error) {
+	var violations []failure.Violation
+	filter := &AuditFilter{EntityType: q.EntityType, EntityID: q.EntityID, Actor: q.Actor, Limit: q.Limit}
+	if q.EntityType != "" && !contains(AuditEntities, q.EntityType) {
+		violations = append(violations, failure.Violation{Field: "entity_type", Message: "entity_type not valid"})
+	}
+	filter.From = parseAuditTime(q.From, "from", &violations)
+	filter.To = parseAuditTime(q.To, "to", &violations)
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		violations = append(violations, failure.Violation{Field: "from", Message: "from must be before to"})
+	}
+	if q.Limit < 0 || q.Limit > maxAuditLimit {
+		violations = append(violations, failure.Violation{Field: "limit", Message: "limit not valid"})
+	}
+	if len(violations) > 0 {
+		return nil, failure.Validation(violations)
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	return filter, nil
+}
+
+func parseAuditTime(value string, name string, violations *[]failure.Violation) *time.Time {
+	if value == "" {
+		return nil
+	}
+	result, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		*violations = append(*violations, failure.Violation{Field: name, Message: name + " not valid"})
+		return nil
+	}
+	return &result
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,128 @@
+package entities
+
+import (
+	"news/shared/Date"
+	"news/shared/IDGEN"
+	"news/shared/failure"
+	"time"
+)
+
+type CommentStatus int
+
+const (
+	CommentPending CommentStatus = iota + 1
+	CommentApproved
+	CommentRejected
+	CommentSpam
+)
+
+func StringToCommentStatus(status string) (CommentStatus, error) {
+	mapStatus := map[string]CommentStatus{
+		"pending":  CommentPending,
+		"approved": CommentApproved,
+		"rejected": CommentRejected,
+		"spam":     CommentSpam,
+	}
+	if v, ok := mapStatus[status]; ok {
+		return v, nil
+	}
+	return 0, failure.NotFound("status not found")
+}
+
+func (c CommentStatus) String() string {
+	stringer := []string{"not found", "pending", "approved", "rejected", "spam"}
+	return stringer[c]
+}
+
+type Comment struct {
+	ID        string        `db:"id"`
+	NewsID    string        `db:"news_id"`
+	ParentID  *string       `db:"parent_id"`
+	Author    string        `db:"author"`
+	Content   string        `db:"content"`
+	Status    CommentStatus `db:"status"`
+	CreatedAt time.Time     `db:"createdAt"`
+}
+
+func NewComment(newsID string, parentID *string, author string, content string) *Comment {
+	return &Comment{
+		ID:        IDGEN.NewUUID(),
+		NewsID:    newsID,
+		ParentID:  parentID,
+		Author:    author,
+		Content:   content,
+		Status:    CommentPending,
+		CreatedAt: Date.Now(),
+	}
+}
+
+func (c *Comment) IsReply() bool {
+	return c.ParentID != nil && *c.ParentID != ""
+}
+
+// commentTransitions are the statuses moderation can move a comment to from each status.
+var commentTransitions = map[CommentStatus][]CommentStatus{
+	CommentPending:  {CommentApproved, CommentRejected, CommentSpam},
+	CommentApproved: {CommentRejected, CommentSpam},
+	CommentRejected: {CommentApproved},
+	CommentSpam:     {CommentApproved, CommentRejected},
+}
+
+// CanMoveTo reports whether moderation can move the comment to status, a
+// comment never goes back to pending.
+func (c *Comment) CanMoveTo(status CommentStatus) bool {
+	for _, allowed := range commentTransitions[c.Status] {
+		if allowed == status {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Comment) ToDto() *CommentDto {
+	res := CommentDto{
+		ID:        c.ID,
+		NewsID:    c.NewsID,
+		Author:    c.Author,
+		Content:   c.Content,
+		Status:    c.Status.String(),
+		CreatedAt: c.CreatedAt,
+	}
+	if c.IsReply() {
+		res.ParentID = *c.ParentID
+	}
+	return &res
+}
+
+type Comments []Comment
+
+func (c Comments) ToCommentsDto() *[]CommentDto {
+	result := []CommentDto{}
+	for _, comment := range c {
+		result = append(result, *comment.ToDto())
+	}
+	return &result
+}
+
+// ToThreadDto nests replies under their parent comment. Only one level of
+// replies is supported, replies whose parent is not in the slice are dropped.
+func (c Comments) ToThreadDto() *[]CommentDto {
+	result := []CommentDto{}
+	index := map[string]int{}
+	for _, comment := range c {
+		if comment.IsReply() {
+			continue
+		}
+		index[comment.ID] = len(result)
+		result = append(result, *comment.ToDto())
+	}
+	for _, comment := range c {
+		if !comment.IsReply() {
+			continue
+		}
+		if i, ok := index[*comment.ParentID]; ok {
+			result[i].Replies = append(result[i].Replies, *comment.ToDto())
+		}
+	}
+	return &result
+}
@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"news/shared/failure"
+	"time"
+)
+
+type CommentDto struct {
+	ID        string       `json:"id"`
+	NewsID    string       `json:"news_id"`
+	ParentID  string       `json:"parent_id,omitempty"`
+	Author    string       `json:"author"`
+	Content   string       `json:"content"`
+	Status    string       `json:"status"`
+	CreatedAt time.Time    `json:"created_at"`
+	Replies   []CommentDto `json:"replies,omitempty"`
+}
+
+type CreateComment struct {
+	ParentID string `json:"parent_id"`
+	Author   string `json:"author"`
+	Content  string `json:"content"`
+}
+
+func (c *CreateComment) Validate() error {
+	var violations []failure.Violation
+	if c.Author == "" {
+		violations = append(violations, failure.Violation{Field: "author", Message: "author can't be null"})
+	}
+	if c.Content == "" {
+		violations = append(violations, failure.Violation{Field: "content", Message: "content can't be null"})
+	}
+	if len(violations) > 0 {
+		return failure.Validation(violations)
+	}
+	return nil
+}
+
+func (c *CreateComment) ToComment(newsID string) *Comment {
+	var parentID *string
+	if c.ParentID != "" {
+		parentID = &c.ParentID
+	}
+	return NewComment(newsID, parentID, c.Author, c.Content)
+}
@@ -0,0 +1,46 @@
+package entities
+
+import (
+	"news/shared/failure"
+	"news/shared/markup"
+)
+
+type ContentFormat int
+
+const (
+	ContentPlain ContentFormat = iota + 1
+	ContentMarkdown
+	ContentHTML
+)
+
+func StringToContentFormat(format string) (ContentFormat, error) {
+	mapFormat := map[string]ContentFormat{
+		"plain":    ContentPlain,
+		"markdown": ContentMarkdown,
+		"html":     ContentHTML,
+	}
+	if v, ok := mapFormat[format]; ok {
+		return v, nil
+	}
+	return 0, failure.BadRequestWithString("content format not valid")
+}
+
+// String reports an unset format as plain, news stored before formats existed are plain text.
+func (c ContentFormat) String() string {
+	stringer := []string{"plain", "plain", "markdown", "html"}
+	return stringer[c]
+}
+
+// Render returns content as sanitized HTML, inline `media:<id>` references are
+// replaced with the media url first so they survive sanitizing.
+func (c ContentFormat) Render(content string) string {
+	content = mediaReference.ReplaceAllString(content, MediaURLPrefix+"$1")
+	switch c {
+	case ContentMarkdown:
+		return markup.Markdown(content)
+	case ContentHTML:
+		return markup.HTML(content)
+	default:
+		return markup.Plain(content)
+	}
+}
@@ -0,0 +1,131 @@
+package entities
+
+import (
+	"encoding/json"
+	"net/http"
+	"news/shared/Date"
+	"news/shared/IDGEN"
+	"news/shared/failure"
+	"time"
+)
+
+type EventType string
+
+const (
+	EventNewsCreated     EventType = "NewsCreated"
+	EventNewsUpdated     EventType = "NewsUpdated"
+	EventNewsPublished   EventType = "NewsPublished"
+	EventNewsUnpublished EventType = "NewsUnpublished"
+	EventNewsDeleted     EventType = "NewsDeleted"
+	EventTagCreated      EventType = "TagCreated"
+	EventTagRenamed      EventType = "TagRenamed"
+	EventTagDeleted      EventType = "TagDeleted"
+)
+
+// EventTypes lists every event the repositories write.
+var EventTypes = []EventType{EventNewsCreated, EventNewsUpdated, EventNewsPublished, EventNewsUnpublished,
+	EventNewsDeleted, EventTagCreated, EventTagRenamed, EventTagDeleted}
+
+// ErrEventTypeNotFound is returned for a name that isn't one of EventTypes.
+var ErrEventTypeNotFound = failure.New(http.StatusNotFound, "event.type_not_found", "event type not found")
+
+func StringToEventType(eventType string) (EventType, error) {
+	for _, v := range EventTypes {
+		if string(v) == eventType {
+			return v, nil
+		}
+	}
+	return "", ErrEventTypeNotFound
+}
+
+// Event is a domain event waiting in the outbox, Sequence orders the events
+// in the order they were written.
+type Event struct {
+	Sequence    int64      `db:"sequence"`
+	ID          string     `db:"id"`
+	Type        EventType  `db:"type"`
+	AggregateID string     `db:"aggregate_id"`
+	Payload     string     `db:"payload"`
+	Attempts    int        `db:"attempts"`
+	LockedUntil *time.Time `db:"lockedUntil"`
+	CreatedAt   time.Time  `db:"createdAt"`
+	PublishedAt *time.Time `db:"publishedAt"`
+	// FailedAt is set on the events the relay gave up on, they are never published.
+	FailedAt *time.Time `db:"failedAt"`
+}
+
+func NewEvent(eventType EventType, aggregateID string, payload interface{}) (*Event, error) {
+	value, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	return &Event{ID: IDGEN.NewUUID(), Type: eventType, AggregateID: aggregateID, Payload: string(value),
+		CreatedAt: Date.Now()}, nil
+}
+
+func (e *Event) ToDto() *EventDto {
+	return &EventDto{
+		ID:          e.ID,
+		Type:        string(e.Type),
+		AggregateID: e.AggregateID,
+		Payload:     json.RawMessage(e.Payload),
+		OccurredAt:  e.CreatedAt,
+	}
+}
+
+type Events []Event
+
+// NewsEvents returns the events of storing news over old, old is nil when news is new.
+func NewsEvents(old *News, news *News) (events Events, err error) {
+	var types []EventType
+	switch {
+	case old == nil:
+		types = append(types, EventNewsCreated)
+		if news.Status == NewsPublish {
+			types = append(types, EventNewsPublished)
+		}
+	case news.Status == NewsDeleted && old.Status != NewsDeleted:
+		types = append(types, EventNewsDeleted)
+	default:
+		types = append(types, EventNewsUpdated)
+		if news.Status == NewsPublish && old.Status != NewsPublish {
+			types = append(types, EventNewsPublished)
+		}
+		if news.Status != NewsPublish && old.Status == NewsPublish {
+			types = append(types, EventNewsUnpublished)
+		}
+	}
+	payload := news.ToNewsDto()
+	for _, eventType := range types {
+		event, errs := NewEvent(eventType, news.ID, payload)
+		if errs != nil {
+			return nil, errs
+		}
+		events = append(events, *event)
+	}
+	return
+}
+
+// TagRenamedPayload is the payload of EventTagRenamed.
+type TagRenamedPayload struct {
+	TagDto
+	PreviousName string `json:"previous_name"`
+}
+
+// TagEvents returns the events of storing tag over old, old is nil when tag is new.
+// Updates that keep the name and status have no event.
+func TagEvents(old *Tag, tag *Tag) (events Events, err error) {
+	var event *Event
+	switch {
+	case old == nil:
+		event, err = NewEvent(EventTagCreated, tag.ID, tag.ToDto())
+	case tag.Status == TagDelete && old.Status != TagDelete:
+		event, err = NewEvent(EventTagDeleted, tag.ID, tag.ToDto())
+	case tag.Name != old.Name:
+		event, err = NewEvent(EventTagRenamed, tag.ID, TagRenamedPayload{TagDto: *tag.ToDto(), PreviousName: old.Name})
+	}
+	if event != nil {
+		events = append(events, *event)
+	}
+	return
+}
@@ -0,0 +1,15 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventDto is the message publishers send, Payload holds the news or tag as the API returns it.
+type EventDto struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	AggregateID string          `json:"aggregate_id"`
+	Payload     json.RawMessage `json:"payload"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+}
@@ -0,0 +1,19 @@
+package entities
+
+import "news/shared/failure"
+
+// DefaultLanguage is used for news created without a language.
+const DefaultLanguage = "id"
+
+var languages = map[string]bool{
+	"id": true,
+	"en": true,
+}
+
+// ValidateLanguage accepts an empty language, which means no language filter.
+func ValidateLanguage(lang string) error {
+	if lang != "" && !languages[lang] {
+		return failure.BadRequestWithString("language not valid")
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package entities
+
+import (
+	"news/shared/Date"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MediaURLPrefix is the public path media files are served from.
+var MediaURLPrefix = "/api/v1/media/"
+
+var mediaReference = regexp.MustCompile(`media:([0-9a-f]{64})`)
+
+// MediaReferences returns the ids of media referenced inline with `media:<id>`.
+func MediaReferences(content string) (ids []string) {
+	duplication := map[string]bool{}
+	for _, match := range mediaReference.FindAllStringSubmatch(content, -1) {
+		if duplication[match[1]] {
+			continue
+		}
+		duplication[match[1]] = true
+		ids = append(ids, match[1])
+	}
+	return
+}
+
+type Media struct {
+	ID         string    `db:"id"`
+	Name       string    `db:"name"`
+	Path       string    `db:"path"`
+	MimeType   string    `db:"mime_type"`
+	Size       int64     `db:"size"`
+	Width      int       `db:"width"`
+	Height     int       `db:"height"`
+	Thumbnails string    `db:"thumbnails"`
+	CreatedAt  time.Time `db:"createdAt"`
+}
+
+func NewMedia(id string, name string, path string, mimeType string, size int64) *Media {
+	return &Media{ID: id, Name: name, Path: path, MimeType: mimeType, Size: size, CreatedAt: Date.Now()}
+}
+
+func (m *Media) ThumbnailSizes() []string {
+	if m.Thumbnails == "" {
+		return nil
+	}
+	return strings.Split(m.Thumbnails, ",")
+}
+
+func (m *Media) HasThumbnail(size string) bool {
+	for _, v := range m.ThumbnailSizes() {
+		if v == size {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Media) ToDto() *MediaDto {
+	res := MediaDto{
+		ID:       m.ID,
+		Name:     m.Name,
+		URL:      MediaURLPrefix + m.ID,
+		MimeType: m.MimeType,
+		Size:     m.Size,
+		Width:    m.Width,
+		Height:   m.Height,
+	}
+	for _, size := range m.ThumbnailSizes() {
+		if res.Thumbnails == nil {
+			res.Thumbnails = map[string]string{}
+		}
+		res.Thumbnails[size] = MediaURLPrefix + m.ID + "/thumbnails/" + size
+	}
+	return &res
+}
+
+type SliceMedia []Media
+
+func (s SliceMedia) ToMapMediaDto() map[string]MediaDto {
+	result := map[string]MediaDto{}
+	for _, media := range s {
+		result[media.ID] = *media.ToDto()
+	}
+	return result
+}
@@ -0,0 +1,12 @@
+package entities
+
+type MediaDto struct {
+	ID         string            `json:"id"`
+	Name       string            `json:"name"`
+	URL        string            `json:"url"`
+	MimeType   string            `json:"mime_type"`
+	Size       int64             `json:"size"`
+	Width      int               `json:"width,omitempty"`
+	Height     int               `json:"height,omitempty"`
+	Thumbnails map[string]string `json:"thumbnails,omitempty"`
+}
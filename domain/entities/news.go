@@ -34,15 +34,26 @@ func (n NewsStatus) String() string {
 }
 
 type News struct {
-	ID        string     `db:"id"`
-	Title     string     `db:"title"`
-	Slug      string     `db:"slug"`
-	Content   string     `db:"content"`
-	Status    NewsStatus `db:"status"`
-	Tags      []string
-	Topic     string     `db:"topic"`
-	CreatedAt time.Time  `db:"createdAt"`
-	DeletedAt *time.Time `db:"deletedAt"`
+	ID                 string        `db:"id"`
+	Title              string        `db:"title"`
+	Slug               string        `db:"slug"`
+	Content            string        `db:"content"`
+	ContentFormat      ContentFormat `db:"content_format"`
+	Excerpt            string        `db:"excerpt"`
+	WordCount          int           `db:"word_count"`
+	ReadingTimeMinutes int           `db:"reading_time_minutes"`
+	Status             NewsStatus    `db:"status"`
+	// Version starts at 1 and is incremented by every write.
+	Version            int `db:"version"`
+	Tags               []string
+	Topic              string     `db:"topic"`
+	Language           string     `db:"language"`
+	TranslationGroupID string     `db:"translation_group_id"`
+	FeaturedImageID    *string    `db:"featured_image_id"`
+	CommentCount       int        `db:"commentCount"`
+	CreatedAt          time.Time  `db:"createdAt"`
+	UpdatedAt          time.Time  `db:"updatedAt"`
+	DeletedAt          *time.Time `db:"deletedAt"`
 }
 
 func NewNews(id string, title string, slug string, content string, status NewsStatus, tags []string, topic string) *News {
@@ -52,7 +63,20 @@ func NewNews(id string, title string, slug string, content string, status NewsSt
 	if id == "" {
 		id = IDGEN.NewUUID()
 	}
-	return &News{ID: id, Title: title, Slug: slug, Content: content, Status: status, Tags: tags, Topic: topic, CreatedAt: Date.Now()}
+	now := Date.Now()
+	return &News{ID: id, Title: title, Slug: slug, Content: content, Status: status, Tags: tags, Topic: topic,
+		Version: 1, CreatedAt: now, UpdatedAt: now}
+}
+
+// SetLanguageDefaults puts news created without a language in DefaultLanguage,
+// and starts a new translation group when it isn't a translation of another news.
+func (n *News) SetLanguageDefaults() {
+	if n.Language == "" {
+		n.Language = DefaultLanguage
+	}
+	if n.TranslationGroupID == "" {
+		n.TranslationGroupID = n.ID
+	}
 }
 
 func (n *News) ToSliceNewsTag() (sliceNewsTag []NewsTag) {
@@ -62,34 +86,32 @@ func (n *News) ToSliceNewsTag() (sliceNewsTag []NewsTag) {
 	return
 }
 
+// Update replaces the news with new, fields left empty in new are cleared. The
+// id, slug, creation time, comment count and deletion time are kept.
 func (n *News) Update(new News) {
-	if new.Title != "" {
-		n.Title = new.Title
-	}
-	if new.Slug != "" {
-		n.Slug = new.Slug
-	}
-	if new.Content != "" {
-		n.Content = new.Content
-	}
-	if new.Status > 0 {
-		n.Status = new.Status
-	}
-	if new.Topic != "" {
-		n.Topic = new.Topic
-	}
-	if len(new.Tags) > 0 {
-		n.Tags = new.Tags
-	}
+	n.Title = new.Title
+	n.Content = new.Content
+	n.ContentFormat = new.ContentFormat
+	n.Status = new.Status
+	n.Topic = new.Topic
+	n.Tags = new.Tags
+	n.Language = new.Language
+	n.TranslationGroupID = new.TranslationGroupID
+	n.FeaturedImageID = new.FeaturedImageID
 	if new.DeletedAt != nil {
 		n.DeletedAt = new.DeletedAt
 	}
+	n.Summarize()
+	n.Version++
+	n.UpdatedAt = Date.Now()
 }
 
 func (n *News) Delete() {
 	n.Status = NewsDeleted
 	now := Date.Now()
 	n.DeletedAt = &now
+	n.Version++
+	n.UpdatedAt = now
 }
 
 func (n *News) ToNewsDto(tagsMap ...map[string]Tag) *NewsDto {
@@ -103,13 +125,26 @@ func (n *News) ToNewsDto(tagsMap ...map[string]Tag) *NewsDto {
 		}
 	}
 	res := NewsDto{
-		ID:      n.ID,
-		Title:   n.Title,
-		Slug:    n.Slug,
-		Content: n.Content,
-		Topic:   n.Topic,
-		Status:  n.Status.String(),
-		Tags:    tags,
+		ID:                 n.ID,
+		ContentFormat:      n.ContentFormat.String(),
+		Title:              n.Title,
+		Slug:               n.Slug,
+		Content:            n.Content,
+		ContentHTML:        n.ContentFormat.Render(n.Content),
+		Excerpt:            n.Excerpt,
+		WordCount:          n.WordCount,
+		ReadingTimeMinutes: n.ReadingTimeMinutes,
+		Topic:              n.Topic,
+		Status:             n.Status.String(),
+		Tags:               tags,
+		Language:           n.Language,
+		TranslationGroupID: n.TranslationGroupID,
+		CommentCount:       n.CommentCount,
+		Version:            n.Version,
+		UpdatedAt:          n.UpdatedAt,
+	}
+	if n.FeaturedImageID != nil {
+		res.FeaturedImageID = *n.FeaturedImageID
 	}
 	return &res
 }
@@ -0,0 +1,35 @@
+package entities
+
+import (
+	"news/shared/failure"
+	"time"
+)
+
+// NewsRecord is a news in import and export files, its tags are written by name.
+type NewsRecord struct {
+	NewsDto
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (n *News) ToNewsRecord() *NewsRecord {
+	return &NewsRecord{NewsDto: *n.ToNewsDto(), CreatedAt: n.CreatedAt}
+}
+
+type ImportError struct {
+	Row   int    `json:"row"`
+	Error string `json:"error"`
+}
+
+type ImportReport struct {
+	DryRun   bool          `json:"dry_run"`
+	Total    int           `json:"total"`
+	Imported int           `json:"imported"`
+	Failed   int           `json:"failed"`
+	Errors   []ImportError `json:"errors"`
+}
+
+// AddError records the failure of row, with the message a client may see.
+func (r *ImportReport) AddError(row int, err error) {
+	r.Failed++
+	r.Errors = append(r.Errors, ImportError{Row: row, Error: failure.As(err).Message})
+}
@@ -0,0 +1,20 @@
+package entities
+
+import (
+	"news/shared/markup"
+	"strings"
+)
+
+const (
+	excerptLength  = 200
+	wordsPerMinute = 200
+)
+
+// Summarize computes the excerpt, word count and reading time from the
+// rendered content, so markup never leaks into the summary.
+func (n *News) Summarize() {
+	text := markup.Text(n.ContentFormat.Render(n.Content))
+	n.Excerpt = markup.Excerpt(text, excerptLength)
+	n.WordCount = len(strings.Fields(text))
+	n.ReadingTimeMinutes = (n.WordCount + wordsPerMinute - 1) / wordsPerMinute
+}
@@ -45,6 +45,8 @@ func TestNews(t *testing.T) {
 					Status:    entities.NewsDraft,
 					Tags:      []string{"tags1", "tags2"},
 					CreatedAt: mockTime,
+					Version:   1,
+					UpdatedAt: mockTime,
 				},
 			},
 			{
@@ -64,6 +66,8 @@ func TestNews(t *testing.T) {
 					Status:    entities.NewsPublish,
 					Tags:      []string{"tags1", "tags2"},
 					CreatedAt: mockTime,
+					Version:   1,
+					UpdatedAt: mockTime,
 				},
 			},
 			{
@@ -83,6 +87,8 @@ func TestNews(t *testing.T) {
 					Status:    entities.NewsDeleted,
 					Tags:      []string{"tags1", "tags2"},
 					CreatedAt: mockTime,
+					Version:   1,
+					UpdatedAt: mockTime,
 				},
 			},
 		}
@@ -160,6 +166,11 @@ func TestNews(t *testing.T) {
 	})
 
 	t.Run("testNewsUpdate", func(t *testing.T) {
+		mockTime := time.Now()
+		Date.Now = func() time.Time {
+			return mockTime
+		}
+		featuredImage := "media1"
 		sliceTest := []struct {
 			testTitle string
 			input     entities.News
@@ -184,33 +195,51 @@ func TestNews(t *testing.T) {
 					Tags:    []string{"tags update", "tags2 update"},
 				},
 				expected: entities.News{
-					ID:      "id",
-					Title:   "title update",
-					Content: "content update",
-					Topic:   "topic update",
-					Status:  entities.NewsPublish,
-					Tags:    []string{"tags update", "tags2 update"},
+					ID:                 "id",
+					Title:              "title update",
+					Content:            "content update",
+					Excerpt:            "content update",
+					WordCount:          2,
+					ReadingTimeMinutes: 1,
+					Topic:              "topic update",
+					Status:             entities.NewsPublish,
+					Tags:               []string{"tags update", "tags2 update"},
+					Version:            1,
+					UpdatedAt:          mockTime,
 				},
 			}, {
-				testTitle: "delete",
+				testTitle: "clears fields left empty",
 				input: entities.News{
-					ID:      "id",
-					Title:   "title",
-					Content: "content",
-					Topic:   "topic",
-					Status:  entities.NewsDraft,
-					Tags:    []string{"idtags1", "idtags2"},
+					ID:              "id",
+					Title:           "title",
+					Slug:            "title",
+					Content:         "content",
+					Topic:           "topic",
+					Status:          entities.NewsDraft,
+					Tags:            []string{"idtags1", "idtags2"},
+					FeaturedImageID: &featuredImage,
+					CommentCount:    2,
 				},
 				input2: entities.News{
-					Status: entities.NewsDeleted,
-				},
-				expected: entities.News{
-					ID:      "id",
 					Title:   "title",
+					Slug:    "other-slug",
 					Content: "content",
 					Topic:   "topic",
 					Status:  entities.NewsDeleted,
-					Tags:    []string{"idtags1", "idtags2"},
+				},
+				expected: entities.News{
+					ID:                 "id",
+					Title:              "title",
+					Slug:               "title",
+					Content:            "content",
+					Excerpt:            "content",
+					WordCount:          1,
+					ReadingTimeMinutes: 1,
+					Topic:              "topic",
+					Status:             entities.NewsDeleted,
+					CommentCount:       2,
+					Version:            1,
+					UpdatedAt:          mockTime,
 				},
 			},
 		}
@@ -251,6 +280,8 @@ func TestNews(t *testing.T) {
 					Status:    entities.NewsDeleted,
 					Tags:      []string{"idtags1", "idtags2"},
 					DeletedAt: &mockTime,
+					Version:   1,
+					UpdatedAt: mockTime,
 				},
 			},
 		}
@@ -294,13 +325,15 @@ func TestNews(t *testing.T) {
 					},
 				},
 				expected: &entities.NewsDto{
-					ID:      "id",
-					Title:   "title",
-					Slug:    "title",
-					Content: "content",
-					Topic:   "topic",
-					Status:  "draft",
-					Tags:    []string{"tag 1", "tag 2"},
+					ID:            "id",
+					Title:         "title",
+					Slug:          "title",
+					Content:       "content",
+					ContentFormat: "plain",
+					ContentHTML:   "<p>content</p>\n",
+					Topic:         "topic",
+					Status:        "draft",
+					Tags:          []string{"tag 1", "tag 2"},
 				},
 			},
 		}
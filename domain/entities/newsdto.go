@@ -2,42 +2,81 @@ package entities
 
 import (
 	"news/shared/failure"
-	"strings"
+	"time"
 )
 
 type NewsDto struct {
-	ID      string   `json:"id"`
-	Title   string   `json:"title"`
-	Slug    string   `json:"slug"`
-	Content string   `json:"content"`
-	Status  string   `json:"status"`
-	Tags    []string `json:"tags"`
-	Topic   string   `json:"topic"`
+	ID                 string   `json:"id"`
+	Title              string   `json:"title"`
+	Slug               string   `json:"slug"`
+	Content            string   `json:"content,omitempty"`
+	ContentFormat      string   `json:"content_format"`
+	ContentHTML        string   `json:"content_html,omitempty"`
+	Excerpt            string   `json:"excerpt"`
+	WordCount          int      `json:"word_count"`
+	ReadingTimeMinutes int      `json:"reading_time_minutes"`
+	Status             string   `json:"status"`
+	Tags               []string `json:"tags"`
+	Topic              string   `json:"topic"`
+	Language           string   `json:"language"`
+	TranslationGroupID string   `json:"translation_group_id"`
+	CommentCount       int      `json:"comment_count"`
+	// Version is the stored version in responses, and the version an update
+	// expects to replace in requests, 0 replaces any.
+	Version int `json:"version"`
+	// UpdatedAt is the time of the last write, it is never read from requests.
+	UpdatedAt time.Time `json:"updated_at"`
+
+	FeaturedImageID string     `json:"featured_image_id"`
+	FeaturedImage   *MediaDto  `json:"featured_image,omitempty"`
+	Media           []MediaDto `json:"media,omitempty"`
 }
 
 func (n *NewsDto) Validate() error {
-	var errString []string
+	return validation(n.violations())
+}
+
+// ValidateCreate is Validate for a new news, which also needs tags. An update
+// may leave a news without tags.
+func (n *NewsDto) ValidateCreate() error {
+	violations := n.violations()
+	if len(n.Tags) < 1 {
+		violations = append(violations, failure.Violation{Field: "tags", Message: "please insert tags"})
+	}
+	return validation(violations)
+}
+
+func (n *NewsDto) violations() (violations []failure.Violation) {
 	if n.Title == "" {
-		errString = append(errString, "title can't be null")
+		violations = append(violations, failure.Violation{Field: "title", Message: "title can't be null"})
 	}
 	if n.Content == "" {
-		errString = append(errString, "content can't be null")
+		violations = append(violations, failure.Violation{Field: "content", Message: "content can't be null"})
+	}
+	if n.ContentFormat != "" {
+		if _, err := StringToContentFormat(n.ContentFormat); err != nil {
+			violations = append(violations, failure.Violation{Field: "content_format", Message: "content format not valid"})
+		}
 	}
 	if n.Status == "" {
-		errString = append(errString, "status can't be null")
+		violations = append(violations, failure.Violation{Field: "status", Message: "status can't be null"})
 	}
 	_, err := StringToNewsStatus(n.Status)
 	if err != nil {
-		errString = append(errString, "status not valid")
-	}
-	if len(n.Tags) < 1 {
-		errString = append(errString, "please insert tags")
+		violations = append(violations, failure.Violation{Field: "status", Message: "status not valid"})
 	}
 	if n.Topic == "" {
-		errString = append(errString, "topic can't be null")
+		violations = append(violations, failure.Violation{Field: "topic", Message: "topic can't be null"})
 	}
-	if len(errString) > 0 {
-		return failure.BadRequestWithString(strings.Join(errString, ", "))
+	if ValidateLanguage(n.Language) != nil {
+		violations = append(violations, failure.Violation{Field: "language", Message: "language not valid"})
+	}
+	return
+}
+
+func validation(violations []failure.Violation) error {
+	if len(violations) > 0 {
+		return failure.Validation(violations)
 	}
 	return nil
 }
@@ -50,8 +89,79 @@ func (n *NewsDto) ToNews() (news *News, err error) {
 	if err != nil {
 		return
 	}
+	format := ContentPlain
+	if n.ContentFormat != "" {
+		format, err = StringToContentFormat(n.ContentFormat)
+	}
+	if err != nil {
+		return
+	}
 	news = NewNews(n.ID, n.Title, n.Slug, n.Content, status, n.Tags, n.Topic)
+	news.ContentFormat = format
+	news.Language = n.Language
+	news.TranslationGroupID = n.TranslationGroupID
+	news.Summarize()
+	if n.FeaturedImageID != "" {
+		news.FeaturedImageID = &n.FeaturedImageID
+	}
 	return
 }
 
+// MediaIds returns the featured image id and the ids referenced inline in content.
+func (n *NewsDto) MediaIds() (ids []string) {
+	if n.FeaturedImageID != "" {
+		ids = append(ids, n.FeaturedImageID)
+	}
+	for _, id := range MediaReferences(n.Content) {
+		if id != n.FeaturedImageID {
+			ids = append(ids, id)
+		}
+	}
+	return
+}
+
+func (n *NewsDto) LinkMedia(mapMedia map[string]MediaDto) {
+	if media, ok := mapMedia[n.FeaturedImageID]; ok {
+		n.FeaturedImage = &media
+	}
+	n.Media = nil
+	for _, id := range MediaReferences(n.Content) {
+		if media, ok := mapMedia[id]; ok {
+			n.Media = append(n.Media, media)
+		}
+	}
+}
+
 type SliceNewsDto []NewsDto
+
+// WithoutContent drops the full content from every news, list responses only
+// carry the excerpt unless the content is asked for.
+func (s *SliceNewsDto) WithoutContent() *SliceNewsDto {
+	res := SliceNewsDto{}
+	for _, news := range *s {
+		news.Content = ""
+		news.ContentHTML = ""
+		res = append(res, news)
+	}
+	return &res
+}
+
+func (s *SliceNewsDto) MediaIds() (ids []string) {
+	duplication := map[string]bool{}
+	for _, news := range *s {
+		for _, id := range news.MediaIds() {
+			if duplication[id] {
+				continue
+			}
+			duplication[id] = true
+			ids = append(ids, id)
+		}
+	}
+	return
+}
+
+func (s *SliceNewsDto) LinkMedia(mapMedia map[string]MediaDto) {
+	for i := range *s {
+		(*s)[i].LinkMedia(mapMedia)
+	}
+}
@@ -19,17 +19,21 @@ func (t TagStatus) String() string {
 }
 
 type Tag struct {
-	ID        string    `db:"id"`
-	Name      string    `db:"name"`
-	Status    TagStatus `db:"status"`
+	ID     string    `db:"id"`
+	Name   string    `db:"name"`
+	Status TagStatus `db:"status"`
+	// Version starts at 1 and is incremented by every write.
+	Version   int       `db:"version"`
 	CreatedAt time.Time `db:"createdAt"`
+	UpdatedAt time.Time `db:"updatedAt"`
 }
 
 func newTag(id, name string) *Tag {
 	if id == "" {
 		id = IDGEN.NewUUID()
 	}
-	return &Tag{ID: id, Name: name, Status: TagActive, CreatedAt: Date.Now()}
+	now := Date.Now()
+	return &Tag{ID: id, Name: name, Status: TagActive, Version: 1, CreatedAt: now, UpdatedAt: now}
 }
 
 func (t *Tag) UpdateTag(newTag *Tag) {
@@ -39,17 +43,23 @@ func (t *Tag) UpdateTag(newTag *Tag) {
 	if newTag.Status > 0 {
 		t.Status = newTag.Status
 	}
+	t.Version++
+	t.UpdatedAt = Date.Now()
 }
 
 func (t *Tag) Delete() {
 	t.Status = TagDelete
+	t.Version++
+	t.UpdatedAt = Date.Now()
 }
 
 func (t *Tag) ToDto() *TagDto {
 	return &TagDto{
-		ID:     t.ID,
-		Name:   t.Name,
-		Status: t.Status.String(),
+		ID:        t.ID,
+		Name:      t.Name,
+		Status:    t.Status.String(),
+		Version:   t.Version,
+		UpdatedAt: t.UpdatedAt,
 	}
 }
 
@@ -1,11 +1,19 @@
 package entities
 
-import "news/shared/failure"
+import (
+	"news/shared/failure"
+	"time"
+)
 
 type TagDto struct {
 	ID     string `json:"id"`
 	Name   string `json:"name"`
 	Status string `json:"status"`
+	// Version is the stored version in responses, and the version an update
+	// expects to replace in requests, 0 replaces any.
+	Version int `json:"version"`
+	// UpdatedAt is the time of the last write, it is never read from requests.
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 func (t *TagDto) ToTag() *Tag {
@@ -14,7 +22,7 @@ func (t *TagDto) ToTag() *Tag {
 
 func (t *TagDto) Validate() error {
 	if t.Name == "" {
-		return failure.BadRequestWithString("name can't be null")
+		return failure.Validation([]failure.Violation{{Field: "name", Message: "name can't be null"}})
 	}
 	return nil
 }
@@ -25,7 +33,7 @@ type CreateTag struct {
 
 func (c *CreateTag) Validate() error {
 	if c.Name == "" {
-		return failure.BadRequestWithString("name can't be empty")
+		return failure.Validation([]failure.Violation{{Field: "name", Message: "name can't be empty"}})
 	}
 	return nil
 }
@@ -0,0 +1,191 @@
+package entities
+
+import (
+	"news/shared/Date"
+	"news/shared/IDGEN"
+	"strings"
+	"time"
+)
+
+type WebhookStatus int
+
+const (
+	WebhookActive WebhookStatus = iota + 1
+	WebhookDeleted
+)
+
+func (w WebhookStatus) String() string {
+	stringer := []string{"not found", "active", "deleted"}
+	return stringer[w]
+}
+
+// Webhook is a subscription of a partner URL to events, EventTypes is comma
+// separated and empty to receive every event.
+type Webhook struct {
+	ID         string        `db:"id"`
+	URL        string        `db:"url"`
+	EventTypes string        `db:"event_types"`
+	Secret     string        `db:"secret"`
+	Status     WebhookStatus `db:"status"`
+	CreatedAt  time.Time     `db:"createdAt"`
+}
+
+func NewWebhook(url string, eventTypes []string, secret string) *Webhook {
+	return &Webhook{ID: IDGEN.NewUUID(), URL: url, EventTypes: strings.Join(eventTypes, ","), Secret: secret,
+		Status: WebhookActive, CreatedAt: Date.Now()}
+}
+
+func (w *Webhook) SliceEventTypes() []string {
+	if w.EventTypes == "" {
+		return []string{}
+	}
+	return strings.Split(w.EventTypes, ",")
+}
+
+// Subscribed reports whether the webhook receives events of eventType.
+func (w *Webhook) Subscribed(eventType string) bool {
+	if w.Status != WebhookActive {
+		return false
+	}
+	if w.EventTypes == "" {
+		return true
+	}
+	for _, v := range w.SliceEventTypes() {
+		if v == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Webhook) Delete() {
+	w.Status = WebhookDeleted
+}
+
+// ToDto leaves the secret out, it is only returned when the webhook is created.
+func (w *Webhook) ToDto() *WebhookDto {
+	return &WebhookDto{
+		ID:         w.ID,
+		URL:        w.URL,
+		EventTypes: w.SliceEventTypes(),
+		Status:     w.Status.String(),
+		CreatedAt:  w.CreatedAt,
+	}
+}
+
+type Webhooks []Webhook
+
+func (w Webhooks) ToWebhooksDto() *[]WebhookDto {
+	result := []WebhookDto{}
+	for _, webhook := range w {
+		result = append(result, *webhook.ToDto())
+	}
+	return &result
+}
+
+type DeliveryStatus int
+
+// maxDeliveryError is the size of the last_error column.
+const maxDeliveryError = 1024
+
+// maxDeliveryBackoff is the longest wait between two attempts at a delivery.
+const maxDeliveryBackoff = 24 * time.Hour
+
+const (
+	DeliveryPending DeliveryStatus = iota + 1
+	DeliverySucceeded
+	DeliveryFailed
+)
+
+func (d DeliveryStatus) String() string {
+	stringer := []string{"not found", "pending", "succeeded", "failed"}
+	return stringer[d]
+}
+
+// WebhookDelivery is one event sent to one webhook, a pending delivery is sent
+// again at NextAttemptAt until it succeeds or runs out of attempts.
+type WebhookDelivery struct {
+	ID            string         `db:"id"`
+	WebhookID     string         `db:"webhook_id"`
+	EventID       string         `db:"event_id"`
+	EventType     string         `db:"event_type"`
+	Payload       string         `db:"payload"`
+	Status        DeliveryStatus `db:"status"`
+	Attempts      int            `db:"attempts"`
+	ResponseCode  int            `db:"response_code"`
+	LastError     string         `db:"last_error"`
+	NextAttemptAt *time.Time     `db:"nextAttemptAt"`
+	// LockedUntil is set while an attempt is in flight, storing its outcome clears it.
+	LockedUntil *time.Time `db:"lockedUntil"`
+	CreatedAt   time.Time  `db:"createdAt"`
+	DeliveredAt *time.Time `db:"deliveredAt"`
+}
+
+func NewWebhookDelivery(webhookID string, event EventDto, payload string) *WebhookDelivery {
+	now := Date.Now()
+	return &WebhookDelivery{ID: IDGEN.NewUUID(), WebhookID: webhookID, EventID: event.ID, EventType: event.Type,
+		Payload: payload, Status: DeliveryPending, NextAttemptAt: &now, CreatedAt: now}
+}
+
+func (d *WebhookDelivery) Succeed(responseCode int) {
+	now := Date.Now()
+	d.Attempts++
+	d.Status = DeliverySucceeded
+	d.ResponseCode = responseCode
+	d.LastError = ""
+	d.NextAttemptAt = nil
+	d.DeliveredAt = &now
+}
+
+// Fail records a failed attempt, the next one waits backoff doubled for every
+// attempt made. The delivery fails for good after maxAttempts.
+func (d *WebhookDelivery) Fail(responseCode int, reason string, maxAttempts int, backoff time.Duration) {
+	d.Attempts++
+	d.ResponseCode = responseCode
+	if len(reason) > maxDeliveryError {
+		reason = reason[:maxDeliveryError]
+	}
+	d.LastError = reason
+	if d.Attempts >= maxAttempts {
+		d.Status = DeliveryFailed
+		d.NextAttemptAt = nil
+		return
+	}
+	next := Date.Now().Add(deliveryBackoff(backoff, d.Attempts-1))
+	d.Status = DeliveryPending
+	d.NextAttemptAt = &next
+}
+
+// deliveryBackoff returns backoff doubled doublings times, at most maxDeliveryBackoff.
+func deliveryBackoff(backoff time.Duration, doublings int) time.Duration {
+	if doublings > 62 || backoff > maxDeliveryBackoff>>doublings {
+		return maxDeliveryBackoff
+	}
+	return backoff << doublings
+}
+
+func (d *WebhookDelivery) ToDto() *WebhookDeliveryDto {
+	return &WebhookDeliveryDto{
+		ID:            d.ID,
+		WebhookID:     d.WebhookID,
+		EventID:       d.EventID,
+		EventType:     d.EventType,
+		Status:        d.Status.String(),
+		Attempts:      d.Attempts,
+		ResponseCode:  d.ResponseCode,
+		LastError:     d.LastError,
+		NextAttemptAt: d.NextAttemptAt,
+		CreatedAt:     d.CreatedAt,
+		DeliveredAt:   d.DeliveredAt,
+	}
+}
+
+type WebhookDeliveries []WebhookDelivery
+
+func (w WebhookDeliveries) ToDeliveriesDto() *[]WebhookDeliveryDto {
+	result := []WebhookDeliveryDto{}
+	for _, delivery := range w {
+		result = append(result, *delivery.ToDto())
+	}
+	return &result
+}
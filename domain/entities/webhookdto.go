@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"net/url"
+	"news/shared/failure"
+	"strconv"
+	"time"
+)
+
+type WebhookDto struct {
+	ID         string    `json:"id"`
+	URL        string    `json:"url"`
+	EventTypes []string  `json:"event_types"`
+	Secret     string    `json:"secret,omitempty"`
+	Status     string    `json:"status"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type CreateWebhook struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Secret     string   `json:"secret"`
+}
+
+func (c *CreateWebhook) Validate() error {
+	var violations []failure.Violation
+	target, err := url.Parse(c.URL)
+	if c.URL == "" {
+		violations = append(violations, failure.Violation{Field: "url", Message: "url can't be null"})
+	} else if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		violations = append(violations, failure.Violation{Field: "url", Message: "url not valid"})
+	}
+	for i, eventType := range c.EventTypes {
+		if _, err := StringToEventType(eventType); err != nil {
+			violations = append(violations, failure.Violation{Field: "event_types[" + strconv.Itoa(i) + "]",
+				Message: "event type " + eventType + " not valid"})
+		}
+	}
+	if len(violations) > 0 {
+		return failure.Validation(violations)
+	}
+	return nil
+}
+
+type WebhookDeliveryDto struct {
+	ID            string     `json:"id"`
+	WebhookID     string     `json:"webhook_id"`
+	EventID       string     `json:"event_id"`
+	EventType     string     `json:"event_type"`
+	Status        string     `json:"status"`
+	Attempts      int        `json:"attempts"`
+	ResponseCode  int        `json:"response_code,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+}
@@ -0,0 +1,33 @@
+package media
+
+import (
+	"context"
+	"news/domain/entities"
+	"news/shared/metrics"
+	"time"
+)
+
+// instrumentedRepository observes the duration of every call of the wrapped
+// repository in the repository_query_duration_seconds metric.
+type instrumentedRepository struct {
+	repo Repository
+}
+
+func NewInstrumentedRepository(repo Repository) *instrumentedRepository {
+	return &instrumentedRepository{repo: repo}
+}
+
+func (r *instrumentedRepository) CreateMedia(ctx context.Context, media *entities.Media) error {
+	defer metrics.ObserveQuery("media", "CreateMedia", time.Now())
+	return r.repo.CreateMedia(ctx, media)
+}
+
+func (r *instrumentedRepository) GetMediaByID(ctx context.Context, id string) (*entities.Media, error) {
+	defer metrics.ObserveQuery("media", "GetMediaByID", time.Now())
+	return r.repo.GetMediaByID(ctx, id)
+}
+
+func (r *instrumentedRepository) GetMediaByIds(ctx context.Context, ids []string) (*entities.SliceMedia, error) {
+	defer metrics.ObserveQuery("media", "GetMediaByIds", time.Now())
+	return r.repo.GetMediaByIds(ctx, ids)
+}
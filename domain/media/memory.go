@@ -0,0 +1,51 @@
+package media
+
+import (
+	"context"
+	"news/domain/entities"
+	"news/shared/failure"
+	"sync"
+)
+
+// memoryRepository keeps media in a map, it behaves like the SQL repository.
+type memoryRepository struct {
+	mu    sync.RWMutex
+	media map[string]entities.Media
+}
+
+func NewMemoryRepository() *memoryRepository {
+	return &memoryRepository{media: map[string]entities.Media{}}
+}
+
+func (r *memoryRepository) CreateMedia(ctx context.Context, media *entities.Media) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.media[media.ID]; ok {
+		return failure.InternalServerError
+	}
+	r.media[media.ID] = *media
+	return nil
+}
+
+func (r *memoryRepository) GetMediaByID(ctx context.Context, id string) (media *entities.Media, err error) {
+	sliceMedia, err := r.GetMediaByIds(ctx, []string{id})
+	if err != nil {
+		return
+	}
+	return &(*sliceMedia)[0], nil
+}
+
+func (r *memoryRepository) GetMediaByIds(ctx context.Context, ids []string) (*entities.SliceMedia, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	sliceMedia := entities.SliceMedia{}
+	for _, id := range ids {
+		if media, ok := r.media[id]; ok {
+			sliceMedia = append(sliceMedia, media)
+		}
+	}
+	if len(sliceMedia) < 1 {
+		return nil, ErrNotFound
+	}
+	return &sliceMedia, nil
+}
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: repository.go
+
+// Package media_mock is a generated GoMock package.
+package media_mock
+
+import (
+	context "context"
+	entities "news/domain/entities"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockRepository is a mock of Repository interface.
+type MockRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockRepositoryMockRecorder
+}
+
+// MockRepositoryMockRecorder is the mock recorder for MockRepository.
+type MockRepositoryMockRecorder struct {
+	mock *MockRepository
+}
+
+// NewMockRepository creates a new mock instance.
+func NewMockRepository(ctrl *gomock.Controller) *MockRepository {
+	mock := &MockRepository{ctrl: ctrl}
+	mock.recorder = &MockRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateMedia mocks base method.
+func (m *MockRepository) CreateMedia(ctx context.Context, media *entities.Media) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMedia", ctx, media)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMedia indicates an expected call of CreateMedia.
+func (mr *MockRepositoryMockRecorder) CreateMedia(ctx, media interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMedia", reflect.TypeOf((*MockRepository)(nil).CreateMedia), ctx, media)
+}
+
+// GetMediaByID mocks base method.
+func (m *MockRepository) GetMediaByID(ctx context.Context, id string) (*entities.Media, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMediaByID", ctx, id)
+	ret0, _ := ret[0].(*entities.Media)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMediaByID indicates an expected call of GetMediaByID.
+func (mr *MockRepositoryMockRecorder) GetMediaByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMediaByID", reflect.TypeOf((*MockRepository)(nil).GetMediaByID), ctx, id)
+}
+
+// GetMediaByIds mocks base method.
+func (m *MockRepository) GetMediaByIds(ctx context.Context, ids []string) (*entities.SliceMedia, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMediaByIds", ctx, ids)
+	ret0, _ := ret[0].(*entities.SliceMedia)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMediaByIds indicates an expected call of GetMediaByIds.
+func (mr *MockRepositoryMockRecorder) GetMediaByIds(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMediaByIds", reflect.TypeOf((*MockRepository)(nil).GetMediaByIds), ctx, ids)
+}
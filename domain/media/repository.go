@@ -0,0 +1,86 @@
+package media
+
+//go:generate go run github.com/golang/mock/mockgen -source repository.go -destination mock/repository_mock.go -package media_mock
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"net/http"
+	"news/domain/entities"
+	"news/shared/database"
+	"news/shared/failure"
+	"news/shared/logger"
+)
+
+type Repository interface {
+	CreateMedia(ctx context.Context, media *entities.Media) error
+	GetMediaByID(ctx context.Context, id string) (*entities.Media, error)
+	GetMediaByIds(ctx context.Context, ids []string) (*entities.SliceMedia, error)
+}
+
+var (
+	ErrNotFound          = failure.New(http.StatusNotFound, "media.not_found", "media not found")
+	ErrThumbnailNotFound = failure.New(http.StatusNotFound, "media.thumbnail_not_found", "thumbnail not found")
+	ErrNotDecodable      = failure.New(http.StatusBadRequest, "media.not_decodable", "can't decode image")
+	ErrTooManyPixels     = failure.New(http.StatusRequestEntityTooLarge, "media.too_many_pixels", "image has too many pixels")
+)
+
+type repository struct {
+	DB *sqlx.DB
+}
+
+func NewRepository(DB *sqlx.DB) *repository {
+	return &repository{DB: DB}
+}
+
+func (r *repository) CreateMedia(ctx context.Context, media *entities.Media) (err error) {
+	query := "INSERT INTO `media`(`id`, `name`, `path`, `mime_type`, `size`, `width`, `height`, `thumbnails`, `createdAt`) " +
+		"VALUES (:id, :name, :path, :mime_type, :size, :width, :height, :thumbnails, :createdAt)"
+	stmt, err := r.DB.PrepareNamedContext(ctx, database.Rebind(r.DB, query))
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
+		return
+	}
+	_, err = stmt.ExecContext(ctx, media)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
+		return
+	}
+	return
+}
+
+func (r *repository) GetMediaByID(ctx context.Context, id string) (media *entities.Media, err error) {
+	sliceMedia, err := r.selectMedia(ctx, "WHERE id = ?", id)
+	if err != nil {
+		return
+	}
+	media = &(*sliceMedia)[0]
+	return
+}
+
+func (r *repository) GetMediaByIds(ctx context.Context, ids []string) (sliceMedia *entities.SliceMedia, err error) {
+	query, args, err := sqlx.In("WHERE id IN (?)", ids)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
+		return
+	}
+	return r.selectMedia(ctx, query, args...)
+}
+
+func (r *repository) selectMedia(ctx context.Context, where string, args ...interface{}) (sliceMedia *entities.SliceMedia, err error) {
+	sliceMedia = new(entities.SliceMedia)
+	query := "SELECT `id`, `name`, `path`, `mime_type`, `size`, `width`, `height`, `thumbnails`, `createdAt` FROM `media` " + where
+	err = r.DB.SelectContext(ctx, sliceMedia, database.Rebind(r.DB, query), args...)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
+		return
+	}
+	if len(*sliceMedia) < 1 {
+		err = ErrNotFound
+	}
+	return
+}
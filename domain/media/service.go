@@ -0,0 +1,181 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"io/fs"
+	"net/http"
+	"news/domain/entities"
+	"news/shared/failure"
+	"news/shared/logger"
+	"strings"
+)
+
+var extensions = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+type Service interface {
+	Upload(ctx context.Context, name string, content io.Reader) (*entities.MediaDto, error)
+	Open(ctx context.Context, id string, size string) (io.ReadCloser, *entities.Media, error)
+}
+
+type service struct {
+	repo         Repository
+	store        MediaStore
+	maxSize      int64
+	maxPixels    int64
+	allowedTypes map[string]bool
+	sizes        []ThumbnailSize
+}
+
+func NewService(repo Repository, store MediaStore, maxSize int64, maxPixels int64, allowedTypes []string,
+	sizes []ThumbnailSize) *service {
+	allowed := map[string]bool{}
+	for _, mimeType := range allowedTypes {
+		mimeType = strings.TrimSpace(mimeType)
+		if _, ok := extensions[mimeType]; ok {
+			allowed[mimeType] = true
+		}
+	}
+	return &service{repo: repo, store: store, maxSize: maxSize, maxPixels: maxPixels, allowedTypes: allowed, sizes: sizes}
+}
+
+func (s service) Upload(ctx context.Context, name string, content io.Reader) (result *entities.MediaDto, err error) {
+	data, err := io.ReadAll(io.LimitReader(content, s.maxSize+1))
+	if err != nil {
+		err = failure.BadRequestWithString("can't read uploaded file")
+		return
+	}
+	if int64(len(data)) > s.maxSize {
+		err = failure.Error(fmt.Errorf("file is larger than %d bytes", s.maxSize), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	mimeType := strings.Split(http.DetectContentType(data), ";")[0]
+	if !s.allowedTypes[mimeType] {
+		err = failure.Error(fmt.Errorf("file type %s is not allowed", mimeType), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	hash := sha256.Sum256(data)
+	id := hex.EncodeToString(hash[:])
+	existing, err := s.repo.GetMediaByID(ctx, id)
+	if err == nil {
+		return existing.ToDto(), nil
+	}
+	if failure.GetStatus(err) != http.StatusNotFound {
+		return
+	}
+
+	media := entities.NewMedia(id, name, id+extensions[mimeType], mimeType, int64(len(data)))
+	config, _, errs := image.DecodeConfig(bytes.NewReader(data))
+	if errs == nil {
+		if int64(config.Width)*int64(config.Height) > s.maxPixels {
+			err = ErrTooManyPixels
+			return
+		}
+		media.Width, media.Height = config.Width, config.Height
+	}
+	// every thumbnail is rendered before anything is stored, an image that can't
+	// be decoded leaves no file behind
+	thumbnails, err := s.createThumbnails(ctx, media, data)
+	if err != nil {
+		return
+	}
+
+	err = s.save(ctx, media.Path, data)
+	if err != nil {
+		return
+	}
+	for name, thumb := range thumbnails {
+		err = s.save(ctx, name, thumb)
+		if err != nil {
+			return
+		}
+	}
+
+	err = s.repo.CreateMedia(ctx, media)
+	if err != nil {
+		return
+	}
+	result = media.ToDto()
+	return
+}
+
+// createThumbnails renders the thumbnails of media by path, it sets the sizes on media.
+func (s service) createThumbnails(ctx context.Context, media *entities.Media, data []byte) (thumbnails map[string][]byte, err error) {
+	if media.MimeType != "image/jpeg" && media.MimeType != "image/png" {
+		return
+	}
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		err = ErrNotDecodable
+		return
+	}
+
+	thumbnails = map[string][]byte{}
+	var sizes []string
+	for _, size := range s.sizes {
+		thumb, errs := thumbnail(src, media.MimeType, size)
+		if errs != nil {
+			logger.ErrorWithStack(ctx, errs)
+			err = failure.InternalServerError.Wrap(errs)
+			return
+		}
+		thumbnails[thumbnailPath(media, size.String())] = thumb
+		sizes = append(sizes, size.String())
+	}
+	media.Thumbnails = strings.Join(sizes, ",")
+	return
+}
+
+func (s service) save(ctx context.Context, name string, data []byte) error {
+	err := s.store.Save(ctx, name, bytes.NewReader(data))
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+	return nil
+}
+
+func (s service) Open(ctx context.Context, id string, size string) (content io.ReadCloser, media *entities.Media, err error) {
+	media, err = s.repo.GetMediaByID(ctx, id)
+	if err != nil {
+		return
+	}
+	name := media.Path
+	if size != "" {
+		if !media.HasThumbnail(size) {
+			err = ErrThumbnailNotFound
+			return
+		}
+		name = thumbnailPath(media, size)
+	}
+	content, err = s.store.Open(ctx, name)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		if errors.Is(err, fs.ErrNotExist) {
+			err = ErrNotFound
+			return
+		}
+		err = failure.InternalServerError.Wrap(err)
+	}
+	return
+}
+
+func thumbnailPath(media *entities.Media, size string) string {
+	return strings.TrimSuffix(media.Path, extensions[media.MimeType]) + "_" + size + extensions[media.MimeType]
+}
@@ -0,0 +1,67 @@
+package media_test
+
+import (
+	"bytes"
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"image"
+	"image/png"
+	"news/domain/media"
+	media_mock "news/domain/media/mock"
+	"os"
+	"testing"
+)
+
+func newPNG(t *testing.T, width, height int) []byte {
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestServiceUpload(t *testing.T) {
+	sliceTest := []struct {
+		testTitle string
+		content   func(t *testing.T) []byte
+		err       error
+		files     int
+	}{
+		{testTitle: "image with thumbnails", content: func(t *testing.T) []byte { return newPNG(t, 40, 20) }, files: 2},
+		{testTitle: "too many pixels", content: func(t *testing.T) []byte { return newPNG(t, 101, 100) },
+			err: media.ErrTooManyPixels},
+		// the header is whole, only the pixels are cut
+		{testTitle: "image not decodable", content: func(t *testing.T) []byte { return newPNG(t, 40, 20)[:40] },
+			err: media.ErrNotDecodable},
+	}
+	for _, test := range sliceTest {
+		t.Run(test.testTitle, func(t *testing.T) {
+			ctx := context.Background()
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			repo := media_mock.NewMockRepository(ctrl)
+			root := t.TempDir()
+			store, err := media.NewLocalStore(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sizes, _ := media.ParseThumbnailSizes([]string{"10x10"})
+			service := media.NewService(repo, store, 1<<20, 100*100, []string{"image/png"}, sizes)
+
+			repo.EXPECT().GetMediaByID(ctx, gomock.Any()).Return(nil, media.ErrNotFound)
+			if test.err == nil {
+				repo.EXPECT().CreateMedia(ctx, gomock.Any()).Return(nil)
+			}
+			_, err = service.Upload(ctx, "photo.png", bytes.NewReader(test.content(t)))
+			assert.Equal(t, err, test.err)
+
+			files, err := os.ReadDir(root)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, len(files), test.files)
+		})
+	}
+}
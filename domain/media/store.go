@@ -0,0 +1,54 @@
+package media
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// MediaStore keeps the binary content of uploaded media, addressed by name.
+type MediaStore interface {
+	Save(ctx context.Context, name string, content io.Reader) error
+	Open(ctx context.Context, name string) (io.ReadCloser, error)
+}
+
+type localStore struct {
+	root string
+}
+
+func NewLocalStore(root string) (*localStore, error) {
+	err := os.MkdirAll(root, 0755)
+	if err != nil {
+		return nil, err
+	}
+	return &localStore{root: root}, nil
+}
+
+func (l *localStore) path(name string) string {
+	return filepath.Join(l.root, filepath.Base(name))
+}
+
+// Save writes to a temporary file first so readers never see a partial file.
+func (l *localStore) Save(ctx context.Context, name string, content io.Reader) (err error) {
+	tmp, err := os.CreateTemp(l.root, ".upload-*")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = io.Copy(tmp, content)
+	if err != nil {
+		tmp.Close()
+		return
+	}
+	err = tmp.Close()
+	if err != nil {
+		return
+	}
+	return os.Rename(tmp.Name(), l.path(name))
+}
+
+func (l *localStore) Open(ctx context.Context, name string) (io.ReadCloser, error) {
+	return os.Open(l.path(name))
+}
@@ -0,0 +1,105 @@
+package media
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"strconv"
+	"strings"
+)
+
+type ThumbnailSize struct {
+	Width  int
+	Height int
+}
+
+func (t ThumbnailSize) String() string {
+	return fmt.Sprintf("%dx%d", t.Width, t.Height)
+}
+
+// ParseThumbnailSizes parses sizes written as `<width>x<height>`, e.g. `150x150`.
+func ParseThumbnailSizes(sizes []string) (result []ThumbnailSize, err error) {
+	for _, size := range sizes {
+		size = strings.TrimSpace(size)
+		if size == "" {
+			continue
+		}
+		parts := strings.Split(size, "x")
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid thumbnail size %q", size)
+		}
+		width, errW := strconv.Atoi(parts[0])
+		height, errH := strconv.Atoi(parts[1])
+		if errW != nil || errH != nil || width < 1 || height < 1 {
+			return nil, fmt.Errorf("invalid thumbnail size %q", size)
+		}
+		result = append(result, ThumbnailSize{Width: width, Height: height})
+	}
+	return
+}
+
+// fit scales width and height down to fit inside the box, keeping the aspect ratio.
+// Images smaller than the box are never scaled up.
+func fit(width, height int, box ThumbnailSize) (int, int) {
+	if width <= box.Width && height <= box.Height {
+		return width, height
+	}
+	if width*box.Height > height*box.Width {
+		return box.Width, maxInt(1, height*box.Width/width)
+	}
+	return maxInt(1, width*box.Height/height), box.Height
+}
+
+// resize downscales src with an area average, every destination pixel is the
+// mean of the source pixels it covers.
+func resize(src image.Image, width, height int) image.Image {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	dst := image.NewRGBA64(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		y0 := bounds.Min.Y + y*srcH/height
+		y1 := maxInt(y0+1, bounds.Min.Y+(y+1)*srcH/height)
+		for x := 0; x < width; x++ {
+			x0 := bounds.Min.X + x*srcW/width
+			x1 := maxInt(x0+1, bounds.Min.X+(x+1)*srcW/width)
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+					n++
+				}
+			}
+			dst.SetRGBA64(x, y, color.RGBA64{R: uint16(r / n), G: uint16(g / n), B: uint16(b / n), A: uint16(a / n)})
+		}
+	}
+	return dst
+}
+
+// thumbnail renders src inside the box and encodes it in the same format as the original.
+func thumbnail(src image.Image, mimeType string, box ThumbnailSize) ([]byte, error) {
+	width, height := fit(src.Bounds().Dx(), src.Bounds().Dy(), box)
+	resized := resize(src, width, height)
+
+	var buf bytes.Buffer
+	var err error
+	switch mimeType {
+	case "image/jpeg":
+		err = jpeg.Encode(&buf, resized, &jpeg.Options{Quality: 85})
+	case "image/png":
+		err = png.Encode(&buf, resized)
+	default:
+		err = fmt.Errorf("can't create thumbnail for %s", mimeType)
+	}
+	return buf.Bytes(), err
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
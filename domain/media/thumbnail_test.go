@@ -0,0 +1,61 @@
+package media
+
+import (
+	"bytes"
+	"github.com/magiconair/properties/assert"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func TestThumbnail(t *testing.T) {
+	t.Run("testParseThumbnailSizes", func(t *testing.T) {
+		actual, err := ParseThumbnailSizes([]string{"150x150", " 640x360", ""})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, actual, []ThumbnailSize{{Width: 150, Height: 150}, {Width: 640, Height: 360}})
+
+		for _, input := range []string{"150", "0x10", "ax10", "10x10x10"} {
+			_, err = ParseThumbnailSizes([]string{input})
+			assert.Equal(t, err != nil, true, input)
+		}
+	})
+
+	t.Run("testFit", func(t *testing.T) {
+		sliceTest := []struct {
+			testTitle     string
+			width, height int
+			box           ThumbnailSize
+			expectedW     int
+			expectedH     int
+		}{
+			{testTitle: "landscape", width: 1280, height: 720, box: ThumbnailSize{150, 150}, expectedW: 150, expectedH: 84},
+			{testTitle: "portrait", width: 720, height: 1280, box: ThumbnailSize{150, 150}, expectedW: 84, expectedH: 150},
+			{testTitle: "smaller than box", width: 100, height: 50, box: ThumbnailSize{150, 150}, expectedW: 100, expectedH: 50},
+			{testTitle: "very wide", width: 10000, height: 1, box: ThumbnailSize{100, 100}, expectedW: 100, expectedH: 1},
+		}
+		for _, test := range sliceTest {
+			t.Run(test.testTitle, func(t *testing.T) {
+				w, h := fit(test.width, test.height, test.box)
+				assert.Equal(t, []int{w, h}, []int{test.expectedW, test.expectedH})
+			})
+		}
+	})
+
+	t.Run("testThumbnailPng", func(t *testing.T) {
+		src := image.NewRGBA(image.Rect(0, 0, 40, 20))
+		for y := 0; y < 20; y++ {
+			for x := 0; x < 40; x++ {
+				src.Set(x, y, color.RGBA{R: 255, A: 255})
+			}
+		}
+		data, err := thumbnail(src, "image/png", ThumbnailSize{Width: 10, Height: 10})
+		assert.Equal(t, err, nil)
+
+		decoded, err := png.Decode(bytes.NewReader(data))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, decoded.Bounds().Size(), image.Point{X: 10, Y: 5})
+		r, g, b, a := decoded.At(3, 3).RGBA()
+		assert.Equal(t, []uint32{r, g, b, a}, []uint32{0xffff, 0, 0, 0xffff})
+	})
+}
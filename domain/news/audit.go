@@ -0,0 +1,19 @@
+package news
+
+import (
+	"context"
+	"news/domain/audit"
+	"news/domain/entities"
+)
+
+// auditEntry returns the audit log entry of storing news over old by the actor
+// of ctx, old is nil for a new news. The repositories write it with the news.
+func auditEntry(ctx context.Context, old *entities.News, news *entities.News) (*entities.AuditEntry, error) {
+	switch {
+	case old == nil:
+		return audit.NewEntry(ctx, entities.AuditCreate, entities.AuditNews, news.ID, nil, news.ToNewsDto())
+	case news.DeletedAt != nil && old.DeletedAt == nil:
+		return audit.NewEntry(ctx, entities.AuditDelete, entities.AuditNews, news.ID, old.ToNewsDto(), nil)
+	}
+	return audit.NewEntry(ctx, entities.AuditUpdate, entities.AuditNews, news.ID, old.ToNewsDto(), news.ToNewsDto())
+}
@@ -15,6 +15,18 @@ type Cache interface {
 	SetNews(ctx context.Context, key string, newsDto *entities.NewsDto) error
 	GetSliceNews(ctx context.Context, key string) (sliceNewsDto *entities.SliceNewsDto, err error)
 	GetNews(ctx context.Context, key string) (newsDto *entities.NewsDto, err error)
+	Delete(ctx context.Context, keys ...string) error
+}
+
+// CacheKeys returns every key the reads of the service may cache news under,
+// with and without its language.
+func CacheKeys(news *entities.News) []string {
+	var keys []string
+	for _, lang := range []string{"", news.Language} {
+		keys = append(keys, "slug:"+lang+":"+news.Slug, "all:"+lang, "topic:"+lang+":"+news.Topic,
+			"status:"+lang+":"+news.Status.String())
+	}
+	return keys
 }
 
 type cacheImpl struct {
@@ -85,3 +97,7 @@ func (c *cacheImpl) GetNews(ctx context.Context, key string) (newsDto *entities.
 	err = json.Unmarshal([]byte(val), newsDto)
 	return
 }
+
+func (c *cacheImpl) Delete(ctx context.Context, keys ...string) error {
+	return c.redis.Del(keys...).Err()
+}
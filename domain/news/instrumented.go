@@ -0,0 +1,79 @@
+package news
+
+import (
+	"context"
+	"news/domain/entities"
+	"news/shared/metrics"
+	"time"
+)
+
+// instrumentedRepository observes the duration of every call of the wrapped
+// repository in the repository_query_duration_seconds metric.
+type instrumentedRepository struct {
+	repo Repository
+}
+
+func NewInstrumentedRepository(repo Repository) *instrumentedRepository {
+	return &instrumentedRepository{repo: repo}
+}
+
+func (r *instrumentedRepository) CreateNews(ctx context.Context, news *entities.News) error {
+	defer metrics.ObserveQuery("news", "CreateNews", time.Now())
+	return r.repo.CreateNews(ctx, news)
+}
+
+func (r *instrumentedRepository) GetNewsByID(ctx context.Context, id string) (*entities.News, error) {
+	defer metrics.ObserveQuery("news", "GetNewsByID", time.Now())
+	return r.repo.GetNewsByID(ctx, id)
+}
+
+func (r *instrumentedRepository) GetNewsBySlug(ctx context.Context, slug string, lang string) (*entities.News, error) {
+	defer metrics.ObserveQuery("news", "GetNewsBySlug", time.Now())
+	return r.repo.GetNewsBySlug(ctx, slug, lang)
+}
+
+func (r *instrumentedRepository) GetNewsByTopic(ctx context.Context, topic string, lang string) (*entities.SliceNews, error) {
+	defer metrics.ObserveQuery("news", "GetNewsByTopic", time.Now())
+	return r.repo.GetNewsByTopic(ctx, topic, lang)
+}
+
+func (r *instrumentedRepository) GetNewsByStatus(ctx context.Context, status entities.NewsStatus, lang string) (*entities.SliceNews, error) {
+	defer metrics.ObserveQuery("news", "GetNewsByStatus", time.Now())
+	return r.repo.GetNewsByStatus(ctx, status, lang)
+}
+
+func (r *instrumentedRepository) GetNewsByTranslationGroup(ctx context.Context, groupID string) (*entities.SliceNews, error) {
+	defer metrics.ObserveQuery("news", "GetNewsByTranslationGroup", time.Now())
+	return r.repo.GetNewsByTranslationGroup(ctx, groupID)
+}
+
+func (r *instrumentedRepository) GetAllNews(ctx context.Context, lang string) (*entities.SliceNews, error) {
+	defer metrics.ObserveQuery("news", "GetAllNews", time.Now())
+	return r.repo.GetAllNews(ctx, lang)
+}
+
+func (r *instrumentedRepository) SearchNews(ctx context.Context, query string, lang string) (*entities.SliceNews, error) {
+	defer metrics.ObserveQuery("news", "SearchNews", time.Now())
+	return r.repo.SearchNews(ctx, query, lang)
+}
+
+func (r *instrumentedRepository) UpdateNews(ctx context.Context, news *entities.News) error {
+	defer metrics.ObserveQuery("news", "UpdateNews", time.Now())
+	return r.repo.UpdateNews(ctx, news)
+}
+
+func (r *instrumentedRepository) SaveNews(ctx context.Context, sliceNews entities.SliceNews) error {
+	defer metrics.ObserveQuery("news", "SaveNews", time.Now())
+	return r.repo.SaveNews(ctx, sliceNews)
+}
+
+// StreamNews observes the whole stream, the time fn takes included.
+func (r *instrumentedRepository) StreamNews(ctx context.Context, fn func(news *entities.News) error) error {
+	defer metrics.ObserveQuery("news", "StreamNews", time.Now())
+	return r.repo.StreamNews(ctx, fn)
+}
+
+func (r *instrumentedRepository) DeleteNews(ctx context.Context, id string) error {
+	defer metrics.ObserveQuery("news", "DeleteNews", time.Now())
+	return r.repo.DeleteNews(ctx, id)
+}
@@ -0,0 +1,289 @@
+package news
+
+import (
+	"context"
+	"news/domain/audit"
+	"news/domain/entities"
+	"news/domain/outbox"
+	"news/shared/failure"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// memoryRepository keeps news in a map, it behaves like the SQL repository and
+// passes the same contract. Slug and creation time never change once stored,
+// exactly like the columns the SQL update leaves alone.
+type memoryRepository struct {
+	mu   sync.RWMutex
+	news map[string]entities.News
+	// CountComments returns the approved comments of a news, the SQL repository
+	// counts them with a subquery. News have no comments while it is nil.
+	CountComments func(newsID string) int
+	// Outbox receives the events of every change, they are dropped while it is nil.
+	Outbox outbox.Recorder
+	// Audit receives the audit log entry of every change, they are dropped while it is nil.
+	Audit audit.Recorder
+}
+
+func NewMemoryRepository() *memoryRepository {
+	return &memoryRepository{news: map[string]entities.News{}}
+}
+
+func copyNews(news entities.News) entities.News {
+	news.Tags = append([]string(nil), news.Tags...)
+	return news
+}
+
+func (r *memoryRepository) CreateNews(ctx context.Context, news *entities.News) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range r.conflicts(news) {
+		if id == news.ID {
+			return failure.InternalServerError
+		}
+		return ErrSlugTaken
+	}
+	r.news[news.ID] = copyNews(*news)
+	return r.record(ctx, nil, news)
+}
+
+// record adds the events of storing news over old to the outbox and its entry to
+// the audit log, old is nil for a new news.
+func (r *memoryRepository) record(ctx context.Context, old *entities.News, news *entities.News) error {
+	if r.Outbox != nil {
+		events, err := entities.NewsEvents(old, news)
+		if err != nil {
+			return failure.InternalServerError.Wrap(err)
+		}
+		err = r.Outbox.Add(ctx, events...)
+		if err != nil {
+			return err
+		}
+	}
+	if r.Audit == nil {
+		return nil
+	}
+	entry, err := auditEntry(ctx, old, news)
+	if err != nil {
+		return err
+	}
+	return r.Audit.CreateEntry(ctx, entry)
+}
+
+// conflicts returns the ids of the stored news with the id, or the slug and language, of news.
+func (r *memoryRepository) conflicts(news *entities.News) (ids []string) {
+	for id, stored := range r.news {
+		if id == news.ID || (stored.Slug == news.Slug && stored.Language == news.Language) {
+			ids = append(ids, id)
+		}
+	}
+	return
+}
+
+func (r *memoryRepository) GetNewsBySlug(ctx context.Context, slug string, lang string) (news *entities.News, err error) {
+	sliceNews, err := r.selectNews(lang, func(news entities.News) bool {
+		return news.Slug == slug && news.Status == entities.NewsPublish
+	})
+	if err != nil {
+		return
+	}
+	return &(*sliceNews)[0], nil
+}
+
+func (r *memoryRepository) GetNewsByID(ctx context.Context, id string) (news *entities.News, err error) {
+	sliceNews, err := r.selectNews("", func(news entities.News) bool {
+		return news.ID == id
+	})
+	if err != nil {
+		return
+	}
+	return &(*sliceNews)[0], nil
+}
+
+func (r *memoryRepository) GetNewsByTopic(ctx context.Context, topic string, lang string) (*entities.SliceNews, error) {
+	return r.selectNews(lang, func(news entities.News) bool {
+		return news.Topic == topic && news.Status == entities.NewsPublish
+	})
+}
+
+func (r *memoryRepository) GetNewsByStatus(ctx context.Context, status entities.NewsStatus, lang string) (*entities.SliceNews, error) {
+	return r.selectNews(lang, func(news entities.News) bool {
+		return news.Status == status
+	})
+}
+
+func (r *memoryRepository) GetNewsByTranslationGroup(ctx context.Context, groupID string) (*entities.SliceNews, error) {
+	return r.selectNews("", func(news entities.News) bool {
+		return news.TranslationGroupID == groupID && news.Status != entities.NewsDeleted
+	})
+}
+
+func (r *memoryRepository) GetAllNews(ctx context.Context, lang string) (*entities.SliceNews, error) {
+	return r.selectNews(lang, func(news entities.News) bool {
+		return news.Status != entities.NewsDeleted
+	})
+}
+
+// SearchNews matches case insensitively like the default MySQL collation.
+func (r *memoryRepository) SearchNews(ctx context.Context, query string, lang string) (*entities.SliceNews, error) {
+	query = strings.ToLower(query)
+	return r.selectNews(lang, func(news entities.News) bool {
+		return news.Status == entities.NewsPublish && (strings.Contains(strings.ToLower(news.Title), query) ||
+			strings.Contains(strings.ToLower(news.Topic), query))
+	})
+}
+
+// selectNews returns the matching news newest first, or NotFound when none match.
+func (r *memoryRepository) selectNews(lang string, match func(news entities.News) bool) (*entities.SliceNews, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	sliceNews := entities.SliceNews{}
+	for _, news := range r.news {
+		if (lang == "" || news.Language == lang) && match(news) {
+			news = copyNews(news)
+			if r.CountComments != nil {
+				news.CommentCount = r.CountComments(news.ID)
+			}
+			sliceNews = append(sliceNews, news)
+		}
+	}
+	if len(sliceNews) < 1 {
+		return nil, ErrNotFound
+	}
+	sort.SliceStable(sliceNews, func(i, j int) bool {
+		if sliceNews[i].CreatedAt.Equal(sliceNews[j].CreatedAt) {
+			return sliceNews[i].ID < sliceNews[j].ID
+		}
+		return sliceNews[i].CreatedAt.After(sliceNews[j].CreatedAt)
+	})
+	return &sliceNews, nil
+}
+
+func (r *memoryRepository) UpdateNews(ctx context.Context, news *entities.News) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.news[news.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	if news.Version != 0 && news.Version != stored.Version {
+		return ErrVersionConflict
+	}
+	old := stored
+	stored.Update(*news)
+	stored.Slug = old.Slug
+	if ids := r.conflicts(&stored); len(ids) > 1 {
+		return ErrSlugTaken
+	}
+	r.store(stored)
+	stored = r.news[news.ID]
+	return r.record(ctx, &old, &stored)
+}
+
+// store replaces the stored news, keeping its slug and creation time.
+func (r *memoryRepository) store(news entities.News) {
+	stored := r.news[news.ID]
+	news.Slug = stored.Slug
+	news.CreatedAt = stored.CreatedAt
+	r.news[news.ID] = copyNews(news)
+}
+
+func (r *memoryRepository) SaveNews(ctx context.Context, sliceNews entities.SliceNews) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// changes are collected first so a failing news leaves everything untouched
+	saved := map[string]entities.News{}
+	for id, news := range r.news {
+		saved[id] = news
+	}
+	batch := &memoryRepository{news: saved}
+	var events entities.Events
+	var entries []*entities.AuditEntry
+	for _, news := range sliceNews {
+		var old *entities.News
+		ids := batch.conflicts(&news)
+		switch {
+		case len(ids) > 1:
+			return ErrIDSlugMismatch
+		case len(ids) == 1:
+			stored := batch.news[ids[0]]
+			old = &stored
+			news.ID = ids[0]
+			news.Version = stored.Version + 1
+			if news.TranslationGroupID == "" {
+				news.TranslationGroupID = stored.TranslationGroupID
+			}
+			batch.store(news)
+		default:
+			if news.TranslationGroupID == "" {
+				news.TranslationGroupID = news.ID
+			}
+			batch.news[news.ID] = copyNews(news)
+		}
+		news = batch.news[news.ID]
+		newsEvents, err := entities.NewsEvents(old, &news)
+		if err != nil {
+			return failure.InternalServerError.Wrap(err)
+		}
+		events = append(events, newsEvents...)
+		entry, err := auditEntry(ctx, old, &news)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+	r.news = saved
+	if r.Outbox != nil {
+		err := r.Outbox.Add(ctx, events...)
+		if err != nil {
+			return err
+		}
+	}
+	if r.Audit == nil {
+		return nil
+	}
+	for _, entry := range entries {
+		err := r.Audit.CreateEntry(ctx, entry)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) StreamNews(ctx context.Context, fn func(news *entities.News) error) error {
+	r.mu.RLock()
+	sliceNews := entities.SliceNews{}
+	for _, news := range r.news {
+		sliceNews = append(sliceNews, copyNews(news))
+	}
+	r.mu.RUnlock()
+
+	sort.SliceStable(sliceNews, func(i, j int) bool {
+		if sliceNews[i].CreatedAt.Equal(sliceNews[j].CreatedAt) {
+			return sliceNews[i].ID < sliceNews[j].ID
+		}
+		return sliceNews[i].CreatedAt.Before(sliceNews[j].CreatedAt)
+	})
+	for i := range sliceNews {
+		err := fn(&sliceNews[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) DeleteNews(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.news[id]
+	if !ok {
+		return ErrNotFound
+	}
+	old := stored
+	stored.Delete()
+	r.news[id] = stored
+	return r.record(ctx, &old, &stored)
+}
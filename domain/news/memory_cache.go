@@ -0,0 +1,90 @@
+package news
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"news/domain/entities"
+	"news/shared/Date"
+	"sync"
+	"time"
+)
+
+var errCacheMiss = errors.New("cache miss")
+
+type memoryCacheItem struct {
+	value   []byte
+	expires time.Time
+}
+
+// memoryCache is the Cache used without redis, values are stored as JSON so
+// callers never share the cached structs.
+type memoryCache struct {
+	mu      sync.Mutex
+	items   map[string]memoryCacheItem
+	expired time.Duration
+}
+
+func NewMemoryCache(expiredSeconds int) *memoryCache {
+	return &memoryCache{items: map[string]memoryCacheItem{}, expired: time.Duration(expiredSeconds) * time.Second}
+}
+
+func (c *memoryCache) set(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = memoryCacheItem{value: data, expires: Date.Now().Add(c.expired)}
+	return nil
+}
+
+func (c *memoryCache) get(key string, value interface{}) error {
+	c.mu.Lock()
+	item, ok := c.items[key]
+	if ok && c.expired > 0 && !Date.Now().Before(item.expires) {
+		delete(c.items, key)
+		ok = false
+	}
+	c.mu.Unlock()
+	if !ok {
+		return errCacheMiss
+	}
+	return json.Unmarshal(item.value, value)
+}
+
+func (c *memoryCache) SetSliceNews(ctx context.Context, key string, sliceNewsDto *entities.SliceNewsDto) error {
+	return c.set(key, sliceNewsDto)
+}
+
+func (c *memoryCache) SetNews(ctx context.Context, key string, newsDto *entities.NewsDto) error {
+	return c.set(key, newsDto)
+}
+
+func (c *memoryCache) GetSliceNews(ctx context.Context, key string) (sliceNewsDto *entities.SliceNewsDto, err error) {
+	sliceNewsDto = &entities.SliceNewsDto{}
+	err = c.get(key, sliceNewsDto)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (c *memoryCache) GetNews(ctx context.Context, key string) (newsDto *entities.NewsDto, err error) {
+	newsDto = &entities.NewsDto{}
+	err = c.get(key, newsDto)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+func (c *memoryCache) Delete(ctx context.Context, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, key := range keys {
+		delete(c.items, key)
+	}
+	return nil
+}
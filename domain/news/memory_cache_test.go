@@ -0,0 +1,42 @@
+package news_test
+
+import (
+	"context"
+	"github.com/magiconair/properties/assert"
+	"news/domain/entities"
+	"news/domain/news"
+	"news/shared/Date"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	Date.Now = func() time.Time {
+		return now
+	}
+	defer func() {
+		Date.Now = time.Now
+	}()
+	cache := news.NewMemoryCache(10)
+
+	_, err := cache.GetNews(ctx, "slug:derby")
+	assert.Equal(t, err != nil, true)
+
+	err = cache.SetNews(ctx, "slug:derby", &entities.NewsDto{ID: "1", Title: "derby"})
+	assert.Equal(t, err, nil)
+	err = cache.SetSliceNews(ctx, "all:", &entities.SliceNewsDto{{ID: "1"}, {ID: "2"}})
+	assert.Equal(t, err, nil)
+
+	newsDto, err := cache.GetNews(ctx, "slug:derby")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, newsDto.Title, "derby")
+	sliceNewsDto, err := cache.GetSliceNews(ctx, "all:")
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(*sliceNewsDto), 2)
+
+	now = now.Add(10 * time.Second)
+	_, err = cache.GetNews(ctx, "slug:derby")
+	assert.Equal(t, err != nil, true)
+}
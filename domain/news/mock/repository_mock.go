@@ -64,63 +64,136 @@ func (mr *MockRepositoryMockRecorder) DeleteNews(ctx, id interface{}) *gomock.Ca
 }
 
 // GetAllNews mocks base method.
-func (m *MockRepository) GetAllNews(ctx context.Context) (*entities.SliceNews, error) {
+func (m *MockRepository) GetAllNews(ctx context.Context, lang string) (*entities.SliceNews, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllNews", ctx)
+	ret := m.ctrl.Call(m, "GetAllNews", ctx, lang)
 	ret0, _ := ret[0].(*entities.SliceNews)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllNews indicates an expected call of GetAllNews.
-func (mr *MockRepositoryMockRecorder) GetAllNews(ctx interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetAllNews(ctx, lang interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllNews", reflect.TypeOf((*MockRepository)(nil).GetAllNews), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllNews", reflect.TypeOf((*MockRepository)(nil).GetAllNews), ctx, lang)
+}
+
+// GetNewsByID mocks base method.
+func (m *MockRepository) GetNewsByID(ctx context.Context, id string) (*entities.News, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNewsByID", ctx, id)
+	ret0, _ := ret[0].(*entities.News)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNewsByID indicates an expected call of GetNewsByID.
+func (mr *MockRepositoryMockRecorder) GetNewsByID(ctx, id interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNewsByID", reflect.TypeOf((*MockRepository)(nil).GetNewsByID), ctx, id)
 }
 
 // GetNewsBySlug mocks base method.
-func (m *MockRepository) GetNewsBySlug(ctx context.Context, slug string) (*entities.News, error) {
+func (m *MockRepository) GetNewsBySlug(ctx context.Context, slug, lang string) (*entities.News, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNewsBySlug", ctx, slug)
+	ret := m.ctrl.Call(m, "GetNewsBySlug", ctx, slug, lang)
 	ret0, _ := ret[0].(*entities.News)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetNewsBySlug indicates an expected call of GetNewsBySlug.
-func (mr *MockRepositoryMockRecorder) GetNewsBySlug(ctx, slug interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetNewsBySlug(ctx, slug, lang interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNewsBySlug", reflect.TypeOf((*MockRepository)(nil).GetNewsBySlug), ctx, slug)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNewsBySlug", reflect.TypeOf((*MockRepository)(nil).GetNewsBySlug), ctx, slug, lang)
 }
 
 // GetNewsByStatus mocks base method.
-func (m *MockRepository) GetNewsByStatus(ctx context.Context, status entities.NewsStatus) (*entities.SliceNews, error) {
+func (m *MockRepository) GetNewsByStatus(ctx context.Context, status entities.NewsStatus, lang string) (*entities.SliceNews, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNewsByStatus", ctx, status)
+	ret := m.ctrl.Call(m, "GetNewsByStatus", ctx, status, lang)
 	ret0, _ := ret[0].(*entities.SliceNews)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetNewsByStatus indicates an expected call of GetNewsByStatus.
-func (mr *MockRepositoryMockRecorder) GetNewsByStatus(ctx, status interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetNewsByStatus(ctx, status, lang interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNewsByStatus", reflect.TypeOf((*MockRepository)(nil).GetNewsByStatus), ctx, status)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNewsByStatus", reflect.TypeOf((*MockRepository)(nil).GetNewsByStatus), ctx, status, lang)
 }
 
 // GetNewsByTopic mocks base method.
-func (m *MockRepository) GetNewsByTopic(ctx context.Context, topic string) (*entities.SliceNews, error) {
+func (m *MockRepository) GetNewsByTopic(ctx context.Context, topic, lang string) (*entities.SliceNews, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetNewsByTopic", ctx, topic)
+	ret := m.ctrl.Call(m, "GetNewsByTopic", ctx, topic, lang)
 	ret0, _ := ret[0].(*entities.SliceNews)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetNewsByTopic indicates an expected call of GetNewsByTopic.
-func (mr *MockRepositoryMockRecorder) GetNewsByTopic(ctx, topic interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) GetNewsByTopic(ctx, topic, lang interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNewsByTopic", reflect.TypeOf((*MockRepository)(nil).GetNewsByTopic), ctx, topic, lang)
+}
+
+// GetNewsByTranslationGroup mocks base method.
+func (m *MockRepository) GetNewsByTranslationGroup(ctx context.Context, groupID string) (*entities.SliceNews, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNewsByTranslationGroup", ctx, groupID)
+	ret0, _ := ret[0].(*entities.SliceNews)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNewsByTranslationGroup indicates an expected call of GetNewsByTranslationGroup.
+func (mr *MockRepositoryMockRecorder) GetNewsByTranslationGroup(ctx, groupID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNewsByTranslationGroup", reflect.TypeOf((*MockRepository)(nil).GetNewsByTranslationGroup), ctx, groupID)
+}
+
+// SaveNews mocks base method.
+func (m *MockRepository) SaveNews(ctx context.Context, sliceNews entities.SliceNews) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveNews", ctx, sliceNews)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveNews indicates an expected call of SaveNews.
+func (mr *MockRepositoryMockRecorder) SaveNews(ctx, sliceNews interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveNews", reflect.TypeOf((*MockRepository)(nil).SaveNews), ctx, sliceNews)
+}
+
+// SearchNews mocks base method.
+func (m *MockRepository) SearchNews(ctx context.Context, query, lang string) (*entities.SliceNews, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchNews", ctx, query, lang)
+	ret0, _ := ret[0].(*entities.SliceNews)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchNews indicates an expected call of SearchNews.
+func (mr *MockRepositoryMockRecorder) SearchNews(ctx, query, lang interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchNews", reflect.TypeOf((*MockRepository)(nil).SearchNews), ctx, query, lang)
+}
+
+// StreamNews mocks base method.
+func (m *MockRepository) StreamNews(ctx context.Context, fn func(*entities.News) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StreamNews", ctx, fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StreamNews indicates an expected call of StreamNews.
+func (mr *MockRepositoryMockRecorder) StreamNews(ctx, fn interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNewsByTopic", reflect.TypeOf((*MockRepository)(nil).GetNewsByTopic), ctx, topic)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StreamNews", reflect.TypeOf((*MockRepository)(nil).StreamNews), ctx, fn)
 }
 
 // UpdateNews mocks base method.
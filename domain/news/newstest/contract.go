@@ -0,0 +1,620 @@
+// Package newstest holds the behaviour every news.Repository implementation
+// must have, backends run it from their own tests.
+package newstest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/magiconair/properties/assert"
+	"net/http"
+	"news/domain/audit"
+	"news/domain/entities"
+	"news/domain/news"
+	"news/domain/outbox"
+	"news/shared/failure"
+	"testing"
+	"time"
+)
+
+var baseTime = time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+// NewNews returns a news ready to be stored, created hours after baseTime.
+func NewNews(id string, title string, status entities.NewsStatus, hours int, tags ...string) *entities.News {
+	news := entities.NewNews(id, title, "", "content of "+title, status, tags, "football")
+	news.ContentFormat = entities.ContentPlain
+	news.Language = entities.DefaultLanguage
+	news.TranslationGroupID = id
+	news.CreatedAt = baseTime.Add(time.Duration(hours) * time.Hour)
+	news.UpdatedAt = news.CreatedAt
+	news.Summarize()
+	return news
+}
+
+// replacement returns the news an update of the news id writes, it replaces any version.
+func replacement(id string, title string, status entities.NewsStatus, tags ...string) *entities.News {
+	news := NewNews(id, title, status, 0, tags...)
+	news.Version = 0
+	return news
+}
+
+func ids(sliceNews *entities.SliceNews) (res []string) {
+	for _, news := range *sliceNews {
+		res = append(res, news.ID)
+	}
+	return
+}
+
+// RepositoryContract runs the suite, newRepo must return an empty repository on every call.
+func RepositoryContract(t *testing.T, newRepo func(t *testing.T) news.Repository) {
+	ctx := context.Background()
+
+	create := func(t *testing.T, repo news.Repository, sliceNews ...*entities.News) {
+		for _, news := range sliceNews {
+			err := repo.CreateNews(ctx, news)
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	t.Run("create and get by slug", func(t *testing.T) {
+		repo := newRepo(t)
+		expected := NewNews("id1", "first title", entities.NewsPublish, 0, "tag1", "tag2")
+		create(t, repo, expected)
+
+		actual, err := repo.GetNewsBySlug(ctx, "first-title", entities.DefaultLanguage)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, actual.ID, expected.ID)
+		assert.Equal(t, actual.Title, expected.Title)
+		assert.Equal(t, actual.Content, expected.Content)
+		assert.Equal(t, actual.Excerpt, expected.Excerpt)
+		assert.Equal(t, actual.Status, entities.NewsPublish)
+		assert.Equal(t, actual.Language, entities.DefaultLanguage)
+		assert.Equal(t, actual.CreatedAt.Equal(expected.CreatedAt), true)
+		assert.Equal(t, actual.UpdatedAt.Equal(expected.UpdatedAt), true)
+		assert.Equal(t, actual.Tags, []string{"tag1", "tag2"})
+	})
+
+	t.Run("duplicate slug in the same language is taken", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, NewNews("id1", "first title", entities.NewsPublish, 0, "tag1"))
+
+		err := repo.CreateNews(ctx, NewNews("id2", "first title", entities.NewsPublish, 1, "tag1"))
+		assert.Equal(t, errors.Is(err, news.ErrSlugTaken), true)
+
+		translation := NewNews("id3", "first title", entities.NewsPublish, 1, "tag1")
+		translation.Language = "en"
+		assert.Equal(t, repo.CreateNews(ctx, translation), nil)
+		// moving the translation back to the language of the first news
+		translation.Language = entities.DefaultLanguage
+		err = repo.UpdateNews(ctx, translation)
+		assert.Equal(t, errors.Is(err, news.ErrSlugTaken), true)
+	})
+
+	t.Run("get by slug filters language and status", func(t *testing.T) {
+		repo := newRepo(t)
+		translation := NewNews("id2", "first title", entities.NewsPublish, 1, "tag1")
+		translation.Language = "en"
+		create(t, repo,
+			NewNews("id1", "first title", entities.NewsPublish, 0, "tag1"),
+			translation,
+			NewNews("id3", "draft title", entities.NewsDraft, 0, "tag1"))
+
+		actual, err := repo.GetNewsBySlug(ctx, "first-title", "en")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, actual.ID, "id2")
+
+		// without language the newest news with the slug wins
+		actual, err = repo.GetNewsBySlug(ctx, "first-title", "")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, actual.ID, "id2")
+
+		_, err = repo.GetNewsBySlug(ctx, "draft-title", "")
+		assert.Equal(t, err, news.ErrNotFound)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetNewsBySlug(ctx, "first-title", "")
+		assert.Equal(t, err, news.ErrNotFound)
+		_, err = repo.GetAllNews(ctx, "")
+		assert.Equal(t, err, news.ErrNotFound)
+		_, err = repo.GetNewsByTopic(ctx, "football", "")
+		assert.Equal(t, err, news.ErrNotFound)
+		_, err = repo.GetNewsByStatus(ctx, entities.NewsDraft, "")
+		assert.Equal(t, err, news.ErrNotFound)
+		_, err = repo.GetNewsByTranslationGroup(ctx, "id1")
+		assert.Equal(t, err, news.ErrNotFound)
+		_, err = repo.SearchNews(ctx, "football", "")
+		assert.Equal(t, err, news.ErrNotFound)
+		err = repo.UpdateNews(ctx, replacement("id1", "updated title", entities.NewsPublish))
+		assert.Equal(t, err, news.ErrNotFound)
+		err = repo.DeleteNews(ctx, "id1")
+		assert.Equal(t, err, news.ErrNotFound)
+	})
+
+	t.Run("get all newest first", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo,
+			NewNews("id1", "first title", entities.NewsPublish, 0, "tag1"),
+			NewNews("id2", "second title", entities.NewsDraft, 2, "tag2"),
+			NewNews("id3", "third title", entities.NewsPublish, 1, "tag1", "tag2"))
+
+		actual, err := repo.GetAllNews(ctx, "")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids(actual), []string{"id2", "id3", "id1"})
+		assert.Equal(t, (*actual)[1].Tags, []string{"tag1", "tag2"})
+	})
+
+	t.Run("get by topic and status", func(t *testing.T) {
+		repo := newRepo(t)
+		other := NewNews("id3", "third title", entities.NewsPublish, 2, "tag1")
+		other.Topic = "tennis"
+		create(t, repo,
+			NewNews("id1", "first title", entities.NewsPublish, 0, "tag1"),
+			NewNews("id2", "second title", entities.NewsDraft, 1, "tag2"),
+			other)
+
+		actual, err := repo.GetNewsByTopic(ctx, "football", "")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids(actual), []string{"id1"})
+
+		actual, err = repo.GetNewsByStatus(ctx, entities.NewsDraft, "")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids(actual), []string{"id2"})
+	})
+
+	t.Run("news without tags", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, NewNews("id1", "first title", entities.NewsPublish, 0))
+
+		actual, err := repo.GetNewsBySlug(ctx, "first-title", "")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(actual.Tags), 0)
+
+		all, err := repo.GetAllNews(ctx, "")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len((*all)[0].Tags), 0)
+	})
+
+	t.Run("get by translation group", func(t *testing.T) {
+		repo := newRepo(t)
+		translation := NewNews("id2", "first title", entities.NewsDraft, 1, "tag2")
+		translation.Language = "en"
+		translation.TranslationGroupID = "id1"
+		deleted := NewNews("id3", "first title", entities.NewsDeleted, 2)
+		deleted.Language = "fr"
+		deleted.TranslationGroupID = "id1"
+		create(t, repo, NewNews("id1", "first title", entities.NewsPublish, 0, "tag1"), translation, deleted)
+
+		actual, err := repo.GetNewsByTranslationGroup(ctx, "id1")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids(actual), []string{"id2", "id1"})
+		assert.Equal(t, (*actual)[0].Tags, []string{"tag2"})
+		assert.Equal(t, (*actual)[1].Tags, []string{"tag1"})
+	})
+
+	t.Run("update replaces tags", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, NewNews("id1", "first title", entities.NewsPublish, 0, "tag1", "tag2"))
+
+		err := repo.UpdateNews(ctx, replacement("id1", "updated title", entities.NewsPublish, "tag3"))
+		assert.Equal(t, err, nil)
+
+		actual, err := repo.GetNewsBySlug(ctx, "first-title", "")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, actual.Title, "updated title")
+		assert.Equal(t, actual.Tags, []string{"tag3"})
+	})
+
+	t.Run("update replaces every field but the slug and creation time", func(t *testing.T) {
+		repo := newRepo(t)
+		expected := NewNews("id1", "first title", entities.NewsPublish, 0, "tag1", "tag2")
+		create(t, repo, expected)
+
+		update := replacement("id1", "first title", entities.NewsPublish)
+		update.Slug, update.Content, update.CreatedAt = "other-slug", "updated content", baseTime.Add(time.Hour)
+		err := repo.UpdateNews(ctx, update)
+		assert.Equal(t, err, nil)
+
+		actual, err := repo.GetNewsBySlug(ctx, "first-title", "")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, actual.Content, "updated content")
+		assert.Equal(t, actual.Excerpt, "updated content")
+		assert.Equal(t, actual.CreatedAt.Equal(expected.CreatedAt), true)
+		assert.Equal(t, actual.UpdatedAt.After(expected.UpdatedAt), true)
+		assert.Equal(t, len(actual.Tags), 0)
+	})
+
+	t.Run("writes increment the version", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, NewNews("id1", "first title", entities.NewsPublish, 0, "tag1"))
+
+		actual, err := repo.GetNewsByID(ctx, "id1")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, actual.Version, 1)
+
+		update := replacement("id1", "updated title", entities.NewsPublish, "tag1")
+		update.Version = 1
+		err = repo.UpdateNews(ctx, update)
+		assert.Equal(t, err, nil)
+		err = repo.DeleteNews(ctx, "id1")
+		assert.Equal(t, err, nil)
+
+		actual, err = repo.GetNewsByID(ctx, "id1")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, actual.Version, 3)
+	})
+
+	t.Run("update with a stale version conflicts", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, NewNews("id1", "first title", entities.NewsPublish, 0, "tag1"))
+		err := repo.UpdateNews(ctx, replacement("id1", "updated title", entities.NewsPublish, "tag1"))
+		assert.Equal(t, err, nil)
+
+		stale := replacement("id1", "stale title", entities.NewsPublish, "tag1")
+		stale.Version = 1
+		err = repo.UpdateNews(ctx, stale)
+		assert.Equal(t, err, news.ErrVersionConflict)
+
+		actual, err := repo.GetNewsByID(ctx, "id1")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, actual.Title, "updated title")
+		assert.Equal(t, actual.Version, 2)
+	})
+
+	t.Run("soft delete", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo,
+			NewNews("id1", "first title", entities.NewsPublish, 0, "tag1"),
+			NewNews("id2", "second title", entities.NewsPublish, 1, "tag1"))
+
+		err := repo.DeleteNews(ctx, "id1")
+		assert.Equal(t, err, nil)
+
+		all, err := repo.GetAllNews(ctx, "")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids(all), []string{"id2"})
+
+		_, err = repo.GetNewsBySlug(ctx, "first-title", "")
+		assert.Equal(t, err, news.ErrNotFound)
+
+		deleted, err := repo.GetNewsByStatus(ctx, entities.NewsDeleted, "")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids(deleted), []string{"id1"})
+		assert.Equal(t, (*deleted)[0].DeletedAt != nil, true)
+		assert.Equal(t, (*deleted)[0].UpdatedAt.Equal(*(*deleted)[0].DeletedAt), true)
+		assert.Equal(t, (*deleted)[0].Tags, []string{"tag1"})
+	})
+
+	t.Run("get by id whatever the status", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo,
+			NewNews("id1", "first title", entities.NewsDraft, 0, "tag1"),
+			NewNews("id2", "second title", entities.NewsPublish, 1))
+		err := repo.DeleteNews(ctx, "id2")
+		assert.Equal(t, err, nil)
+
+		actual, err := repo.GetNewsByID(ctx, "id1")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, actual.Slug, "first-title")
+		assert.Equal(t, actual.Tags, []string{"tag1"})
+
+		actual, err = repo.GetNewsByID(ctx, "id2")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, actual.Status, entities.NewsDeleted)
+
+		_, err = repo.GetNewsByID(ctx, "missing")
+		assert.Equal(t, err, news.ErrNotFound)
+	})
+
+	t.Run("search title and topic", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo,
+			NewNews("id1", "derby tonight", entities.NewsPublish, 0, "tag1"),
+			NewNews("id2", "derby draft", entities.NewsDraft, 1, "tag1"),
+			NewNews("id3", "election result", entities.NewsPublish, 2, "tag1"))
+
+		actual, err := repo.SearchNews(ctx, "derby", "")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids(actual), []string{"id1"})
+
+		actual, err = repo.SearchNews(ctx, "football", "")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids(actual), []string{"id3", "id1"})
+	})
+
+	t.Run("save upserts and stream reads oldest first", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, NewNews("id1", "first title", entities.NewsPublish, 1, "tag1"))
+
+		bySlug := NewNews("other", "first title", entities.NewsDraft, 1, "tag2")
+		bySlug.Title = "saved title"
+		bySlug.TranslationGroupID = ""
+		created := NewNews("id2", "second title", entities.NewsPublish, 0)
+		created.TranslationGroupID = ""
+		err := repo.SaveNews(ctx, entities.SliceNews{*bySlug, *created})
+		assert.Equal(t, err, nil)
+
+		var streamed entities.SliceNews
+		err = repo.StreamNews(ctx, func(news *entities.News) error {
+			streamed = append(streamed, *news)
+			return nil
+		})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids(&streamed), []string{"id2", "id1"})
+		assert.Equal(t, streamed[1].Title, "saved title")
+		assert.Equal(t, streamed[1].Tags, []string{"tag2"})
+		assert.Equal(t, []string{streamed[0].TranslationGroupID, streamed[1].TranslationGroupID}, []string{"id2", "id1"})
+	})
+
+	t.Run("save rolls back the whole batch", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo,
+			NewNews("id1", "first title", entities.NewsPublish, 0, "tag1"),
+			NewNews("id2", "second title", entities.NewsPublish, 1, "tag1"))
+
+		// id1 with the slug of id2 matches two different news
+		conflict := NewNews("id1", "second title", entities.NewsPublish, 0, "tag1")
+		err := repo.SaveNews(ctx, entities.SliceNews{*NewNews("id3", "third title", entities.NewsPublish, 2), *conflict})
+		assert.Equal(t, err, news.ErrIDSlugMismatch)
+
+		actual, err := repo.GetAllNews(ctx, "")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, ids(actual), []string{"id2", "id1"})
+	})
+}
+
+// pending returns the type and news id of every unpublished event, oldest first.
+func pending(t *testing.T, box outbox.Repository) (res []string) {
+	events, err := box.GetPending(context.Background(), 100)
+	if err != nil && failure.GetStatus(err) != http.StatusNotFound {
+		t.Fatal(err)
+	}
+	if events == nil {
+		return
+	}
+	for _, event := range *events {
+		res = append(res, string(event.Type)+" "+event.AggregateID)
+	}
+	return
+}
+
+// OutboxContract checks the events a repository writes to its outbox, newRepo must
+// return an empty repository and the outbox it writes to on every call.
+func OutboxContract(t *testing.T, newRepo func(t *testing.T) (news.Repository, outbox.Repository)) {
+	ctx := context.Background()
+
+	t.Run("create, update and delete", func(t *testing.T) {
+		repo, box := newRepo(t)
+		err := repo.CreateNews(ctx, NewNews("id1", "first title", entities.NewsPublish, 0, "tag1"))
+		assert.Equal(t, err, nil)
+		err = repo.UpdateNews(ctx, replacement("id1", "first title", entities.NewsDraft, "tag1"))
+		assert.Equal(t, err, nil)
+		err = repo.UpdateNews(ctx, replacement("id1", "updated title", entities.NewsPublish, "tag1"))
+		assert.Equal(t, err, nil)
+		err = repo.DeleteNews(ctx, "id1")
+		assert.Equal(t, err, nil)
+
+		assert.Equal(t, pending(t, box), []string{"NewsCreated id1", "NewsPublished id1", "NewsUpdated id1",
+			"NewsUnpublished id1", "NewsUpdated id1", "NewsPublished id1", "NewsDeleted id1"})
+
+		events, err := box.GetPending(ctx, 100)
+		assert.Equal(t, err, nil)
+		var payload entities.NewsDto
+		err = json.Unmarshal([]byte((*events)[4].Payload), &payload)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, payload.Title, "updated title")
+		assert.Equal(t, payload.Slug, "first-title")
+		assert.Equal(t, payload.Tags, []string{"tag1"})
+	})
+
+	t.Run("failed change writes no event", func(t *testing.T) {
+		repo, box := newRepo(t)
+		err := repo.CreateNews(ctx, NewNews("id1", "first title", entities.NewsDraft, 0, "tag1"))
+		assert.Equal(t, err, nil)
+
+		err = repo.CreateNews(ctx, NewNews("id2", "first title", entities.NewsDraft, 1, "tag1"))
+		assert.Equal(t, err != nil, true)
+		// id1 with the slug of id3 matches two different news
+		err = repo.SaveNews(ctx, entities.SliceNews{*NewNews("id3", "third title", entities.NewsDraft, 2),
+			*NewNews("id1", "third title", entities.NewsDraft, 0)})
+		assert.Equal(t, err != nil, true)
+
+		assert.Equal(t, pending(t, box), []string{"NewsCreated id1"})
+	})
+
+	t.Run("save", func(t *testing.T) {
+		repo, box := newRepo(t)
+		err := repo.CreateNews(ctx, NewNews("id1", "first title", entities.NewsDraft, 0, "tag1"))
+		assert.Equal(t, err, nil)
+
+		err = repo.SaveNews(ctx, entities.SliceNews{*NewNews("other", "first title", entities.NewsPublish, 0, "tag1"),
+			*NewNews("id2", "second title", entities.NewsDraft, 1)})
+		assert.Equal(t, err, nil)
+
+		assert.Equal(t, pending(t, box), []string{"NewsCreated id1", "NewsUpdated id1", "NewsPublished id1",
+			"NewsCreated id2"})
+	})
+
+	t.Run("published events leave the pending list", func(t *testing.T) {
+		repo, box := newRepo(t)
+		err := repo.CreateNews(ctx, NewNews("id1", "first title", entities.NewsDraft, 0, "tag1"))
+		assert.Equal(t, err, nil)
+		err = repo.CreateNews(ctx, NewNews("id2", "second title", entities.NewsDraft, 1, "tag1"))
+		assert.Equal(t, err, nil)
+
+		events, err := box.GetPending(ctx, 1)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(*events), 1)
+		err = box.MarkFailed(ctx, (*events)[0].ID)
+		assert.Equal(t, err, nil)
+		err = box.MarkPublished(ctx, (*events)[0].ID, baseTime)
+		assert.Equal(t, err, nil)
+
+		events, err = box.GetPending(ctx, 100)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(*events), 1)
+		assert.Equal(t, (*events)[0].AggregateID, "id2")
+		assert.Equal(t, (*events)[0].Attempts, 0)
+	})
+
+	t.Run("claimed events are leased to one relay", func(t *testing.T) {
+		repo, box := newRepo(t)
+		err := repo.CreateNews(ctx, NewNews("id1", "first title", entities.NewsDraft, 0, "tag1"))
+		assert.Equal(t, err, nil)
+		events, err := box.GetPending(ctx, 1)
+		assert.Equal(t, err, nil)
+		id := (*events)[0].ID
+
+		claimed, err := box.Claim(ctx, id, baseTime, baseTime.Add(time.Minute))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, claimed, true)
+		claimed, err = box.Claim(ctx, id, baseTime.Add(time.Second), baseTime.Add(time.Minute))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, claimed, false)
+		// the lease is over, another relay takes the event over
+		claimed, err = box.Claim(ctx, id, baseTime.Add(time.Hour), baseTime.Add(time.Hour+time.Minute))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, claimed, true)
+
+		err = box.MarkFailed(ctx, id)
+		assert.Equal(t, err, nil)
+		claimed, err = box.Claim(ctx, id, baseTime.Add(time.Hour), baseTime.Add(time.Hour+time.Minute))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, claimed, true)
+
+		err = box.MarkDead(ctx, id, baseTime)
+		assert.Equal(t, err, nil)
+		_, err = box.GetPending(ctx, 100)
+		assert.Equal(t, failure.GetStatus(err), http.StatusNotFound)
+		claimed, err = box.Claim(ctx, id, baseTime.Add(2*time.Hour), baseTime.Add(3*time.Hour))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, claimed, false)
+	})
+
+	t.Run("purge deletes old published and dead events only", func(t *testing.T) {
+		repo, box := newRepo(t)
+		for i, id := range []string{"id1", "id2", "id3"} {
+			err := repo.CreateNews(ctx, NewNews(id, id+" title", entities.NewsDraft, i, "tag1"))
+			assert.Equal(t, err, nil)
+		}
+		events, err := box.GetPending(ctx, 100)
+		assert.Equal(t, err, nil)
+		err = box.MarkPublished(ctx, (*events)[0].ID, baseTime)
+		assert.Equal(t, err, nil)
+		err = box.MarkDead(ctx, (*events)[1].ID, baseTime)
+		assert.Equal(t, err, nil)
+
+		// the events are written at the time of the change
+		deleted, err := box.Purge(ctx, time.Now().Add(-time.Hour))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, deleted, int64(0))
+		deleted, err = box.Purge(ctx, time.Now().Add(time.Hour))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, deleted, int64(2))
+		assert.Equal(t, pending(t, box), []string{"NewsCreated id3"})
+
+		err = repo.CreateNews(ctx, NewNews("id4", "fourth title", entities.NewsDraft, 3, "tag1"))
+		assert.Equal(t, err, nil)
+		events, err = box.GetPending(ctx, 100)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, (*events)[1].Sequence > (*events)[0].Sequence, true)
+	})
+}
+
+// AuditContract checks the audit log entries a repository writes with its changes,
+// newRepo must return an empty repository and the audit log it writes to on every call.
+func AuditContract(t *testing.T, newRepo func(t *testing.T) (news.Repository, audit.Repository)) {
+	ctx := audit.NewContext(context.Background(), audit.Metadata{Actor: "budi", IP: "10.0.0.1", RequestID: "request1"})
+
+	entries := func(t *testing.T, log audit.Repository) map[string]entities.AuditEntry {
+		stored, err := log.GetEntries(ctx, &entities.AuditFilter{EntityType: entities.AuditNews, Limit: 100})
+		if err != nil && failure.GetStatus(err) != http.StatusNotFound {
+			t.Fatal(err)
+		}
+		actions := map[string]entities.AuditEntry{}
+		if stored == nil {
+			return actions
+		}
+		for _, entry := range *stored {
+			actions[entry.Action+" "+entry.EntityID] = entry
+		}
+		return actions
+	}
+	snapshot := func(t *testing.T, value *string) (dto entities.NewsDto) {
+		err := json.Unmarshal([]byte(*value), &dto)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	t.Run("create, update and delete", func(t *testing.T) {
+		repo, log := newRepo(t)
+		err := repo.CreateNews(ctx, NewNews("id1", "first title", entities.NewsDraft, 0, "tag1"))
+		assert.Equal(t, err, nil)
+		err = repo.UpdateNews(ctx, replacement("id1", "updated title", entities.NewsPublish, "tag1"))
+		assert.Equal(t, err, nil)
+		err = repo.DeleteNews(ctx, "id1")
+		assert.Equal(t, err, nil)
+
+		actions := entries(t, log)
+		assert.Equal(t, len(actions), 3)
+		for _, entry := range actions {
+			assert.Equal(t, entry.Actor, "budi")
+			assert.Equal(t, entry.IP, "10.0.0.1")
+			assert.Equal(t, entry.RequestID, "request1")
+		}
+		assert.Equal(t, actions["create id1"].Before == nil, true)
+		assert.Equal(t, snapshot(t, actions["create id1"].After).Status, "draft")
+		assert.Equal(t, snapshot(t, actions["update id1"].Before).Title, "first title")
+		assert.Equal(t, snapshot(t, actions["update id1"].After).Title, "updated title")
+		assert.Equal(t, snapshot(t, actions["delete id1"].Before).Status, "publish")
+		assert.Equal(t, actions["delete id1"].After == nil, true)
+	})
+
+	t.Run("failed change writes no entry", func(t *testing.T) {
+		repo, log := newRepo(t)
+		err := repo.CreateNews(ctx, NewNews("id1", "first title", entities.NewsDraft, 0, "tag1"))
+		assert.Equal(t, err, nil)
+
+		err = repo.CreateNews(ctx, NewNews("id2", "first title", entities.NewsDraft, 1, "tag1"))
+		assert.Equal(t, err != nil, true)
+		err = repo.DeleteNews(ctx, "missing")
+		assert.Equal(t, err != nil, true)
+		// id1 with the slug of id3 matches two different news
+		err = repo.SaveNews(ctx, entities.SliceNews{*NewNews("id3", "third title", entities.NewsDraft, 2),
+			*NewNews("id1", "third title", entities.NewsDraft, 0)})
+		assert.Equal(t, err != nil, true)
+
+		actions := entries(t, log)
+		assert.Equal(t, len(actions), 1)
+		assert.Equal(t, actions["create id1"].EntityID, "id1")
+	})
+
+	t.Run("save", func(t *testing.T) {
+		repo, log := newRepo(t)
+		err := repo.CreateNews(ctx, NewNews("id1", "first title", entities.NewsDraft, 0, "tag1"))
+		assert.Equal(t, err, nil)
+
+		err = repo.SaveNews(ctx, entities.SliceNews{*NewNews("other", "first title", entities.NewsPublish, 0, "tag1"),
+			*NewNews("id2", "second title", entities.NewsDraft, 1)})
+		assert.Equal(t, err, nil)
+
+		actions := entries(t, log)
+		assert.Equal(t, len(actions), 3)
+		assert.Equal(t, snapshot(t, actions["update id1"].After).Status, "publish")
+		// the stored news is recorded whole
+		before := snapshot(t, actions["update id1"].Before)
+		assert.Equal(t, before.Status, "draft")
+		assert.Equal(t, before.Title, "first title")
+		assert.Equal(t, before.Content, "content of first title")
+		assert.Equal(t, before.Topic, "football")
+		assert.Equal(t, before.Language, entities.DefaultLanguage)
+		assert.Equal(t, before.Tags, []string{"tag1"})
+		assert.Equal(t, actions["create id2"].Before == nil, true)
+	})
+}
@@ -0,0 +1,23 @@
+package news
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"news/domain/entities"
+)
+
+// postgresRepository shares the SQL of repository, queries are rebound to $n
+// placeholders and double quoted identifiers. Only search differs: Postgres
+// matches words through the `search` tsvector column instead of LIKE.
+type postgresRepository struct {
+	*repository
+}
+
+func NewPostgresRepository(DB *sqlx.DB) *postgresRepository {
+	return &postgresRepository{repository: NewRepository(DB)}
+}
+
+func (r *postgresRepository) SearchNews(ctx context.Context, query string, lang string) (sliceNews *entities.SliceNews, err error) {
+	where, args := languageFilter("WHERE search @@ plainto_tsquery('simple', ?) AND status = ?", lang, query, entities.NewsPublish)
+	return r.selectNewsWithTags(ctx, where, args...)
+}
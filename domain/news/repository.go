@@ -4,22 +4,59 @@ package news
 
 import (
 	"context"
+	"errors"
 	"github.com/jmoiron/sqlx"
+	"net/http"
+	"news/domain/audit"
 	"news/domain/entities"
+	"news/domain/outbox"
+	"news/shared/database"
 	"news/shared/failure"
 	"news/shared/logger"
 )
 
 type Repository interface {
 	CreateNews(ctx context.Context, news *entities.News) error
-	GetNewsBySlug(ctx context.Context, slug string) (*entities.News, error)
-	GetNewsByTopic(ctx context.Context, topic string) (*entities.SliceNews, error)
-	GetNewsByStatus(ctx context.Context, status entities.NewsStatus) (*entities.SliceNews, error)
-	GetAllNews(ctx context.Context) (*entities.SliceNews, error)
+	GetNewsByID(ctx context.Context, id string) (*entities.News, error)
+	GetNewsBySlug(ctx context.Context, slug string, lang string) (*entities.News, error)
+	GetNewsByTopic(ctx context.Context, topic string, lang string) (*entities.SliceNews, error)
+	GetNewsByStatus(ctx context.Context, status entities.NewsStatus, lang string) (*entities.SliceNews, error)
+	GetNewsByTranslationGroup(ctx context.Context, groupID string) (*entities.SliceNews, error)
+	GetAllNews(ctx context.Context, lang string) (*entities.SliceNews, error)
+	SearchNews(ctx context.Context, query string, lang string) (*entities.SliceNews, error)
 	UpdateNews(ctx context.Context, news *entities.News) error
+	SaveNews(ctx context.Context, sliceNews entities.SliceNews) error
+	StreamNews(ctx context.Context, fn func(news *entities.News) error) error
 	DeleteNews(ctx context.Context, id string) error
 }
 
+const newsColumns = "id, title, slug, content, content_format, excerpt, word_count, reading_time_minutes, topic, " +
+	"language, translation_group_id, status, version, featured_image_id, `createdAt`, `updatedAt`, `deletedAt`"
+
+var (
+	ErrNotFound = failure.New(http.StatusNotFound, "news.not_found", "news not found")
+	// ErrVersionConflict is returned by writes expecting a version of the news
+	// that is not the stored one anymore.
+	ErrVersionConflict = failure.New(http.StatusConflict, "news.version_conflict", "news was changed by another write")
+	// ErrSlugTaken is returned by writes of a news with the slug and language of another news.
+	ErrSlugTaken = failure.New(http.StatusConflict, "news.slug_taken", "a news with this slug already exists in this language")
+	// ErrTranslationGroupNotFound is returned by writes of a news joining a translation group no news is in.
+	ErrTranslationGroupNotFound = failure.New(http.StatusBadRequest, "news.translation_group_not_found",
+		"translation group not found")
+	// ErrTranslationExists is returned by writes of a news joining a translation group that has a news in its language.
+	ErrTranslationExists = failure.New(http.StatusConflict, "news.translation_exists",
+		"the translation group already has a news in this language")
+	// ErrFeaturedImageNotFound is returned by writes of a news with a featured image no media has the id of.
+	ErrFeaturedImageNotFound = failure.New(http.StatusBadRequest, "news.featured_image_not_found",
+		"featured image not found")
+	// ErrIDSlugMismatch is returned by saves of a news whose id and slug match two different news.
+	ErrIDSlugMismatch = failure.New(http.StatusBadRequest, "news.id_slug_mismatch",
+		"id and slug belong to different news")
+
+	// errTagsNotFound is returned by the reads of the tags of news without any.
+	errTagsNotFound = failure.New(http.StatusNotFound, "news.tags_not_found", "tags not found")
+)
+
 type repository struct {
 	DB *sqlx.DB
 }
@@ -31,16 +68,21 @@ func NewRepository(DB *sqlx.DB) *repository {
 func (r *repository) CreateNews(ctx context.Context, news *entities.News) (err error) {
 	tx, err := r.DB.BeginTxx(ctx, nil)
 	if err != nil {
-		logger.ErrorWithStack(err)
-		err = failure.InternalServerError
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
+		return
+	}
+	err = r.insertNews(ctx, tx, news)
+	if err != nil {
+		tx.Rollback()
 		return
 	}
-	err = r.insertNews(tx, news)
+	err = r.insertNewsTags(ctx, tx, news)
 	if err != nil {
 		tx.Rollback()
 		return
 	}
-	err = r.insertNewsTags(tx, news)
+	err = r.record(ctx, tx, nil, news)
 	if err != nil {
 		tx.Rollback()
 		return
@@ -49,92 +91,121 @@ func (r *repository) CreateNews(ctx context.Context, news *entities.News) (err e
 	return
 }
 
-func (r *repository) insertNews(tx *sqlx.Tx, news *entities.News) (err error) {
-	query := "INSERT INTO `news`(`id`, `title`, `slug`, `content`, `topic`, `status`, `createdAt`) " +
-		"VALUES (:id, :title, :slug, :content, :topic, :status, :createdAt)"
-	stmt, err := tx.PrepareNamed(query)
+func (r *repository) insertNews(ctx context.Context, tx *sqlx.Tx, news *entities.News) (err error) {
+	query := "INSERT INTO `news`(`id`, `title`, `slug`, `content`, `content_format`, `excerpt`, `word_count`, " +
+		"`reading_time_minutes`, `topic`, `language`, `translation_group_id`, `status`, `version`, " +
+		"`featured_image_id`, `createdAt`, `updatedAt`) VALUES (:id, :title, :slug, :content, :content_format, :excerpt, " +
+		":word_count, :reading_time_minutes, :topic, :language, :translation_group_id, :status, :version, " +
+		":featured_image_id, :createdAt, :updatedAt)"
+	stmt, err := tx.PrepareNamed(database.Rebind(tx, query))
 	if err != nil {
-		logger.ErrorWithStack(err)
-		err = failure.InternalServerError
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
 		return
 	}
 	_, err = stmt.Exec(news)
+	if database.IsUniqueViolation(err) {
+		return ErrSlugTaken.Wrap(err)
+	}
 	if err != nil {
-		logger.ErrorWithStack(err)
-		err = failure.InternalServerError
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
 		return
 	}
 	return
 }
 
-func (r *repository) insertNewsTags(tx *sqlx.Tx, news *entities.News) (err error) {
+func (r *repository) insertNewsTags(ctx context.Context, tx *sqlx.Tx, news *entities.News) (err error) {
 	query := "INSERT INTO `news_tags`(`news_id`, `tag_id`) VALUES(:news_id, :tag_id)"
-	stmt, err := tx.PrepareNamed(query)
+	stmt, err := tx.PrepareNamed(database.Rebind(tx, query))
 	if err != nil {
-		logger.ErrorWithStack(err)
-		err = failure.InternalServerError
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
 		return
 	}
 	sliceNewsTag := news.ToSliceNewsTag()
 	for _, newsTag := range sliceNewsTag {
 		_, err = stmt.Exec(newsTag)
 		if err != nil {
-			logger.ErrorWithStack(err)
-			err = failure.InternalServerError
+			logger.ErrorWithStack(ctx, err)
+			err = failure.InternalServerError.Wrap(err)
 			break
 		}
 	}
 	return
 }
 
-func (r *repository) GetNewsBySlug(ctx context.Context, slug string) (news *entities.News, err error) {
-	sliceNews, err := r.selectNews(ctx, "WHERE slug = ? AND status = ?", slug, entities.NewsPublish)
-	if err != nil {
-		return
-	}
-	news = &(*sliceNews)[0]
-	tags, err := r.selectNewsTag(ctx, "WHERE news_id = ?", news.ID)
+// GetNewsBySlug returns the newest published news with the slug when lang is empty,
+// slugs are only unique per language.
+func (r *repository) GetNewsBySlug(ctx context.Context, slug string, lang string) (news *entities.News, err error) {
+	where, args := languageFilter("WHERE slug = ? AND status = ?", lang, slug, entities.NewsPublish)
+	sliceNews, err := r.selectNews(ctx, where, args...)
 	if err != nil {
 		return
 	}
-	news.Tags = tags.ToMapTag()[news.ID]
-	return
+	return r.withTags(ctx, &(*sliceNews)[0])
 }
 
-func (r *repository) GetNewsByTopic(ctx context.Context, topic string) (sliceNews *entities.SliceNews, err error) {
-	sliceNews, err = r.selectNews(ctx, "WHERE topic = ? and status = ?", topic, entities.NewsPublish)
-	if err != nil {
-		return
-	}
-	tags, err := r.selectNewsTagByNewsIds(ctx, extractNewsId(sliceNews))
+// GetNewsByID returns the news whatever its status, deleted ones included.
+func (r *repository) GetNewsByID(ctx context.Context, id string) (news *entities.News, err error) {
+	sliceNews, err := r.selectNews(ctx, "WHERE id = ?", id)
 	if err != nil {
 		return
 	}
-	compositeNewsTags(sliceNews, tags)
-	return
+	return r.withTags(ctx, &(*sliceNews)[0])
 }
 
-func (r *repository) GetNewsByStatus(ctx context.Context, status entities.NewsStatus) (sliceNews *entities.SliceNews, err error) {
-	sliceNews, err = r.selectNews(ctx, "WHERE status = ?", status)
-	if err != nil {
-		return
-	}
-	tags, err := r.selectNewsTagByNewsIds(ctx, extractNewsId(sliceNews))
+func (r *repository) withTags(ctx context.Context, news *entities.News) (*entities.News, error) {
+	tags, err := r.selectNewsTag(ctx, "WHERE news_id = ?", news.ID)
 	if err != nil {
-		return
+		if !errors.Is(err, errTagsNotFound) {
+			return nil, err
+		}
+		return news, nil
 	}
-	compositeNewsTags(sliceNews, tags)
-	return
+	news.Tags = tags.ToMapTag()[news.ID]
+	return news, nil
 }
 
-func (r *repository) GetAllNews(ctx context.Context) (sliceNews *entities.SliceNews, err error) {
-	sliceNews, err = r.selectNews(ctx, "WHERE status <> ?", entities.NewsDeleted)
+func (r *repository) GetNewsByTopic(ctx context.Context, topic string, lang string) (sliceNews *entities.SliceNews, err error) {
+	where, args := languageFilter("WHERE topic = ? and status = ?", lang, topic, entities.NewsPublish)
+	return r.selectNewsWithTags(ctx, where, args...)
+}
+
+func (r *repository) GetNewsByStatus(ctx context.Context, status entities.NewsStatus, lang string) (sliceNews *entities.SliceNews, err error) {
+	where, args := languageFilter("WHERE status = ?", lang, status)
+	return r.selectNewsWithTags(ctx, where, args...)
+}
+
+// GetNewsByTranslationGroup returns the news of the group in every status but deleted.
+func (r *repository) GetNewsByTranslationGroup(ctx context.Context, groupID string) (sliceNews *entities.SliceNews, err error) {
+	return r.selectNewsWithTags(ctx, "WHERE translation_group_id = ? AND status <> ?", groupID, entities.NewsDeleted)
+}
+
+func (r *repository) GetAllNews(ctx context.Context, lang string) (sliceNews *entities.SliceNews, err error) {
+	where, args := languageFilter("WHERE status <> ?", lang, entities.NewsDeleted)
+	return r.selectNewsWithTags(ctx, where, args...)
+}
+
+// SearchNews finds published news with the query in their title or topic.
+func (r *repository) SearchNews(ctx context.Context, query string, lang string) (sliceNews *entities.SliceNews, err error) {
+	like := "%" + query + "%"
+	where, args := languageFilter("WHERE (title LIKE ? OR topic LIKE ?) AND status = ?", lang, like, like, entities.NewsPublish)
+	return r.selectNewsWithTags(ctx, where, args...)
+}
+
+func (r *repository) selectNewsWithTags(ctx context.Context, where string, args ...interface{}) (sliceNews *entities.SliceNews, err error) {
+	sliceNews, err = r.selectNews(ctx, where, args...)
 	if err != nil {
 		return
 	}
 	tags, err := r.selectNewsTagByNewsIds(ctx, extractNewsId(sliceNews))
 	if err != nil {
-		return
+		// news without any tag are still returned
+		if !errors.Is(err, errTagsNotFound) {
+			return nil, err
+		}
+		return sliceNews, nil
 	}
 	compositeNewsTags(sliceNews, tags)
 	return
@@ -142,15 +213,18 @@ func (r *repository) GetAllNews(ctx context.Context) (sliceNews *entities.SliceN
 
 func (r *repository) selectNews(ctx context.Context, where string, args ...interface{}) (news *entities.SliceNews, err error) {
 	news = new(entities.SliceNews)
-	query := "SELECT id, title, slug, content, topic, status, createdAt FROM `news` " + where + " ORDER BY createdAt desc"
-	err = r.DB.SelectContext(ctx, news, query, args...)
+	query := "SELECT " + newsColumns + ", " +
+		"(SELECT COUNT(*) FROM `comments` WHERE `comments`.news_id = `news`.id AND `comments`.status = ?) AS `commentCount` " +
+		"FROM `news` " + where + " ORDER BY `createdAt` desc"
+	args = append([]interface{}{entities.CommentApproved}, args...)
+	err = r.DB.SelectContext(ctx, news, database.Rebind(r.DB, query), args...)
 	if err != nil {
-		logger.ErrorWithStack(err)
-		err = failure.InternalServerError
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
 		return
 	}
 	if len(*news) < 1 {
-		err = failure.NotFound("news not found")
+		err = ErrNotFound
 		return
 	}
 	return
@@ -159,8 +233,8 @@ func (r *repository) selectNews(ctx context.Context, where string, args ...inter
 func (r *repository) selectNewsTagByNewsIds(ctx context.Context, ids []string) (tags *entities.SliceNewsTag, err error) {
 	where, args, err := sqlx.In("WHERE `news_id` IN (?)", ids)
 	if err != nil {
-		logger.ErrorWithStack(err)
-		err = failure.InternalServerError
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
 		return
 	}
 	return r.selectNewsTag(ctx, where, args...)
@@ -169,14 +243,14 @@ func (r *repository) selectNewsTagByNewsIds(ctx context.Context, ids []string) (
 func (r *repository) selectNewsTag(ctx context.Context, where string, args ...interface{}) (tags *entities.SliceNewsTag, err error) {
 	tags = new(entities.SliceNewsTag)
 	query := "SELECT `news_id`, `tag_id` FROM `news_tags` " + where
-	err = r.DB.SelectContext(ctx, tags, query, args...)
+	err = r.DB.SelectContext(ctx, tags, database.Rebind(r.DB, query), args...)
 	if err != nil {
-		logger.ErrorWithStack(err)
-		err = failure.InternalServerError
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
 		return
 	}
 	if len(*tags) < 1 {
-		err = failure.NotFound("tags not found")
+		err = errTagsNotFound
 	}
 	return
 }
@@ -187,84 +261,277 @@ func (r *repository) UpdateNews(ctx context.Context, news *entities.News) (err e
 		return
 	}
 	newNews := (*sliceNews)[0]
+	if news.Version != 0 && news.Version != newNews.Version {
+		return ErrVersionConflict
+	}
+	// the current tags go in the events of the stored news
+	tags, err := r.selectNewsTag(ctx, "WHERE news_id = ?", newNews.ID)
+	if err != nil && !errors.Is(err, errTagsNotFound) {
+		return
+	}
+	if err == nil {
+		newNews.Tags = tags.ToMapTag()[newNews.ID]
+	}
+	oldNews := newNews
 	newNews.Update(*news)
+	// the slug column is never updated, the events carry the stored one
+	newNews.Slug = oldNews.Slug
 
 	tx, err := r.DB.BeginTxx(ctx, nil)
 	if err != nil {
-		logger.ErrorWithStack(err)
-		return failure.InternalServerError
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
 	}
 
-	err = r.updateNews(tx, &newNews)
+	err = r.updateNews(ctx, tx, &newNews)
 	if err != nil {
 		tx.Rollback()
 		return
 	}
 
-	err = r.deleteNewsTag(tx, newNews.ID)
+	err = r.deleteNewsTag(ctx, tx, newNews.ID)
 	if err != nil {
 		tx.Rollback()
 		return
 	}
 
-	err = r.insertNewsTags(tx, &newNews)
+	err = r.insertNewsTags(ctx, tx, &newNews)
 	if err != nil {
 		tx.Rollback()
 		return
 	}
+
+	err = r.record(ctx, tx, &oldNews, &newNews)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+	tx.Commit()
+	return
+}
+
+// SaveNews creates or replaces every news in a single transaction. A news replaces
+// the stored news with the same id, or else with the same slug and language. A
+// news without a translation group keeps the stored one, or starts its own.
+func (r *repository) SaveNews(ctx context.Context, sliceNews entities.SliceNews) (err error) {
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+	for i := range sliceNews {
+		err = r.saveNews(ctx, tx, &sliceNews[i])
+		if err != nil {
+			tx.Rollback()
+			return
+		}
+	}
 	tx.Commit()
 	return
 }
 
+func (r *repository) saveNews(ctx context.Context, tx *sqlx.Tx, news *entities.News) (err error) {
+	var stored []entities.News
+	query := "SELECT " + newsColumns + " FROM `news` WHERE id = ? OR (slug = ? AND language = ?)"
+	err = tx.SelectContext(ctx, &stored, database.Rebind(tx, query), news.ID, news.Slug, news.Language)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+	if len(stored) > 1 {
+		return ErrIDSlugMismatch
+	}
+	if len(stored) == 0 {
+		if news.TranslationGroupID == "" {
+			news.TranslationGroupID = news.ID
+		}
+		err = r.insertNews(ctx, tx, news)
+		if err != nil {
+			return
+		}
+		err = r.insertNewsTags(ctx, tx, news)
+		if err != nil {
+			return
+		}
+		return r.record(ctx, tx, nil, news)
+	}
+
+	// the stored tags go in the events and the audit entry of the stored news
+	query = "SELECT `tag_id` FROM `news_tags` WHERE `news_id` = ?"
+	err = tx.SelectContext(ctx, &stored[0].Tags, database.Rebind(tx, query), stored[0].ID)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+
+	news.ID = stored[0].ID
+	news.Version = stored[0].Version + 1
+	if news.TranslationGroupID == "" {
+		news.TranslationGroupID = stored[0].TranslationGroupID
+	}
+	err = r.updateNews(ctx, tx, news)
+	if err != nil {
+		return
+	}
+	err = r.deleteNewsTag(ctx, tx, news.ID)
+	if err != nil {
+		return
+	}
+	err = r.insertNewsTags(ctx, tx, news)
+	if err != nil {
+		return
+	}
+	saved := *news
+	saved.Slug, saved.CreatedAt = stored[0].Slug, stored[0].CreatedAt
+	return r.record(ctx, tx, &stored[0], &saved)
+}
+
+// record writes the outbox events and the audit log entry of storing news over old
+// in tx, old is nil for a new news.
+func (r *repository) record(ctx context.Context, tx *sqlx.Tx, old *entities.News, news *entities.News) error {
+	events, err := entities.NewsEvents(old, news)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+	err = outbox.Insert(ctx, tx, events)
+	if err != nil {
+		return err
+	}
+	entry, err := auditEntry(ctx, old, news)
+	if err != nil {
+		return err
+	}
+	return audit.Insert(ctx, tx, entry)
+}
+
+// StreamNews calls fn with every news and its tag ids, oldest first, reading
+// rows one by one so the whole table is never held in memory.
+func (r *repository) StreamNews(ctx context.Context, fn func(news *entities.News) error) (err error) {
+	query := "SELECT " + newsColumns + ", tag_id FROM `news` LEFT JOIN `news_tags` ON `news_tags`.news_id = `news`.id " +
+		"ORDER BY `createdAt`, id"
+	rows, err := r.DB.QueryxContext(ctx, database.Rebind(r.DB, query))
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+	defer rows.Close()
+
+	var current *entities.News
+	for rows.Next() {
+		var row struct {
+			entities.News
+			TagID *string `db:"tag_id"`
+		}
+		err = rows.StructScan(&row)
+		if err != nil {
+			logger.ErrorWithStack(ctx, err)
+			return failure.InternalServerError.Wrap(err)
+		}
+		if current != nil && current.ID != row.ID {
+			err = fn(current)
+			if err != nil {
+				return
+			}
+			current = nil
+		}
+		if current == nil {
+			current = &row.News
+		}
+		if row.TagID != nil {
+			current.Tags = append(current.Tags, *row.TagID)
+		}
+	}
+	err = rows.Err()
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+	if current != nil {
+		return fn(current)
+	}
+	return
+}
+
 func (r *repository) DeleteNews(ctx context.Context, id string) (err error) {
 	sliceNews, err := r.selectNews(ctx, "WHERE id = ?", id)
 	if err != nil {
 		return
 	}
 	newNews := (*sliceNews)[0]
+	oldNews := newNews
 	newNews.Delete()
 	tx, err := r.DB.BeginTxx(ctx, nil)
 	if err != nil {
-		logger.ErrorWithStack(err)
-		err = failure.InternalServerError
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
+		return
+	}
+	err = r.updateNews(ctx, tx, &newNews)
+	if err != nil {
+		tx.Rollback()
 		return
 	}
-	err = r.updateNews(tx, &newNews)
+	err = r.record(ctx, tx, &oldNews, &newNews)
 	if err != nil {
+		tx.Rollback()
 		return
 	}
 	tx.Commit()
 	return
 }
 
-func (r *repository) updateNews(tx *sqlx.Tx, news *entities.News) (err error) {
-	query := "UPDATE `news` SET title = :title, content = :content, topic = :topic, status = :status, " +
-		"deletedAt = :deletedAt WHERE id = :id"
-	stmt, err := tx.PrepareNamed(query)
+// updateNews writes news over the version before its own, ErrVersionConflict
+// tells another write replaced that version since it was read.
+func (r *repository) updateNews(ctx context.Context, tx *sqlx.Tx, news *entities.News) (err error) {
+	query := "UPDATE `news` SET title = :title, content = :content, content_format = :content_format, " +
+		"excerpt = :excerpt, word_count = :word_count, reading_time_minutes = :reading_time_minutes, topic = :topic, " +
+		"language = :language, translation_group_id = :translation_group_id, status = :status, version = :version, " +
+		"featured_image_id = :featured_image_id, `updatedAt` = :updatedAt, `deletedAt` = :deletedAt " +
+		"WHERE id = :id AND version = :version - 1"
+	stmt, err := tx.PrepareNamed(database.Rebind(tx, query))
 	if err != nil {
-		logger.ErrorWithStack(err)
-		err = failure.InternalServerError
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
 		return
 	}
-	_, err = stmt.Exec(news)
+	result, err := stmt.Exec(news)
+	if database.IsUniqueViolation(err) {
+		return ErrSlugTaken.Wrap(err)
+	}
 	if err != nil {
-		logger.ErrorWithStack(err)
-		err = failure.InternalServerError
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
 		return
 	}
+	updated, err := result.RowsAffected()
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+	if updated == 0 {
+		return ErrVersionConflict
+	}
 	return
 }
 
-func (r *repository) deleteNewsTag(tx *sqlx.Tx, id string) error {
+func (r *repository) deleteNewsTag(ctx context.Context, tx *sqlx.Tx, id string) error {
 	query := "DELETE FROM `news_tags` WHERE `news_id` = ?"
-	_, err := tx.Exec(query, id)
+	_, err := tx.Exec(database.Rebind(tx, query), id)
 	if err != nil {
-		logger.ErrorWithStack(err)
-		return failure.InternalServerError
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
 	}
 	return nil
 }
 
+func languageFilter(where string, lang string, args ...interface{}) (string, []interface{}) {
+	if lang == "" {
+		return where, args
+	}
+	return where + " AND language = ?", append(args, lang)
+}
+
 func extractNewsId(sliceNews *entities.SliceNews) (res []string) {
 	for _, news := range *sliceNews {
 		res = append(res, news.ID)
@@ -0,0 +1,78 @@
+package news_test
+
+import (
+	"news/domain/audit"
+	"news/domain/news"
+	"news/domain/news/newstest"
+	"news/domain/outbox"
+	"news/infras"
+	"news/infras/dbtest"
+	"testing"
+)
+
+func TestMemoryRepositoryContract(t *testing.T) {
+	newstest.RepositoryContract(t, func(t *testing.T) news.Repository {
+		return news.NewMemoryRepository()
+	})
+}
+
+func TestRepositoryContract(t *testing.T) {
+	for _, driver := range dbtest.Drivers() {
+		driver := driver
+		t.Run(driver, func(t *testing.T) {
+			newstest.RepositoryContract(t, func(t *testing.T) news.Repository {
+				db := dbtest.Open(t, driver)
+				if driver == infras.DriverPostgres {
+					return news.NewPostgresRepository(db)
+				}
+				return news.NewRepository(db)
+			})
+		})
+	}
+}
+
+func TestMemoryRepositoryOutbox(t *testing.T) {
+	newstest.OutboxContract(t, func(t *testing.T) (news.Repository, outbox.Repository) {
+		repo, box := news.NewMemoryRepository(), outbox.NewMemoryRepository()
+		repo.Outbox = box
+		return repo, box
+	})
+}
+
+func TestRepositoryOutbox(t *testing.T) {
+	for _, driver := range dbtest.Drivers() {
+		driver := driver
+		t.Run(driver, func(t *testing.T) {
+			newstest.OutboxContract(t, func(t *testing.T) (news.Repository, outbox.Repository) {
+				db := dbtest.Open(t, driver)
+				if driver == infras.DriverPostgres {
+					return news.NewPostgresRepository(db), outbox.NewRepository(db)
+				}
+				return news.NewRepository(db), outbox.NewRepository(db)
+			})
+		})
+	}
+}
+
+func TestMemoryRepositoryAudit(t *testing.T) {
+	newstest.AuditContract(t, func(t *testing.T) (news.Repository, audit.Repository) {
+		repo, log := news.NewMemoryRepository(), audit.NewMemoryRepository()
+		repo.Audit = log
+		return repo, log
+	})
+}
+
+func TestRepositoryAudit(t *testing.T) {
+	for _, driver := range dbtest.Drivers() {
+		driver := driver
+		t.Run(driver, func(t *testing.T) {
+			newstest.AuditContract(t, func(t *testing.T) (news.Repository, audit.Repository) {
+				db := dbtest.Open(t, driver)
+				if driver == infras.DriverPostgres {
+					return news.NewPostgresRepository(db), audit.NewRepository(db)
+				}
+				return news.NewRepository(db), audit.NewRepository(db)
+			})
+		})
+	}
+}
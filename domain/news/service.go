@@ -2,30 +2,42 @@ package news
 
 import (
 	"context"
-	"fmt"
+	"encoding/json"
+	"errors"
+	"net/http"
 	"news/domain/entities"
+	"news/domain/media"
 	"news/domain/tag"
+	"news/shared/failure"
 	"news/shared/logger"
+	"news/shared/metrics"
+	"news/shared/patch"
+	"strings"
 )
 
 type Service interface {
 	Create(ctx context.Context, dto *entities.NewsDto) (result *entities.NewsDto, err error)
-	GetAll(ctx context.Context) (result *entities.SliceNewsDto, err error)
-	GetBySlug(ctx context.Context, slug string) (result *entities.NewsDto, err error)
-	GetByTopic(ctx context.Context, topic string) (result *entities.SliceNewsDto, err error)
-	GetByStatus(ctx context.Context, status entities.NewsStatus) (result *entities.SliceNewsDto, err error)
+	GetAll(ctx context.Context, lang string) (result *entities.SliceNewsDto, err error)
+	GetByID(ctx context.Context, id string) (result *entities.NewsDto, err error)
+	GetBySlug(ctx context.Context, slug string, lang string) (result *entities.NewsDto, err error)
+	GetByTopic(ctx context.Context, topic string, lang string) (result *entities.SliceNewsDto, err error)
+	GetByStatus(ctx context.Context, status entities.NewsStatus, lang string) (result *entities.SliceNewsDto, err error)
+	GetTranslations(ctx context.Context, slug string, lang string) (result *entities.SliceNewsDto, err error)
+	Search(ctx context.Context, query string, lang string) (result *entities.SliceNewsDto, err error)
 	Update(ctx context.Context, dto *entities.NewsDto) (err error)
+	Patch(ctx context.Context, id string, version int, p patch.Patch) (err error)
 	Delete(ctx context.Context, id string) (err error)
 }
 
 type serviceImpl struct {
-	repo    Repository
-	tagRepo tag.Repository
-	cache   Cache
+	repo      Repository
+	tagRepo   tag.Repository
+	mediaRepo media.Repository
+	cache     Cache
 }
 
-func NewService(repo Repository, tagRepo tag.Repository, cache Cache) *serviceImpl {
-	return &serviceImpl{repo: repo, tagRepo: tagRepo, cache: cache}
+func NewService(repo Repository, tagRepo tag.Repository, mediaRepo media.Repository, cache Cache) *serviceImpl {
+	return &serviceImpl{repo: repo, tagRepo: tagRepo, mediaRepo: mediaRepo, cache: cache}
 }
 
 func (s *serviceImpl) Create(ctx context.Context, dto *entities.NewsDto) (result *entities.NewsDto, err error) {
@@ -33,132 +45,402 @@ func (s *serviceImpl) Create(ctx context.Context, dto *entities.NewsDto) (result
 	if err != nil {
 		return
 	}
+	news.SetLanguageDefaults()
+
+	err = s.checkFeaturedImage(ctx, news)
+	if err != nil {
+		return
+	}
+	err = s.checkTranslationGroup(ctx, news)
+	if err != nil {
+		return
+	}
 
 	err = s.repo.CreateNews(ctx, news)
 	if err != nil {
 		return
 	}
+	s.invalidate(ctx, news)
 
 	result = news.ToNewsDto()
+	result.LinkMedia(s.mediaByIds(ctx, result.MediaIds()))
 	return
 }
 
-func (s *serviceImpl) GetAll(ctx context.Context) (result *entities.SliceNewsDto, err error) {
-	result, err = s.cache.GetSliceNews(ctx, "all:")
+func (s *serviceImpl) GetAll(ctx context.Context, lang string) (result *entities.SliceNewsDto, err error) {
+	result, err = s.cache.GetSliceNews(ctx, "all:"+lang)
 	if err == nil {
-		fmt.Println("Get from Cache")
+		cacheLookup(ctx, "all", true)
 		return
 	}
-	fmt.Println("Search from DB")
-	sliceNews, err := s.repo.GetAllNews(ctx)
+	cacheLookup(ctx, "all", false)
+	sliceNews, err := s.repo.GetAllNews(ctx, lang)
 	if err != nil {
 		return
 	}
 
-	tags, err := s.tagRepo.GetTagByIds(ctx, sliceNews.GetSliceTagIds())
+	tags, err := s.tagsByIds(ctx, sliceNews.GetSliceTagIds())
 	if err != nil {
 		return
 	}
 
 	result = sliceNews.ToSliceNewsDto(tags.ToMapTags)
+	result.LinkMedia(s.mediaByIds(ctx, result.MediaIds()))
 
-	errs := s.cache.SetSliceNews(ctx, "all:", result)
+	errs := s.cache.SetSliceNews(ctx, "all:"+lang, result)
 	if errs != nil {
-		logger.ErrorWithStack(errs)
+		logger.ErrorWithStack(ctx, errs)
 	}
 	return
 }
 
-func (s *serviceImpl) GetByTopic(ctx context.Context, topic string) (result *entities.SliceNewsDto, err error) {
-	result, err = s.cache.GetSliceNews(ctx, "topic:"+topic)
+func (s *serviceImpl) GetByTopic(ctx context.Context, topic string, lang string) (result *entities.SliceNewsDto, err error) {
+	result, err = s.cache.GetSliceNews(ctx, "topic:"+lang+":"+topic)
 	if err == nil {
-		fmt.Println("Get from Cache")
+		cacheLookup(ctx, "topic", true)
 		return
 	}
-	fmt.Println("Search from DB")
+	cacheLookup(ctx, "topic", false)
 
-	sliceNews, err := s.repo.GetNewsByTopic(ctx, topic)
+	sliceNews, err := s.repo.GetNewsByTopic(ctx, topic, lang)
 	if err != nil {
 		return
 	}
 
-	tags, err := s.tagRepo.GetTagByIds(ctx, sliceNews.GetSliceTagIds())
+	tags, err := s.tagsByIds(ctx, sliceNews.GetSliceTagIds())
 	if err != nil {
 		return
 	}
 
 	result = sliceNews.ToSliceNewsDto(tags.ToMapTags)
+	result.LinkMedia(s.mediaByIds(ctx, result.MediaIds()))
 
-	errs := s.cache.SetSliceNews(ctx, "topic:"+topic, result)
+	errs := s.cache.SetSliceNews(ctx, "topic:"+lang+":"+topic, result)
 	if errs != nil {
-		logger.ErrorWithStack(errs)
+		logger.ErrorWithStack(ctx, errs)
 	}
 	return
 }
 
-func (s *serviceImpl) GetByStatus(ctx context.Context, status entities.NewsStatus) (result *entities.SliceNewsDto, err error) {
-	result, err = s.cache.GetSliceNews(ctx, "status:"+status.String())
+func (s *serviceImpl) GetByStatus(ctx context.Context, status entities.NewsStatus, lang string) (result *entities.SliceNewsDto, err error) {
+	result, err = s.cache.GetSliceNews(ctx, "status:"+lang+":"+status.String())
 	if err == nil {
-		fmt.Println("Get from Cache")
+		cacheLookup(ctx, "status", true)
 		return
 	}
-	fmt.Println("Search from DB")
+	cacheLookup(ctx, "status", false)
 
-	sliceNews, err := s.repo.GetNewsByStatus(ctx, status)
+	sliceNews, err := s.repo.GetNewsByStatus(ctx, status, lang)
 	if err != nil {
 		return
 	}
 
-	tags, err := s.tagRepo.GetTagByIds(ctx, sliceNews.GetSliceTagIds())
+	tags, err := s.tagsByIds(ctx, sliceNews.GetSliceTagIds())
 	if err != nil {
 		return
 	}
 
 	result = sliceNews.ToSliceNewsDto(tags.ToMapTags)
+	result.LinkMedia(s.mediaByIds(ctx, result.MediaIds()))
 
-	errs := s.cache.SetSliceNews(ctx, "status:"+status.String(), result)
+	errs := s.cache.SetSliceNews(ctx, "status:"+lang+":"+status.String(), result)
 	if errs != nil {
-		logger.ErrorWithStack(errs)
+		logger.ErrorWithStack(ctx, errs)
 	}
 	return
 }
 
-func (s *serviceImpl) GetBySlug(ctx context.Context, slug string) (result *entities.NewsDto, err error) {
-	result, err = s.cache.GetNews(ctx, "slug:"+slug)
+// GetByID isn't cached, it reads the current news for writes.
+func (s *serviceImpl) GetByID(ctx context.Context, id string) (result *entities.NewsDto, err error) {
+	news, err := s.repo.GetNewsByID(ctx, id)
+	if err != nil {
+		return
+	}
+
+	tags, err := s.tagsByIds(ctx, news.Tags)
+	if err != nil {
+		return
+	}
+
+	result = news.ToNewsDto(tags.ToMapTags())
+	result.LinkMedia(s.mediaByIds(ctx, result.MediaIds()))
+	return
+}
+
+func (s *serviceImpl) GetBySlug(ctx context.Context, slug string, lang string) (result *entities.NewsDto, err error) {
+	result, err = s.cache.GetNews(ctx, "slug:"+lang+":"+slug)
 	if err == nil {
-		fmt.Println("Get from Cache")
+		cacheLookup(ctx, "slug", true)
 		return
 	}
-	fmt.Println("Search from DB")
+	cacheLookup(ctx, "slug", false)
 
-	news, err := s.repo.GetNewsBySlug(ctx, slug)
+	news, err := s.repo.GetNewsBySlug(ctx, slug, lang)
 	if err != nil {
 		return
 	}
 
-	tags, err := s.tagRepo.GetTagByIds(ctx, news.Tags)
+	tags, err := s.tagsByIds(ctx, news.Tags)
 	if err != nil {
 		return
 	}
 
 	result = news.ToNewsDto(tags.ToMapTags())
+	result.LinkMedia(s.mediaByIds(ctx, result.MediaIds()))
 
-	errs := s.cache.SetNews(ctx, "slug:"+slug, result)
+	errs := s.cache.SetNews(ctx, "slug:"+lang+":"+slug, result)
 	if errs != nil {
-		logger.ErrorWithStack(errs)
+		logger.ErrorWithStack(ctx, errs)
+	}
+	return
+}
+
+// GetTranslations returns the other published languages of the news with the slug.
+func (s *serviceImpl) GetTranslations(ctx context.Context, slug string, lang string) (result *entities.SliceNewsDto, err error) {
+	news, err := s.repo.GetNewsBySlug(ctx, slug, lang)
+	if err != nil {
+		return
+	}
+
+	group, err := s.repo.GetNewsByTranslationGroup(ctx, news.TranslationGroupID)
+	if err != nil {
+		return
+	}
+
+	translations := entities.SliceNews{}
+	for _, translation := range *group {
+		if translation.ID != news.ID && translation.Status == entities.NewsPublish {
+			translations = append(translations, translation)
+		}
 	}
+	if len(translations) < 1 {
+		return &entities.SliceNewsDto{}, nil
+	}
+
+	tags, err := s.tagsByIds(ctx, translations.GetSliceTagIds())
+	if err != nil {
+		return
+	}
+
+	result = translations.ToSliceNewsDto(tags.ToMapTags)
+	result.LinkMedia(s.mediaByIds(ctx, result.MediaIds()))
 	return
 }
 
+// Search isn't cached, queries are too varied to be worth it.
+func (s *serviceImpl) Search(ctx context.Context, query string, lang string) (result *entities.SliceNewsDto, err error) {
+	if strings.TrimSpace(query) == "" {
+		err = failure.BadRequestWithString("search query can't be empty")
+		return
+	}
+	sliceNews, err := s.repo.SearchNews(ctx, query, lang)
+	if err != nil {
+		if failure.GetStatus(err) == http.StatusNotFound {
+			return &entities.SliceNewsDto{}, nil
+		}
+		return
+	}
+
+	tags, err := s.tagsByIds(ctx, sliceNews.GetSliceTagIds())
+	if err != nil {
+		return
+	}
+
+	result = sliceNews.ToSliceNewsDto(tags.ToMapTags)
+	result.LinkMedia(s.mediaByIds(ctx, result.MediaIds()))
+	return
+}
+
+// Update replaces the news with the id of dto by dto, a dto without a
+// translation group, a content format or a language keeps the one of the stored news.
 func (s *serviceImpl) Update(ctx context.Context, dto *entities.NewsDto) (err error) {
 	news, err := dto.ToNews()
 	if err != nil {
 		return
 	}
+	stored, err := s.repo.GetNewsByID(ctx, news.ID)
+	if err != nil {
+		return
+	}
+	if news.TranslationGroupID == "" {
+		news.TranslationGroupID = stored.TranslationGroupID
+	}
+	if dto.ContentFormat == "" {
+		news.ContentFormat = stored.ContentFormat
+	}
+	if news.Language == "" {
+		news.Language = stored.Language
+	}
+	news.SetLanguageDefaults()
+	news.Version = dto.Version
+
+	err = s.checkFeaturedImage(ctx, news)
+	if err != nil {
+		return
+	}
+	err = s.checkTranslationGroup(ctx, news)
+	if err != nil {
+		return
+	}
 
-	return s.repo.UpdateNews(ctx, news)
+	err = s.repo.UpdateNews(ctx, news)
+	if err != nil {
+		return
+	}
+	// the slug is never updated, the reads cache the news under the stored one
+	news.Slug = stored.Slug
+	s.invalidate(ctx, stored, news)
+	return
+}
+
+// Patch applies p to the news as it is written, tags are ids, and replaces
+// the news with the result once it is valid. version is the version the patch
+// expects to change, 0 for the one it reads, or a version the patch writes.
+func (s *serviceImpl) Patch(ctx context.Context, id string, version int, p patch.Patch) (err error) {
+	news, err := s.repo.GetNewsByID(ctx, id)
+	if err != nil {
+		return
+	}
+	if version != 0 && version != news.Version {
+		return ErrVersionConflict
+	}
+	dto := news.ToNewsDto()
+	// tags stay an array a patch can add to
+	if dto.Tags == nil {
+		dto.Tags = []string{}
+	}
+	doc, err := json.Marshal(dto)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+	doc, err = p.Apply(doc)
+	if err != nil {
+		return
+	}
+	dto = &entities.NewsDto{}
+	err = json.Unmarshal(doc, dto)
+	if err != nil {
+		return failure.BadRequestWithString("patched news not valid: " + err.Error())
+	}
+	err = dto.Validate()
+	if err != nil {
+		return
+	}
+	dto.ID = id
+	if version != 0 {
+		dto.Version = version
+	}
+	return s.Update(ctx, dto)
 }
 
 func (s *serviceImpl) Delete(ctx context.Context, id string) (err error) {
-	return s.repo.DeleteNews(ctx, id)
+	stored, err := s.repo.GetNewsByID(ctx, id)
+	if err != nil {
+		return
+	}
+	err = s.repo.DeleteNews(ctx, id)
+	if err != nil {
+		return
+	}
+	deleted := *stored
+	deleted.Delete()
+	s.invalidate(ctx, stored, &deleted)
+	return
+}
+
+// invalidate drops the cached reads of every state of the news a write went through.
+// It is best effort, the write is already done.
+func (s *serviceImpl) invalidate(ctx context.Context, states ...*entities.News) {
+	var keys []string
+	for _, news := range states {
+		keys = append(keys, CacheKeys(news)...)
+	}
+	err := s.cache.Delete(ctx, keys...)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+	}
+}
+
+func (s *serviceImpl) checkFeaturedImage(ctx context.Context, news *entities.News) error {
+	return checkFeaturedImage(ctx, s.mediaRepo, news)
+}
+
+// checkTranslationGroup makes sure news joins a translation group some news is
+// already in, without another news in its language. A news is always allowed
+// in the group named after itself.
+func (s *serviceImpl) checkTranslationGroup(ctx context.Context, news *entities.News) error {
+	group, err := s.repo.GetNewsByTranslationGroup(ctx, news.TranslationGroupID)
+	if errors.Is(err, ErrNotFound) {
+		return joinGroup(news, nil, nil)
+	}
+	if err != nil {
+		return err
+	}
+	return joinGroup(news, *group, func(translation *entities.News) bool {
+		return translation.ID == news.ID
+	})
+}
+
+// checkFeaturedImage makes sure the featured image of news is a stored media.
+func checkFeaturedImage(ctx context.Context, mediaRepo media.Repository, news *entities.News) error {
+	if news.FeaturedImageID == nil {
+		return nil
+	}
+	_, err := mediaRepo.GetMediaByID(ctx, *news.FeaturedImageID)
+	if failure.GetStatus(err) == http.StatusNotFound {
+		return ErrFeaturedImageNotFound
+	}
+	return err
+}
+
+// joinGroup checks news may join the translation group of group, the news in
+// it. replaces tells the news in group the write of news replaces, it isn't
+// another translation.
+func joinGroup(news *entities.News, group entities.SliceNews, replaces func(translation *entities.News) bool) error {
+	if len(group) < 1 {
+		if news.TranslationGroupID == news.ID {
+			return nil
+		}
+		return ErrTranslationGroupNotFound
+	}
+	for i := range group {
+		if group[i].Language == news.Language && !replaces(&group[i]) {
+			return ErrTranslationExists
+		}
+	}
+	return nil
+}
+
+// tagsByIds returns the tags with the ids, news without tags don't query them.
+func (s *serviceImpl) tagsByIds(ctx context.Context, ids []string) (*entities.Tags, error) {
+	if len(ids) < 1 {
+		return &entities.Tags{}, nil
+	}
+	return s.tagRepo.GetTagByIds(ctx, ids)
+}
+
+// mediaByIds is best effort, news is still returned when its media can't be loaded.
+func (s *serviceImpl) mediaByIds(ctx context.Context, ids []string) map[string]entities.MediaDto {
+	if len(ids) < 1 {
+		return nil
+	}
+	sliceMedia, err := s.mediaRepo.GetMediaByIds(ctx, ids)
+	if err != nil {
+		if failure.GetStatus(err) != http.StatusNotFound {
+			logger.ErrorWithStack(ctx, err)
+		}
+		return nil
+	}
+	return sliceMedia.ToMapMediaDto()
+}
+
+// cacheLookup records a lookup of the cache keys of family in the metrics and the request log.
+func cacheLookup(ctx context.Context, family string, hit bool) {
+	metrics.Cache(family, hit)
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	logger.AddStr(ctx, "cache", result)
 }
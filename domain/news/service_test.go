@@ -4,13 +4,18 @@ import (
 	"context"
 	"github.com/golang/mock/gomock"
 	"github.com/magiconair/properties/assert"
+	"net/http"
 	"news/domain/entities"
+	"news/domain/media"
+	media_mock "news/domain/media/mock"
 	"news/domain/news"
 	news_mock "news/domain/news/mock"
+	"news/domain/tag"
 	tag_mock "news/domain/tag/mock"
 	"news/shared/Date"
 	"news/shared/IDGEN"
 	"news/shared/failure"
+	"news/shared/patch"
 	"testing"
 	"time"
 )
@@ -33,8 +38,9 @@ func TestNewsService(t *testing.T) {
 		defer ctrl.Finish()
 		mockNewsRepo := news_mock.NewMockRepository(ctrl)
 		mockTagRepo := tag_mock.NewMockRepository(ctrl)
+		mockMediaRepo := media_mock.NewMockRepository(ctrl)
 		mockCache := news_mock.NewMockCache(ctrl)
-		service := news.NewService(mockNewsRepo, mockTagRepo, mockCache)
+		service := news.NewService(mockNewsRepo, mockTagRepo, mockMediaRepo, mockCache)
 
 		sliceTest := []struct {
 			testTitle      string
@@ -47,7 +53,10 @@ func TestNewsService(t *testing.T) {
 				testTitle: "create success",
 				mockSetup: func(ctx context.Context, repo *news_mock.MockRepository, input entities.NewsDto) {
 					dto, _ := input.ToNews()
+					dto.SetLanguageDefaults()
+					repo.EXPECT().GetNewsByTranslationGroup(ctx, dto.ID).Return(nil, news.ErrNotFound)
 					repo.EXPECT().CreateNews(ctx, dto).Return(nil)
+					mockCache.EXPECT().Delete(ctx, cacheKeys(dto)...).Return(nil)
 				},
 				input: entities.NewsDto{
 					Title:   "first title",
@@ -57,13 +66,22 @@ func TestNewsService(t *testing.T) {
 					Tags:    []string{"tags1", "tags2"},
 				},
 				expectedResult: &entities.NewsDto{
-					ID:      "d2668631-1563-46bd-9498-5bfac7eed17a",
-					Title:   "first title",
-					Slug:    "first-title",
-					Content: "content first",
-					Topic:   "football",
-					Status:  "deleted",
-					Tags:    []string{"tags1", "tags2"},
+					ID:                 "d2668631-1563-46bd-9498-5bfac7eed17a",
+					Title:              "first title",
+					Slug:               "first-title",
+					Content:            "content first",
+					ContentFormat:      "plain",
+					ContentHTML:        "<p>content first</p>\n",
+					Excerpt:            "content first",
+					WordCount:          2,
+					ReadingTimeMinutes: 1,
+					Topic:              "football",
+					Language:           "id",
+					TranslationGroupID: "d2668631-1563-46bd-9498-5bfac7eed17a",
+					Status:             "deleted",
+					Tags:               []string{"tags1", "tags2"},
+					Version:            1,
+					UpdatedAt:          mockTime,
 				},
 				expectedError: nil,
 			},
@@ -87,6 +105,8 @@ func TestNewsService(t *testing.T) {
 				testTitle: "error repository",
 				mockSetup: func(ctx context.Context, repo *news_mock.MockRepository, input entities.NewsDto) {
 					dto, _ := input.ToNews()
+					dto.SetLanguageDefaults()
+					repo.EXPECT().GetNewsByTranslationGroup(ctx, dto.ID).Return(nil, news.ErrNotFound)
 					repo.EXPECT().CreateNews(ctx, dto).Return(failure.InternalServerError)
 				},
 				input: entities.NewsDto{
@@ -134,8 +154,9 @@ func TestNewsService(t *testing.T) {
 		defer ctrl.Finish()
 		mockNewsRepo := news_mock.NewMockRepository(ctrl)
 		mockTagRepo := tag_mock.NewMockRepository(ctrl)
+		mockMediaRepo := media_mock.NewMockRepository(ctrl)
 		mockCache := news_mock.NewMockCache(ctrl)
-		service := news.NewService(mockNewsRepo, mockTagRepo, mockCache)
+		service := news.NewService(mockNewsRepo, mockTagRepo, mockMediaRepo, mockCache)
 
 		sliceTest := []struct {
 			testTitle      string
@@ -147,7 +168,7 @@ func TestNewsService(t *testing.T) {
 			{
 				testTitle: "success from cache",
 				mockSetup: func(ctx context.Context, repo *news_mock.MockRepository, tagRepo *tag_mock.MockRepository, cache *news_mock.MockCache, slug string) {
-					cache.EXPECT().GetNews(ctx, "slug:"+slug).Return(&entities.NewsDto{
+					cache.EXPECT().GetNews(ctx, "slug:en:"+slug).Return(&entities.NewsDto{
 						ID:      "d2668631-1563-46bd-9498-5bfac7eed17a",
 						Title:   "first title",
 						Slug:    "first-title",
@@ -172,8 +193,8 @@ func TestNewsService(t *testing.T) {
 			{
 				testTitle: "success from DB",
 				mockSetup: func(ctx context.Context, repo *news_mock.MockRepository, tagRepo *tag_mock.MockRepository, cache *news_mock.MockCache, slug string) {
-					cache.EXPECT().GetNews(ctx, "slug:"+slug).Return(nil, failure.InternalServerError)
-					repo.EXPECT().GetNewsBySlug(ctx, slug).Return(&entities.News{
+					cache.EXPECT().GetNews(ctx, "slug:en:"+slug).Return(nil, failure.InternalServerError)
+					repo.EXPECT().GetNewsBySlug(ctx, slug, "en").Return(&entities.News{
 						ID:        "d2668631-1563-46bd-9498-5bfac7eed17a",
 						Title:     "first title",
 						Slug:      "first-title",
@@ -196,25 +217,29 @@ func TestNewsService(t *testing.T) {
 							Status: entities.TagActive,
 						},
 					}, nil)
-					cache.EXPECT().SetNews(ctx, "slug:"+slug, &entities.NewsDto{
-						ID:      "d2668631-1563-46bd-9498-5bfac7eed17a",
-						Title:   "first title",
-						Slug:    "first-title",
-						Content: "content first",
-						Topic:   "football",
-						Status:  "publish",
-						Tags:    []string{"tags1", "tags2"},
+					cache.EXPECT().SetNews(ctx, "slug:en:"+slug, &entities.NewsDto{
+						ID:            "d2668631-1563-46bd-9498-5bfac7eed17a",
+						Title:         "first title",
+						Slug:          "first-title",
+						Content:       "content first",
+						ContentFormat: "plain",
+						ContentHTML:   "<p>content first</p>\n",
+						Topic:         "football",
+						Status:        "publish",
+						Tags:          []string{"tags1", "tags2"},
 					}).Return(nil)
 				},
 				input: "news-title",
 				expectedResult: &entities.NewsDto{
-					ID:      "d2668631-1563-46bd-9498-5bfac7eed17a",
-					Title:   "first title",
-					Slug:    "first-title",
-					Content: "content first",
-					Topic:   "football",
-					Status:  "publish",
-					Tags:    []string{"tags1", "tags2"},
+					ID:            "d2668631-1563-46bd-9498-5bfac7eed17a",
+					Title:         "first title",
+					Slug:          "first-title",
+					Content:       "content first",
+					ContentFormat: "plain",
+					ContentHTML:   "<p>content first</p>\n",
+					Topic:         "football",
+					Status:        "publish",
+					Tags:          []string{"tags1", "tags2"},
 				},
 				expectedError: nil,
 			},
@@ -224,7 +249,7 @@ func TestNewsService(t *testing.T) {
 			t.Run(test.testTitle, func(t *testing.T) {
 				ctx := context.Background()
 				test.mockSetup(ctx, mockNewsRepo, mockTagRepo, mockCache, test.input)
-				actual, err := service.GetBySlug(ctx, test.input)
+				actual, err := service.GetBySlug(ctx, test.input, "en")
 				assert.Equal(t, err, test.expectedError)
 				if test.expectedResult != nil {
 					assert.Equal(t, *actual, *test.expectedResult)
@@ -252,8 +277,9 @@ func TestNewsService(t *testing.T) {
 		defer ctrl.Finish()
 		mockNewsRepo := news_mock.NewMockRepository(ctrl)
 		mockTagRepo := tag_mock.NewMockRepository(ctrl)
+		mockMediaRepo := media_mock.NewMockRepository(ctrl)
 		mockCache := news_mock.NewMockCache(ctrl)
-		service := news.NewService(mockNewsRepo, mockTagRepo, mockCache)
+		service := news.NewService(mockNewsRepo, mockTagRepo, mockMediaRepo, mockCache)
 
 		sliceTest := []struct {
 			testTitle      string
@@ -265,7 +291,7 @@ func TestNewsService(t *testing.T) {
 			{
 				testTitle: "success from cache",
 				mockSetup: func(ctx context.Context, repo *news_mock.MockRepository, tagRepo *tag_mock.MockRepository, cache *news_mock.MockCache, slug string) {
-					cache.EXPECT().GetSliceNews(ctx, "topic:"+slug).Return(&entities.SliceNewsDto{{
+					cache.EXPECT().GetSliceNews(ctx, "topic:en:"+slug).Return(&entities.SliceNewsDto{{
 						ID:      "d2668631-1563-46bd-9498-5bfac7eed17a",
 						Title:   "first title",
 						Slug:    "first-title",
@@ -290,8 +316,8 @@ func TestNewsService(t *testing.T) {
 			{
 				testTitle: "success from DB",
 				mockSetup: func(ctx context.Context, repo *news_mock.MockRepository, tagRepo *tag_mock.MockRepository, cache *news_mock.MockCache, slug string) {
-					cache.EXPECT().GetSliceNews(ctx, "topic:"+slug).Return(nil, failure.InternalServerError)
-					repo.EXPECT().GetNewsByTopic(ctx, slug).Return(&entities.SliceNews{{
+					cache.EXPECT().GetSliceNews(ctx, "topic:en:"+slug).Return(nil, failure.InternalServerError)
+					repo.EXPECT().GetNewsByTopic(ctx, slug, "en").Return(&entities.SliceNews{{
 						ID:        "d2668631-1563-46bd-9498-5bfac7eed17a",
 						Title:     "first title",
 						Slug:      "first-title",
@@ -314,25 +340,29 @@ func TestNewsService(t *testing.T) {
 							Status: entities.TagActive,
 						},
 					}, nil)
-					cache.EXPECT().SetSliceNews(ctx, "topic:"+slug, &entities.SliceNewsDto{{
-						ID:      "d2668631-1563-46bd-9498-5bfac7eed17a",
-						Title:   "first title",
-						Slug:    "first-title",
-						Content: "content first",
-						Topic:   "football",
-						Status:  "publish",
-						Tags:    []string{"tags1", "tags2"},
+					cache.EXPECT().SetSliceNews(ctx, "topic:en:"+slug, &entities.SliceNewsDto{{
+						ID:            "d2668631-1563-46bd-9498-5bfac7eed17a",
+						Title:         "first title",
+						Slug:          "first-title",
+						Content:       "content first",
+						ContentFormat: "plain",
+						ContentHTML:   "<p>content first</p>\n",
+						Topic:         "football",
+						Status:        "publish",
+						Tags:          []string{"tags1", "tags2"},
 					}}).Return(nil)
 				},
 				input: "topic",
 				expectedResult: &entities.SliceNewsDto{{
-					ID:      "d2668631-1563-46bd-9498-5bfac7eed17a",
-					Title:   "first title",
-					Slug:    "first-title",
-					Content: "content first",
-					Topic:   "football",
-					Status:  "publish",
-					Tags:    []string{"tags1", "tags2"},
+					ID:            "d2668631-1563-46bd-9498-5bfac7eed17a",
+					Title:         "first title",
+					Slug:          "first-title",
+					Content:       "content first",
+					ContentFormat: "plain",
+					ContentHTML:   "<p>content first</p>\n",
+					Topic:         "football",
+					Status:        "publish",
+					Tags:          []string{"tags1", "tags2"},
 				}},
 				expectedError: nil,
 			},
@@ -342,7 +372,7 @@ func TestNewsService(t *testing.T) {
 			t.Run(test.testTitle, func(t *testing.T) {
 				ctx := context.Background()
 				test.mockSetup(ctx, mockNewsRepo, mockTagRepo, mockCache, test.input)
-				actual, err := service.GetByTopic(ctx, test.input)
+				actual, err := service.GetByTopic(ctx, test.input, "en")
 				assert.Equal(t, err, test.expectedError)
 				if test.expectedResult != nil {
 					assert.Equal(t, *actual, *test.expectedResult)
@@ -370,8 +400,9 @@ func TestNewsService(t *testing.T) {
 		defer ctrl.Finish()
 		mockNewsRepo := news_mock.NewMockRepository(ctrl)
 		mockTagRepo := tag_mock.NewMockRepository(ctrl)
+		mockMediaRepo := media_mock.NewMockRepository(ctrl)
 		mockCache := news_mock.NewMockCache(ctrl)
-		service := news.NewService(mockNewsRepo, mockTagRepo, mockCache)
+		service := news.NewService(mockNewsRepo, mockTagRepo, mockMediaRepo, mockCache)
 
 		sliceTest := []struct {
 			testTitle      string
@@ -383,7 +414,7 @@ func TestNewsService(t *testing.T) {
 			{
 				testTitle: "success from cache",
 				mockSetup: func(ctx context.Context, repo *news_mock.MockRepository, tagRepo *tag_mock.MockRepository, cache *news_mock.MockCache, slug entities.NewsStatus) {
-					cache.EXPECT().GetSliceNews(ctx, "status:"+slug.String()).Return(&entities.SliceNewsDto{{
+					cache.EXPECT().GetSliceNews(ctx, "status:en:"+slug.String()).Return(&entities.SliceNewsDto{{
 						ID:      "d2668631-1563-46bd-9498-5bfac7eed17a",
 						Title:   "first title",
 						Slug:    "first-title",
@@ -408,8 +439,8 @@ func TestNewsService(t *testing.T) {
 			{
 				testTitle: "success from DB",
 				mockSetup: func(ctx context.Context, repo *news_mock.MockRepository, tagRepo *tag_mock.MockRepository, cache *news_mock.MockCache, slug entities.NewsStatus) {
-					cache.EXPECT().GetSliceNews(ctx, "status:"+slug.String()).Return(nil, failure.InternalServerError)
-					repo.EXPECT().GetNewsByStatus(ctx, slug).Return(&entities.SliceNews{{
+					cache.EXPECT().GetSliceNews(ctx, "status:en:"+slug.String()).Return(nil, failure.InternalServerError)
+					repo.EXPECT().GetNewsByStatus(ctx, slug, "en").Return(&entities.SliceNews{{
 						ID:        "d2668631-1563-46bd-9498-5bfac7eed17a",
 						Title:     "first title",
 						Slug:      "first-title",
@@ -432,25 +463,29 @@ func TestNewsService(t *testing.T) {
 							Status: entities.TagActive,
 						},
 					}, nil)
-					cache.EXPECT().SetSliceNews(ctx, "status:"+slug.String(), &entities.SliceNewsDto{{
-						ID:      "d2668631-1563-46bd-9498-5bfac7eed17a",
-						Title:   "first title",
-						Slug:    "first-title",
-						Content: "content first",
-						Topic:   "football",
-						Status:  "publish",
-						Tags:    []string{"tags1", "tags2"},
+					cache.EXPECT().SetSliceNews(ctx, "status:en:"+slug.String(), &entities.SliceNewsDto{{
+						ID:            "d2668631-1563-46bd-9498-5bfac7eed17a",
+						Title:         "first title",
+						Slug:          "first-title",
+						Content:       "content first",
+						ContentFormat: "plain",
+						ContentHTML:   "<p>content first</p>\n",
+						Topic:         "football",
+						Status:        "publish",
+						Tags:          []string{"tags1", "tags2"},
 					}}).Return(nil)
 				},
 				input: entities.NewsPublish,
 				expectedResult: &entities.SliceNewsDto{{
-					ID:      "d2668631-1563-46bd-9498-5bfac7eed17a",
-					Title:   "first title",
-					Slug:    "first-title",
-					Content: "content first",
-					Topic:   "football",
-					Status:  "publish",
-					Tags:    []string{"tags1", "tags2"},
+					ID:            "d2668631-1563-46bd-9498-5bfac7eed17a",
+					Title:         "first title",
+					Slug:          "first-title",
+					Content:       "content first",
+					ContentFormat: "plain",
+					ContentHTML:   "<p>content first</p>\n",
+					Topic:         "football",
+					Status:        "publish",
+					Tags:          []string{"tags1", "tags2"},
 				}},
 				expectedError: nil,
 			},
@@ -460,7 +495,7 @@ func TestNewsService(t *testing.T) {
 			t.Run(test.testTitle, func(t *testing.T) {
 				ctx := context.Background()
 				test.mockSetup(ctx, mockNewsRepo, mockTagRepo, mockCache, test.input)
-				actual, err := service.GetByStatus(ctx, test.input)
+				actual, err := service.GetByStatus(ctx, test.input, "en")
 				assert.Equal(t, err, test.expectedError)
 				if test.expectedResult != nil {
 					assert.Equal(t, *actual, *test.expectedResult)
@@ -488,8 +523,9 @@ func TestNewsService(t *testing.T) {
 		defer ctrl.Finish()
 		mockNewsRepo := news_mock.NewMockRepository(ctrl)
 		mockTagRepo := tag_mock.NewMockRepository(ctrl)
+		mockMediaRepo := media_mock.NewMockRepository(ctrl)
 		mockCache := news_mock.NewMockCache(ctrl)
-		service := news.NewService(mockNewsRepo, mockTagRepo, mockCache)
+		service := news.NewService(mockNewsRepo, mockTagRepo, mockMediaRepo, mockCache)
 
 		sliceTest := []struct {
 			testTitle      string
@@ -525,7 +561,7 @@ func TestNewsService(t *testing.T) {
 				testTitle: "success from DB",
 				mockSetup: func(ctx context.Context, repo *news_mock.MockRepository, tagRepo *tag_mock.MockRepository, cache *news_mock.MockCache) {
 					cache.EXPECT().GetSliceNews(ctx, "all:").Return(nil, failure.InternalServerError)
-					repo.EXPECT().GetAllNews(ctx).Return(&entities.SliceNews{{
+					repo.EXPECT().GetAllNews(ctx, "").Return(&entities.SliceNews{{
 						ID:        "d2668631-1563-46bd-9498-5bfac7eed17a",
 						Title:     "first title",
 						Slug:      "first-title",
@@ -549,23 +585,27 @@ func TestNewsService(t *testing.T) {
 						},
 					}, nil)
 					cache.EXPECT().SetSliceNews(ctx, "all:", &entities.SliceNewsDto{{
-						ID:      "d2668631-1563-46bd-9498-5bfac7eed17a",
-						Title:   "first title",
-						Slug:    "first-title",
-						Content: "content first",
-						Topic:   "football",
-						Status:  "publish",
-						Tags:    []string{"tags1", "tags2"},
+						ID:            "d2668631-1563-46bd-9498-5bfac7eed17a",
+						Title:         "first title",
+						Slug:          "first-title",
+						Content:       "content first",
+						ContentFormat: "plain",
+						ContentHTML:   "<p>content first</p>\n",
+						Topic:         "football",
+						Status:        "publish",
+						Tags:          []string{"tags1", "tags2"},
 					}}).Return(nil)
 				},
 				expectedResult: &entities.SliceNewsDto{{
-					ID:      "d2668631-1563-46bd-9498-5bfac7eed17a",
-					Title:   "first title",
-					Slug:    "first-title",
-					Content: "content first",
-					Topic:   "football",
-					Status:  "publish",
-					Tags:    []string{"tags1", "tags2"},
+					ID:            "d2668631-1563-46bd-9498-5bfac7eed17a",
+					Title:         "first title",
+					Slug:          "first-title",
+					Content:       "content first",
+					ContentFormat: "plain",
+					ContentHTML:   "<p>content first</p>\n",
+					Topic:         "football",
+					Status:        "publish",
+					Tags:          []string{"tags1", "tags2"},
 				}},
 				expectedError: nil,
 			},
@@ -575,7 +615,7 @@ func TestNewsService(t *testing.T) {
 			t.Run(test.testTitle, func(t *testing.T) {
 				ctx := context.Background()
 				test.mockSetup(ctx, mockNewsRepo, mockTagRepo, mockCache)
-				actual, err := service.GetAll(ctx)
+				actual, err := service.GetAll(ctx, "")
 				assert.Equal(t, err, test.expectedError)
 				if test.expectedResult != nil {
 					assert.Equal(t, *actual, *test.expectedResult)
@@ -603,11 +643,34 @@ func TestNewsService(t *testing.T) {
 		defer ctrl.Finish()
 		mockNewsRepo := news_mock.NewMockRepository(ctrl)
 		mockTagRepo := tag_mock.NewMockRepository(ctrl)
+		mockMediaRepo := media_mock.NewMockRepository(ctrl)
 		mockCache := news_mock.NewMockCache(ctrl)
-		service := news.NewService(mockNewsRepo, mockTagRepo, mockCache)
+		service := news.NewService(mockNewsRepo, mockTagRepo, mockMediaRepo, mockCache)
 		setup := func(ctx context.Context, repo *news_mock.MockRepository, input *entities.NewsDto, err error) {
 			dto, _ := input.ToNews()
+			stored := *dto
+			stored.TranslationGroupID = "group1"
+			stored.ContentFormat = entities.ContentMarkdown
+			stored.Title = "stored title"
+			stored.Language = "en"
+			repo.EXPECT().GetNewsByID(ctx, dto.ID).Return(&stored, nil)
+			// without a translation group, a content format or a language the news keeps the stored one
+			if dto.TranslationGroupID == "" {
+				dto.TranslationGroupID = stored.TranslationGroupID
+			}
+			if input.ContentFormat == "" {
+				dto.ContentFormat = stored.ContentFormat
+			}
+			if dto.Language == "" {
+				dto.Language = stored.Language
+			}
+			dto.SetLanguageDefaults()
+			dto.Version = input.Version
+			repo.EXPECT().GetNewsByTranslationGroup(ctx, dto.TranslationGroupID).Return(&entities.SliceNews{*dto}, nil)
 			repo.EXPECT().UpdateNews(ctx, dto).Return(err)
+			if err == nil {
+				mockCache.EXPECT().Delete(ctx, cacheKeys(&stored, dto)...).Return(nil)
+			}
 		}
 		sliceTest := []struct {
 			testTitle      string
@@ -629,6 +692,52 @@ func TestNewsService(t *testing.T) {
 				},
 				expectedResult: nil,
 			},
+			{
+				testTitle: "update into its own translation group",
+				mockSetup: setup,
+				input: &entities.NewsDto{
+					ID:                 "d2668631-1563-46bd-9498-5bfac7eed17a",
+					Title:              "first title",
+					Slug:               "first-title",
+					Content:            "content first",
+					Topic:              "football",
+					Status:             "publish",
+					Tags:               []string{"tags1", "tags2"},
+					TranslationGroupID: "d2668631-1563-46bd-9498-5bfac7eed17a",
+				},
+				expectedResult: nil,
+			},
+			{
+				testTitle: "update with a content format",
+				mockSetup: setup,
+				input: &entities.NewsDto{
+					ID:                 "d2668631-1563-46bd-9498-5bfac7eed17a",
+					Title:              "first title",
+					Slug:               "first-title",
+					Content:            "content first",
+					ContentFormat:      "html",
+					Topic:              "football",
+					Status:             "publish",
+					Tags:               []string{"tags1", "tags2"},
+					TranslationGroupID: "d2668631-1563-46bd-9498-5bfac7eed17a",
+				},
+				expectedResult: nil,
+			},
+			{
+				testTitle: "update with a language",
+				mockSetup: setup,
+				input: &entities.NewsDto{
+					ID:       "d2668631-1563-46bd-9498-5bfac7eed17a",
+					Title:    "first title",
+					Slug:     "first-title",
+					Content:  "content first",
+					Topic:    "football",
+					Status:   "publish",
+					Tags:     []string{"tags1", "tags2"},
+					Language: "id",
+				},
+				expectedResult: nil,
+			},
 			{
 				testTitle: "update fail",
 				mockSetup: setup,
@@ -641,7 +750,22 @@ func TestNewsService(t *testing.T) {
 					Status:  "publish",
 					Tags:    []string{"tags1", "tags2"},
 				},
-				expectedResult: failure.NotFound("news not found"),
+				expectedResult: news.ErrNotFound,
+			},
+			{
+				testTitle: "update stale version",
+				mockSetup: setup,
+				input: &entities.NewsDto{
+					ID:      "d2668631-1563-46bd-9498-5bfac7eed17a",
+					Title:   "first title",
+					Slug:    "first-title",
+					Content: "content first",
+					Topic:   "football",
+					Status:  "publish",
+					Tags:    []string{"tags1", "tags2"},
+					Version: 2,
+				},
+				expectedResult: news.ErrVersionConflict,
 			},
 		}
 
@@ -672,10 +796,19 @@ func TestNewsService(t *testing.T) {
 		defer ctrl.Finish()
 		mockNewsRepo := news_mock.NewMockRepository(ctrl)
 		mockTagRepo := tag_mock.NewMockRepository(ctrl)
+		mockMediaRepo := media_mock.NewMockRepository(ctrl)
 		mockCache := news_mock.NewMockCache(ctrl)
-		service := news.NewService(mockNewsRepo, mockTagRepo, mockCache)
+		service := news.NewService(mockNewsRepo, mockTagRepo, mockMediaRepo, mockCache)
 		setup := func(ctx context.Context, repo *news_mock.MockRepository, input string, err error) {
+			stored := entities.News{ID: input, Slug: "first-title", Language: "en", Topic: "football",
+				Status: entities.NewsPublish}
+			repo.EXPECT().GetNewsByID(ctx, input).Return(&stored, nil)
 			repo.EXPECT().DeleteNews(ctx, input).Return(err)
+			if err == nil {
+				deleted := stored
+				deleted.Delete()
+				mockCache.EXPECT().Delete(ctx, cacheKeys(&stored, &deleted)...).Return(nil)
+			}
 		}
 		sliceTest := []struct {
 			testTitle      string
@@ -693,7 +826,7 @@ func TestNewsService(t *testing.T) {
 				testTitle:      "update fail",
 				mockSetup:      setup,
 				input:          "d2668631",
-				expectedResult: failure.NotFound("news not found"),
+				expectedResult: news.ErrNotFound,
 			},
 		}
 
@@ -706,4 +839,284 @@ func TestNewsService(t *testing.T) {
 			})
 		}
 	})
+
+	t.Run("testGetTranslations", func(t *testing.T) {
+		// setup
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockNewsRepo := news_mock.NewMockRepository(ctrl)
+		mockTagRepo := tag_mock.NewMockRepository(ctrl)
+		mockMediaRepo := media_mock.NewMockRepository(ctrl)
+		mockCache := news_mock.NewMockCache(ctrl)
+		service := news.NewService(mockNewsRepo, mockTagRepo, mockMediaRepo, mockCache)
+
+		original := entities.News{
+			ID:                 "id-news",
+			Title:              "judul",
+			Slug:               "judul",
+			Content:            "isi",
+			Language:           "id",
+			TranslationGroupID: "id-news",
+			Status:             entities.NewsPublish,
+			Tags:               []string{"id1"},
+		}
+		translation := entities.News{
+			ID:                 "en-news",
+			Title:              "title",
+			Slug:               "title",
+			Content:            "content",
+			Language:           "en",
+			TranslationGroupID: "id-news",
+			Status:             entities.NewsPublish,
+			Tags:               []string{"id1"},
+		}
+
+		sliceTest := []struct {
+			testTitle      string
+			mockSetup      func(ctx context.Context)
+			expectedResult *entities.SliceNewsDto
+			expectedError  error
+		}{
+			{
+				testTitle: "success",
+				mockSetup: func(ctx context.Context) {
+					mockNewsRepo.EXPECT().GetNewsBySlug(ctx, "judul", "id").Return(&original, nil)
+					mockNewsRepo.EXPECT().GetNewsByTranslationGroup(ctx, "id-news").
+						Return(&entities.SliceNews{translation, original}, nil)
+					mockTagRepo.EXPECT().GetTagByIds(ctx, []string{"id1"}).
+						Return(&entities.Tags{{ID: "id1", Name: "tags1", Status: entities.TagActive}}, nil)
+				},
+				expectedResult: &entities.SliceNewsDto{{
+					ID:                 "en-news",
+					Title:              "title",
+					Slug:               "title",
+					Content:            "content",
+					ContentFormat:      "plain",
+					ContentHTML:        "<p>content</p>\n",
+					Language:           "en",
+					TranslationGroupID: "id-news",
+					Status:             "publish",
+					Tags:               []string{"tags1"},
+				}},
+			},
+			{
+				testTitle: "no translation",
+				mockSetup: func(ctx context.Context) {
+					mockNewsRepo.EXPECT().GetNewsBySlug(ctx, "judul", "id").Return(&original, nil)
+					mockNewsRepo.EXPECT().GetNewsByTranslationGroup(ctx, "id-news").
+						Return(&entities.SliceNews{original}, nil)
+				},
+				expectedResult: &entities.SliceNewsDto{},
+			},
+			{
+				testTitle: "draft translation",
+				mockSetup: func(ctx context.Context) {
+					draft := translation
+					draft.Status = entities.NewsDraft
+					mockNewsRepo.EXPECT().GetNewsBySlug(ctx, "judul", "id").Return(&original, nil)
+					mockNewsRepo.EXPECT().GetNewsByTranslationGroup(ctx, "id-news").
+						Return(&entities.SliceNews{draft, original}, nil)
+				},
+				expectedResult: &entities.SliceNewsDto{},
+			},
+			{
+				testTitle: "news not found",
+				mockSetup: func(ctx context.Context) {
+					mockNewsRepo.EXPECT().GetNewsBySlug(ctx, "judul", "id").Return(nil, news.ErrNotFound)
+				},
+				expectedResult: nil,
+				expectedError:  news.ErrNotFound,
+			},
+		}
+
+		for _, test := range sliceTest {
+			t.Run(test.testTitle, func(t *testing.T) {
+				ctx := context.Background()
+				test.mockSetup(ctx)
+				actual, err := service.GetTranslations(ctx, "judul", "id")
+				assert.Equal(t, err, test.expectedError)
+				assert.Equal(t, actual, test.expectedResult)
+			})
+		}
+	})
+
+	t.Run("testTranslationGroup", func(t *testing.T) {
+		// setup
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockNewsRepo := news_mock.NewMockRepository(ctrl)
+		mockTagRepo := tag_mock.NewMockRepository(ctrl)
+		mockMediaRepo := media_mock.NewMockRepository(ctrl)
+		mockCache := news_mock.NewMockCache(ctrl)
+		service := news.NewService(mockNewsRepo, mockTagRepo, mockMediaRepo, mockCache)
+
+		group := entities.SliceNews{{ID: "id-news", Language: "id", TranslationGroupID: "id-news",
+			Status: entities.NewsDraft}}
+		sliceTest := []struct {
+			testTitle     string
+			mockSetup     func(ctx context.Context)
+			language      string
+			group         string
+			expectedError error
+		}{
+			{
+				testTitle: "join a group",
+				mockSetup: func(ctx context.Context) {
+					mockNewsRepo.EXPECT().GetNewsByTranslationGroup(ctx, "id-news").Return(&group, nil)
+					mockNewsRepo.EXPECT().CreateNews(ctx, gomock.Any()).Return(nil)
+					mockCache.EXPECT().Delete(ctx, gomock.Any()).Return(nil)
+				},
+				language: "en",
+				group:    "id-news",
+			},
+			{
+				testTitle: "group not found",
+				mockSetup: func(ctx context.Context) {
+					mockNewsRepo.EXPECT().GetNewsByTranslationGroup(ctx, "missing").Return(nil, news.ErrNotFound)
+				},
+				language:      "en",
+				group:         "missing",
+				expectedError: news.ErrTranslationGroupNotFound,
+			},
+			{
+				testTitle: "language already in the group",
+				mockSetup: func(ctx context.Context) {
+					mockNewsRepo.EXPECT().GetNewsByTranslationGroup(ctx, "id-news").Return(&group, nil)
+				},
+				language:      "id",
+				group:         "id-news",
+				expectedError: news.ErrTranslationExists,
+			},
+		}
+
+		for _, test := range sliceTest {
+			t.Run(test.testTitle, func(t *testing.T) {
+				ctx := context.Background()
+				test.mockSetup(ctx)
+				_, err := service.Create(ctx, &entities.NewsDto{Title: "title", Content: "content", Topic: "football",
+					Status: "draft", Tags: []string{"id1"}, Language: test.language, TranslationGroupID: test.group})
+				assert.Equal(t, err, test.expectedError)
+			})
+		}
+	})
+
+	t.Run("testSearch", func(t *testing.T) {
+		// setup
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockNewsRepo := news_mock.NewMockRepository(ctrl)
+		mockTagRepo := tag_mock.NewMockRepository(ctrl)
+		mockMediaRepo := media_mock.NewMockRepository(ctrl)
+		mockCache := news_mock.NewMockCache(ctrl)
+		service := news.NewService(mockNewsRepo, mockTagRepo, mockMediaRepo, mockCache)
+
+		sliceTest := []struct {
+			testTitle      string
+			input          string
+			mockSetup      func(ctx context.Context)
+			expectedResult *entities.SliceNewsDto
+			expectedError  error
+		}{
+			{
+				testTitle: "success",
+				input:     "derby",
+				mockSetup: func(ctx context.Context) {
+					mockNewsRepo.EXPECT().SearchNews(ctx, "derby", "en").Return(&entities.SliceNews{{
+						ID:     "id-news",
+						Title:  "derby",
+						Slug:   "derby",
+						Status: entities.NewsPublish,
+						Tags:   []string{"id1"},
+					}}, nil)
+					mockTagRepo.EXPECT().GetTagByIds(ctx, []string{"id1"}).
+						Return(&entities.Tags{{ID: "id1", Name: "tags1", Status: entities.TagActive}}, nil)
+				},
+				expectedResult: &entities.SliceNewsDto{{
+					ID:            "id-news",
+					Title:         "derby",
+					Slug:          "derby",
+					ContentFormat: "plain",
+					Status:        "publish",
+					Tags:          []string{"tags1"},
+				}},
+			},
+			{
+				testTitle: "nothing found",
+				input:     "derby",
+				mockSetup: func(ctx context.Context) {
+					mockNewsRepo.EXPECT().SearchNews(ctx, "derby", "en").Return(nil, news.ErrNotFound)
+				},
+				expectedResult: &entities.SliceNewsDto{},
+			},
+			{
+				testTitle:     "error empty query",
+				input:         " ",
+				mockSetup:     func(ctx context.Context) {},
+				expectedError: failure.BadRequestWithString("search query can't be empty"),
+			},
+		}
+
+		for _, test := range sliceTest {
+			t.Run(test.testTitle, func(t *testing.T) {
+				ctx := context.Background()
+				test.mockSetup(ctx)
+				actual, err := service.Search(ctx, test.input, "en")
+				assert.Equal(t, err, test.expectedError)
+				assert.Equal(t, actual, test.expectedResult)
+			})
+		}
+	})
+}
+
+func TestServicePatch(t *testing.T) {
+	ctx := context.Background()
+	newsRepo := news.NewMemoryRepository()
+	service := news.NewService(newsRepo, tag.NewMemoryRepository(), media.NewMemoryRepository(), news.NewMemoryCache(0))
+	created, err := service.Create(ctx, &entities.NewsDto{Title: "derby day", Content: "the derby ends in a draw",
+		Status: "draft", Topic: "sport", Tags: []string{"tag1", "tag2"}})
+	assert.Equal(t, err, nil)
+	apply := func(contentType string, body string, version int) error {
+		p, err := patch.Parse(contentType, []byte(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return service.Patch(ctx, created.ID, version, p)
+	}
+
+	err = apply(patch.MergePatchType, `{"content": "a late winner", "tags": [], "status": "publish"}`, 0)
+	assert.Equal(t, err, nil)
+	actual, err := newsRepo.GetNewsByID(ctx, created.ID)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, actual.Title, "derby day")
+	assert.Equal(t, actual.Content, "a late winner")
+	assert.Equal(t, actual.Status, entities.NewsPublish)
+	assert.Equal(t, len(actual.Tags), 0)
+	assert.Equal(t, actual.Version, 2)
+
+	err = apply(patch.JSONPatchType, `[{"op": "test", "path": "/version", "value": 2},
+		{"op": "add", "path": "/tags/-", "value": "tag3"}]`, 2)
+	assert.Equal(t, err, nil)
+	actual, err = newsRepo.GetNewsByID(ctx, created.ID)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, actual.Tags, []string{"tag3"})
+
+	// the patched news is validated, not the patch
+	err = apply(patch.MergePatchType, `{"title": null}`, 0)
+	assert.Equal(t, err, failure.Validation([]failure.Violation{{Field: "title", Message: "title can't be null"}}))
+	err = apply(patch.JSONPatchType, `[{"op": "test", "path": "/status", "value": "draft"}]`, 0)
+	assert.Equal(t, failure.GetStatus(err), http.StatusConflict)
+	err = apply(patch.MergePatchType, `{"content": "stale"}`, 2)
+	assert.Equal(t, err, news.ErrVersionConflict)
+	err = apply(patch.MergePatchType, `{"content": "stale", "version": 1}`, 0)
+	assert.Equal(t, err, news.ErrVersionConflict)
+}
+
+// cacheKeys returns the keys the service drops for the states of a news, as mock arguments.
+func cacheKeys(states ...*entities.News) (keys []interface{}) {
+	for _, state := range states {
+		for _, key := range news.CacheKeys(state) {
+			keys = append(keys, key)
+		}
+	}
+	return
 }
@@ -0,0 +1,415 @@
+package news
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"news/domain/entities"
+	"news/domain/media"
+	"news/domain/tag"
+	"news/shared/failure"
+	"news/shared/logger"
+	"strings"
+	"time"
+)
+
+const (
+	FormatJSONL = "jsonl"
+	FormatCSV   = "csv"
+
+	importBatchSize = 100
+	csvTagSeparator = "|"
+)
+
+// ErrNotReadable is returned by imports of a file that can't be read to the end,
+// like a JSONL line longer than 16MB. Batches saved before stay saved.
+var ErrNotReadable = failure.New(http.StatusBadRequest, "import.not_readable", "can't read the file")
+
+// ErrTagNotCreated is the error of the rows with a missing tag the import failed to create.
+var ErrTagNotCreated = failure.New(http.StatusInternalServerError, "import.tag_not_created", "can't create tag")
+
+var csvHeader = []string{"id", "slug", "title", "content", "content_format", "status", "topic", "language",
+	"translation_group_id", "featured_image_id", "tags", "created_at"}
+
+// Transfer moves news in and out of the database as JSONL or CSV files.
+type Transfer interface {
+	Export(ctx context.Context, format string, w io.Writer) error
+	Import(ctx context.Context, format string, r io.Reader, dryRun bool) (*entities.ImportReport, error)
+}
+
+type transferImpl struct {
+	repo      Repository
+	tagRepo   tag.Repository
+	mediaRepo media.Repository
+}
+
+func NewTransfer(repo Repository, tagRepo tag.Repository, mediaRepo media.Repository) *transferImpl {
+	return &transferImpl{repo: repo, tagRepo: tagRepo, mediaRepo: mediaRepo}
+}
+
+func ValidateFormat(format string) error {
+	if format != FormatJSONL && format != FormatCSV {
+		return failure.BadRequestWithString("format not supported, use jsonl or csv")
+	}
+	return nil
+}
+
+func (t *transferImpl) Export(ctx context.Context, format string, w io.Writer) (err error) {
+	err = ValidateFormat(format)
+	if err != nil {
+		return
+	}
+
+	mapTags := map[string]entities.Tag{}
+	tags, err := t.tagRepo.GetAllTag(ctx)
+	if err == nil {
+		mapTags = tags.ToMapTags()
+	} else if failure.GetStatus(err) != http.StatusNotFound {
+		return
+	}
+
+	buf := bufio.NewWriter(w)
+	write := writeJSONL(buf)
+	var csvWriter *csv.Writer
+	if format == FormatCSV {
+		csvWriter = csv.NewWriter(buf)
+		write = writeCSV(csvWriter)
+		err = csvWriter.Write(csvHeader)
+		if err != nil {
+			return
+		}
+	}
+
+	err = t.repo.StreamNews(ctx, func(news *entities.News) error {
+		news.SetTagsFromMapTags(mapTags)
+		return write(news.ToNewsRecord())
+	})
+	if err != nil && failure.GetStatus(err) != http.StatusNotFound {
+		return
+	}
+	if csvWriter != nil {
+		csvWriter.Flush()
+		err = csvWriter.Error()
+		if err != nil {
+			return
+		}
+	}
+	return buf.Flush()
+}
+
+func writeJSONL(w io.Writer) func(record *entities.NewsRecord) error {
+	encoder := json.NewEncoder(w)
+	return func(record *entities.NewsRecord) error {
+		return encoder.Encode(record)
+	}
+}
+
+func writeCSV(w *csv.Writer) func(record *entities.NewsRecord) error {
+	return func(record *entities.NewsRecord) error {
+		return w.Write([]string{record.ID, record.Slug, record.Title, record.Content, record.ContentFormat,
+			record.Status, record.Topic, record.Language, record.TranslationGroupID, record.FeaturedImageID,
+			strings.Join(record.Tags, csvTagSeparator), record.CreatedAt.Format(time.RFC3339)})
+	}
+}
+
+type importRow struct {
+	row    int
+	record *entities.NewsRecord
+}
+
+// Import reads every record, validates it with NewsDto.Validate and the checks
+// of the service on its featured image and translation group, and saves valid
+// records in batches, each batch in one transaction. Tags are resolved by name
+// and created when missing. With dryRun nothing is written. A file that can't
+// be read to the end stops the import with ErrNotReadable.
+func (t *transferImpl) Import(ctx context.Context, format string, r io.Reader, dryRun bool) (report *entities.ImportReport, err error) {
+	err = ValidateFormat(format)
+	if err != nil {
+		return
+	}
+	next := readJSONL(r)
+	if format == FormatCSV {
+		next, err = readCSV(r)
+		if err != nil {
+			return
+		}
+	}
+
+	report = &entities.ImportReport{DryRun: dryRun, Errors: []entities.ImportError{}}
+	groups := map[string]entities.SliceNews{}
+	var batch []importRow
+	for row := 1; ; row++ {
+		record, errs := next()
+		if errs == io.EOF {
+			break
+		}
+		if errors.Is(errs, ErrNotReadable) {
+			return nil, errs
+		}
+		report.Total++
+		if errs != nil {
+			report.AddError(row, errs)
+			continue
+		}
+		errs = record.Validate()
+		if errs != nil {
+			report.AddError(row, errs)
+			continue
+		}
+		batch = append(batch, importRow{row: row, record: record})
+		if len(batch) == importBatchSize {
+			t.importBatch(ctx, batch, groups, report)
+			batch = nil
+		}
+	}
+	if len(batch) > 0 {
+		t.importBatch(ctx, batch, groups, report)
+	}
+	return
+}
+
+func (t *transferImpl) importBatch(ctx context.Context, batch []importRow, groups map[string]entities.SliceNews,
+	report *entities.ImportReport) {
+	tagIds, tagErrors := t.resolveTags(ctx, batch, report.DryRun)
+
+	var rows []importRow
+	var sliceNews entities.SliceNews
+	for _, item := range batch {
+		dto := item.record.NewsDto
+		dto.Tags = nil
+		var err error
+		for _, name := range item.record.Tags {
+			if tagErrors[name] != nil {
+				err = tagErrors[name]
+				break
+			}
+			dto.Tags = append(dto.Tags, tagIds[name])
+		}
+		if err != nil {
+			report.AddError(item.row, err)
+			continue
+		}
+
+		news, err := dto.ToNews()
+		if err != nil {
+			report.AddError(item.row, err)
+			continue
+		}
+		news.SetLanguageDefaults()
+		err = checkFeaturedImage(ctx, t.mediaRepo, news)
+		if err != nil {
+			report.AddError(item.row, err)
+			continue
+		}
+		// a record without a group keeps the group of the news it replaces, SaveNews resolves it
+		if dto.TranslationGroupID == "" {
+			news.TranslationGroupID = ""
+		} else {
+			err = t.joinGroup(ctx, news, groups)
+			if err != nil {
+				report.AddError(item.row, err)
+				continue
+			}
+		}
+		if !item.record.CreatedAt.IsZero() {
+			news.CreatedAt = item.record.CreatedAt
+		}
+		rows = append(rows, item)
+		sliceNews = append(sliceNews, *news)
+	}
+	if report.DryRun || len(sliceNews) < 1 {
+		report.Imported += len(sliceNews)
+		return
+	}
+
+	err := t.repo.SaveNews(ctx, sliceNews)
+	if err == nil {
+		report.Imported += len(sliceNews)
+		return
+	}
+	// the batch is rolled back as a whole, save rows one by one to find the failing ones
+	for i, news := range sliceNews {
+		err = t.repo.SaveNews(ctx, entities.SliceNews{news})
+		if err != nil {
+			report.AddError(rows[i].row, err)
+			continue
+		}
+		report.Imported++
+	}
+}
+
+// joinGroup checks news may join its translation group like the service does,
+// groups holds the groups of the rows checked before, they may not be stored yet.
+// A news in the group with the id or the slug of news is the one SaveNews replaces.
+func (t *transferImpl) joinGroup(ctx context.Context, news *entities.News, groups map[string]entities.SliceNews) error {
+	group, ok := groups[news.TranslationGroupID]
+	if !ok {
+		stored, err := t.repo.GetNewsByTranslationGroup(ctx, news.TranslationGroupID)
+		if err != nil && !errors.Is(err, ErrNotFound) {
+			return err
+		}
+		if err == nil {
+			group = *stored
+		}
+		groups[news.TranslationGroupID] = group
+	}
+	replaces := func(translation *entities.News) bool {
+		return translation.ID == news.ID || translation.Slug == news.Slug
+	}
+	err := joinGroup(news, group, replaces)
+	if err != nil {
+		return err
+	}
+
+	joined := entities.SliceNews{*news}
+	for i := range group {
+		if group[i].ID != news.ID && (group[i].Language != news.Language || group[i].Slug != news.Slug) {
+			joined = append(joined, group[i])
+		}
+	}
+	groups[news.TranslationGroupID] = joined
+	return nil
+}
+
+// resolveTags maps tag names to ids, creating the missing tags unless dryRun.
+func (t *transferImpl) resolveTags(ctx context.Context, batch []importRow, dryRun bool) (ids map[string]string, errs map[string]error) {
+	ids, errs = map[string]string{}, map[string]error{}
+	var names []string
+	for _, item := range batch {
+		for _, name := range item.record.Tags {
+			if _, ok := ids[name]; !ok {
+				ids[name] = ""
+				names = append(names, name)
+			}
+		}
+	}
+
+	tags, err := t.tagRepo.GetTagByNames(ctx, names)
+	if err != nil && failure.GetStatus(err) != http.StatusNotFound {
+		for _, name := range names {
+			errs[name] = err
+		}
+		return
+	}
+	if err == nil {
+		for _, tag := range *tags {
+			ids[tag.Name] = tag.ID
+		}
+	}
+
+	for _, name := range names {
+		if ids[name] != "" {
+			continue
+		}
+		createTag := entities.CreateTag{Name: name}
+		err = createTag.Validate()
+		if err != nil {
+			errs[name] = err
+			continue
+		}
+		if dryRun {
+			ids[name] = name
+			continue
+		}
+		created, err := t.tagRepo.CreateTag(ctx, createTag.ToTag())
+		if err != nil {
+			logger.ErrorWithStack(ctx, err)
+			errs[name] = failure.New(ErrTagNotCreated.Status, ErrTagNotCreated.Code,
+				fmt.Sprintf("%s %q", ErrTagNotCreated.Message, name)).Wrap(err)
+			continue
+		}
+		ids[name] = created.ID
+	}
+	return
+}
+
+func readJSONL(r io.Reader) func() (*entities.NewsRecord, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	return func() (*entities.NewsRecord, error) {
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			record := &entities.NewsRecord{}
+			err := json.Unmarshal([]byte(line), record)
+			if err != nil {
+				return nil, failure.BadRequestWithString("invalid json: " + err.Error())
+			}
+			return record, nil
+		}
+		if scanner.Err() != nil {
+			return nil, notReadable(scanner.Err())
+		}
+		return nil, io.EOF
+	}
+}
+
+// notReadable returns ErrNotReadable with the reason in its message.
+func notReadable(err error) error {
+	return failure.New(ErrNotReadable.Status, ErrNotReadable.Code, ErrNotReadable.Message+": "+err.Error())
+}
+
+func readCSV(r io.Reader) (func() (*entities.NewsRecord, error), error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, failure.BadRequestWithString("can't read csv header")
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.TrimSpace(name)] = i
+	}
+
+	return func() (*entities.NewsRecord, error) {
+		values, err := reader.Read()
+		if err == io.EOF {
+			return nil, err
+		}
+		// a row that isn't valid csv fails alone, the reader goes on with the next one
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			return nil, failure.BadRequestWithString("invalid csv: " + err.Error())
+		}
+		if err != nil {
+			return nil, notReadable(err)
+		}
+		get := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(values) {
+				return values[i]
+			}
+			return ""
+		}
+		record := &entities.NewsRecord{NewsDto: entities.NewsDto{
+			ID:                 get("id"),
+			Slug:               get("slug"),
+			Title:              get("title"),
+			Content:            get("content"),
+			ContentFormat:      get("content_format"),
+			Status:             get("status"),
+			Topic:              get("topic"),
+			Language:           get("language"),
+			TranslationGroupID: get("translation_group_id"),
+			FeaturedImageID:    get("featured_image_id"),
+		}}
+		for _, name := range strings.Split(get("tags"), csvTagSeparator) {
+			if name = strings.TrimSpace(name); name != "" {
+				record.Tags = append(record.Tags, name)
+			}
+		}
+		if createdAt := get("created_at"); createdAt != "" {
+			record.CreatedAt, err = time.Parse(time.RFC3339, createdAt)
+			if err != nil {
+				return nil, failure.BadRequestWithString("created_at must be RFC3339")
+			}
+		}
+		return record, nil
+	}, nil
+}
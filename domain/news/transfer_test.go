@@ -0,0 +1,255 @@
+package news_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"io"
+	"news/domain/entities"
+	media_mock "news/domain/media/mock"
+	"news/domain/news"
+	news_mock "news/domain/news/mock"
+	tag_mock "news/domain/tag/mock"
+	"news/shared/Date"
+	"news/shared/IDGEN"
+	"news/shared/failure"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestNewsTransfer(t *testing.T) {
+	t.Run("testExport", func(t *testing.T) {
+		mockTime := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+		// setup
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockNewsRepo := news_mock.NewMockRepository(ctrl)
+		mockTagRepo := tag_mock.NewMockRepository(ctrl)
+		transfer := news.NewTransfer(mockNewsRepo, mockTagRepo, media_mock.NewMockRepository(ctrl))
+
+		sliceTest := []struct {
+			testTitle      string
+			format         string
+			expectedResult string
+			expectedError  error
+		}{
+			{
+				testTitle:      "export csv",
+				format:         "csv",
+				expectedResult: "id,slug,title,content,content_format,status,topic,language,translation_group_id,featured_image_id,tags,created_at\nid1,first-title,first title,\"content, first\",plain,publish,football,id,id1,,tags1|tags2,2022-05-01T10:00:00Z\n",
+			},
+			{
+				testTitle:     "error format",
+				format:        "xml",
+				expectedError: failure.BadRequestWithString("format not supported, use jsonl or csv"),
+			},
+		}
+
+		for _, test := range sliceTest {
+			t.Run(test.testTitle, func(t *testing.T) {
+				ctx := context.Background()
+				if test.expectedError == nil {
+					mockTagRepo.EXPECT().GetAllTag(ctx).Return(&entities.Tags{
+						{ID: "tag1", Name: "tags1"},
+						{ID: "tag2", Name: "tags2"},
+					}, nil)
+					mockNewsRepo.EXPECT().StreamNews(ctx, gomock.Any()).DoAndReturn(
+						func(ctx context.Context, fn func(*entities.News) error) error {
+							return fn(&entities.News{
+								ID:                 "id1",
+								Title:              "first title",
+								Slug:               "first-title",
+								Content:            "content, first",
+								Status:             entities.NewsPublish,
+								Topic:              "football",
+								Language:           "id",
+								TranslationGroupID: "id1",
+								Tags:               []string{"tag1", "tag2"},
+								CreatedAt:          mockTime,
+							})
+						})
+				}
+				var buf bytes.Buffer
+				err := transfer.Export(ctx, test.format, &buf)
+				assert.Equal(t, err, test.expectedError)
+				assert.Equal(t, buf.String(), test.expectedResult)
+			})
+		}
+	})
+
+	t.Run("testImport", func(t *testing.T) {
+		//mock uuid
+		IDGEN.NewUUID = func() string {
+			return "d2668631-1563-46bd-9498-5bfac7eed17a"
+		}
+
+		//mock time
+		mockTime := time.Now()
+		Date.Now = func() time.Time {
+			return mockTime
+		}
+
+		// setup
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockNewsRepo := news_mock.NewMockRepository(ctrl)
+		mockTagRepo := tag_mock.NewMockRepository(ctrl)
+		transfer := news.NewTransfer(mockNewsRepo, mockTagRepo, media_mock.NewMockRepository(ctrl))
+
+		input := `{"id":"id1","title":"first title","content":"content first","status":"publish","topic":"football","tags":["tags1"]}
+{"title":"second title","content":"content second","status":"publish","topic":"football","tags":["tags2"]}
+{"title":"","content":"content third","status":"publish","topic":"football","tags":["tags1"]}
+not json
+`
+
+		sliceTest := []struct {
+			testTitle      string
+			dryRun         bool
+			mockSetup      func(ctx context.Context, repo *news_mock.MockRepository, tagRepo *tag_mock.MockRepository)
+			expectedResult *entities.ImportReport
+		}{
+			{
+				testTitle: "dry run writes nothing",
+				dryRun:    true,
+				mockSetup: func(ctx context.Context, repo *news_mock.MockRepository, tagRepo *tag_mock.MockRepository) {
+					tagRepo.EXPECT().GetTagByNames(ctx, []string{"tags1", "tags2"}).Return(&entities.Tags{
+						{ID: "tag1", Name: "tags1"},
+					}, nil)
+				},
+				expectedResult: &entities.ImportReport{DryRun: true, Total: 4, Imported: 2, Failed: 2, Errors: []entities.ImportError{
+					{Row: 3, Error: "title can't be null"},
+					{Row: 4, Error: "invalid json: invalid character 'o' in literal null (expecting 'u')"},
+				}},
+			},
+			{
+				testTitle: "failed batch is retried row by row",
+				mockSetup: func(ctx context.Context, repo *news_mock.MockRepository, tagRepo *tag_mock.MockRepository) {
+					tagRepo.EXPECT().GetTagByNames(ctx, []string{"tags1", "tags2"}).Return(&entities.Tags{
+						{ID: "tag1", Name: "tags1"},
+					}, nil)
+					tagRepo.EXPECT().CreateTag(ctx, gomock.Any()).Return(&entities.Tag{ID: "tag2", Name: "tags2"}, nil)
+					repo.EXPECT().SaveNews(ctx, gomock.Len(2)).Return(failure.BadRequestWithString("slug already used"))
+					repo.EXPECT().SaveNews(ctx, gomock.Len(1)).DoAndReturn(
+						func(ctx context.Context, sliceNews entities.SliceNews) error {
+							assert.Equal(t, sliceNews[0].Tags, []string{"tag1"})
+							return nil
+						})
+					repo.EXPECT().SaveNews(ctx, gomock.Len(1)).Return(failure.BadRequestWithString("slug already used"))
+				},
+				expectedResult: &entities.ImportReport{Total: 4, Imported: 1, Failed: 3, Errors: []entities.ImportError{
+					{Row: 3, Error: "title can't be null"},
+					{Row: 4, Error: "invalid json: invalid character 'o' in literal null (expecting 'u')"},
+					{Row: 2, Error: "slug already used"},
+				}},
+			},
+			{
+				testTitle: "rows of a tag that can't be created fail",
+				mockSetup: func(ctx context.Context, repo *news_mock.MockRepository, tagRepo *tag_mock.MockRepository) {
+					tagRepo.EXPECT().GetTagByNames(ctx, []string{"tags1", "tags2"}).Return(&entities.Tags{
+						{ID: "tag1", Name: "tags1"},
+					}, nil)
+					tagRepo.EXPECT().CreateTag(ctx, gomock.Any()).Return(nil, errors.New("connection refused"))
+					repo.EXPECT().SaveNews(ctx, gomock.Len(1)).Return(nil)
+				},
+				expectedResult: &entities.ImportReport{Total: 4, Imported: 1, Failed: 3, Errors: []entities.ImportError{
+					{Row: 3, Error: "title can't be null"},
+					{Row: 4, Error: "invalid json: invalid character 'o' in literal null (expecting 'u')"},
+					{Row: 2, Error: `can't create tag "tags2"`},
+				}},
+			},
+		}
+
+		for _, test := range sliceTest {
+			t.Run(test.testTitle, func(t *testing.T) {
+				ctx := context.Background()
+				test.mockSetup(ctx, mockNewsRepo, mockTagRepo)
+				actual, err := transfer.Import(ctx, "jsonl", strings.NewReader(input), test.dryRun)
+				assert.Equal(t, err, nil)
+				assert.Equal(t, *actual, *test.expectedResult)
+			})
+		}
+	})
+	t.Run("testImportChecks", func(t *testing.T) {
+		// setup
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockNewsRepo := news_mock.NewMockRepository(ctrl)
+		mockTagRepo := tag_mock.NewMockRepository(ctrl)
+		mockMediaRepo := media_mock.NewMockRepository(ctrl)
+		transfer := news.NewTransfer(mockNewsRepo, mockTagRepo, mockMediaRepo)
+
+		input := `{"id":"id1","title":"first title","content":"content","status":"publish","topic":"football","tags":["tags1"],"featured_image_id":"missing"}
+{"id":"id2","title":"second title","content":"content","status":"publish","topic":"football","tags":["tags1"],"language":"id","translation_group_id":"group1"}
+{"id":"id3","title":"third title","content":"content","status":"publish","topic":"football","tags":["tags1"],"language":"en","translation_group_id":"group1"}
+{"id":"id4","title":"fourth title","content":"content","status":"publish","topic":"football","tags":["tags1"],"language":"en","translation_group_id":"group1"}
+{"id":"id5","title":"fifth title","content":"content","status":"publish","topic":"football","tags":["tags1"],"translation_group_id":"missing"}
+{"title":"group title","content":"content","status":"publish","topic":"football","tags":["tags1"],"language":"id","translation_group_id":"group1"}
+`
+		ctx := context.Background()
+		mockTagRepo.EXPECT().GetTagByNames(ctx, []string{"tags1"}).Return(&entities.Tags{{ID: "tag1", Name: "tags1"}}, nil)
+		mockMediaRepo.EXPECT().GetMediaByID(ctx, "missing").Return(nil, failure.NotFound("media not found"))
+		// the group is read once, the rows after see the ones imported before them
+		mockNewsRepo.EXPECT().GetNewsByTranslationGroup(ctx, "group1").Return(&entities.SliceNews{
+			{ID: "group1", Slug: "group-title", Language: "id", TranslationGroupID: "group1"},
+		}, nil)
+		mockNewsRepo.EXPECT().GetNewsByTranslationGroup(ctx, "missing").Return(nil, news.ErrNotFound)
+		mockNewsRepo.EXPECT().SaveNews(ctx, gomock.Len(2)).DoAndReturn(
+			func(ctx context.Context, sliceNews entities.SliceNews) error {
+				assert.Equal(t, sliceNews[0].ID, "id3")
+				assert.Equal(t, sliceNews[1].Slug, "group-title")
+				return nil
+			})
+
+		actual, err := transfer.Import(ctx, "jsonl", strings.NewReader(input), false)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, *actual, entities.ImportReport{Total: 6, Imported: 2, Failed: 4, Errors: []entities.ImportError{
+			{Row: 1, Error: "featured image not found"},
+			{Row: 2, Error: "the translation group already has a news in this language"},
+			{Row: 4, Error: "the translation group already has a news in this language"},
+			{Row: 5, Error: "translation group not found"},
+		}})
+	})
+	t.Run("testImportNotReadable", func(t *testing.T) {
+		// setup
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		transfer := news.NewTransfer(news_mock.NewMockRepository(ctrl), tag_mock.NewMockRepository(ctrl),
+			media_mock.NewMockRepository(ctrl))
+
+		record := `{"title":"first title","content":"content first","status":"publish","topic":"football"}`
+		sliceTest := []struct {
+			testTitle     string
+			format        string
+			input         io.Reader
+			expectedError string
+		}{
+			{
+				testTitle:     "line longer than 16MB",
+				format:        "jsonl",
+				input:         strings.NewReader(record + "\n" + strings.Repeat("a", 16*1024*1024+1) + "\n" + record),
+				expectedError: "can't read the file: bufio.Scanner: token too long",
+			},
+			{
+				testTitle: "csv read error",
+				format:    "csv",
+				input: io.MultiReader(strings.NewReader("title,content,status,topic\nfirst title,content,publish,football\n"),
+					iotest.ErrReader(errors.New("connection reset"))),
+				expectedError: "can't read the file: connection reset",
+			},
+		}
+
+		for _, test := range sliceTest {
+			t.Run(test.testTitle, func(t *testing.T) {
+				actual, err := transfer.Import(context.Background(), test.format, test.input, false)
+				assert.Equal(t, errors.Is(err, news.ErrNotReadable), true)
+				assert.Equal(t, err.Error(), test.expectedError)
+				assert.Equal(t, actual == nil, true)
+			})
+		}
+	})
+}
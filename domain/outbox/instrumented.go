@@ -0,0 +1,48 @@
+package outbox
+
+import (
+	"context"
+	"news/domain/entities"
+	"news/shared/metrics"
+	"time"
+)
+
+// instrumentedRepository observes the duration of every call of the wrapped
+// repository in the repository_query_duration_seconds metric.
+type instrumentedRepository struct {
+	repo Repository
+}
+
+func NewInstrumentedRepository(repo Repository) *instrumentedRepository {
+	return &instrumentedRepository{repo: repo}
+}
+
+func (r *instrumentedRepository) GetPending(ctx context.Context, limit int) (*entities.Events, error) {
+	defer metrics.ObserveQuery("outbox", "GetPending", time.Now())
+	return r.repo.GetPending(ctx, limit)
+}
+
+func (r *instrumentedRepository) Claim(ctx context.Context, id string, now time.Time, until time.Time) (bool, error) {
+	defer metrics.ObserveQuery("outbox", "Claim", time.Now())
+	return r.repo.Claim(ctx, id, now, until)
+}
+
+func (r *instrumentedRepository) MarkPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	defer metrics.ObserveQuery("outbox", "MarkPublished", time.Now())
+	return r.repo.MarkPublished(ctx, id, publishedAt)
+}
+
+func (r *instrumentedRepository) MarkFailed(ctx context.Context, id string) error {
+	defer metrics.ObserveQuery("outbox", "MarkFailed", time.Now())
+	return r.repo.MarkFailed(ctx, id)
+}
+
+func (r *instrumentedRepository) MarkDead(ctx context.Context, id string, failedAt time.Time) error {
+	defer metrics.ObserveQuery("outbox", "MarkDead", time.Now())
+	return r.repo.MarkDead(ctx, id, failedAt)
+}
+
+func (r *instrumentedRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	defer metrics.ObserveQuery("outbox", "Purge", time.Now())
+	return r.repo.Purge(ctx, before)
+}
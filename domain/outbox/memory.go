@@ -0,0 +1,109 @@
+package outbox
+
+import (
+	"context"
+	"news/domain/entities"
+	"sync"
+	"time"
+)
+
+// memoryRepository is the outbox of the memory repositories, events are kept
+// in the order they were added.
+type memoryRepository struct {
+	mu       sync.Mutex
+	events   entities.Events
+	sequence int64
+}
+
+func NewMemoryRepository() *memoryRepository {
+	return &memoryRepository{}
+}
+
+func (r *memoryRepository) Add(ctx context.Context, events ...entities.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, event := range events {
+		r.sequence++
+		event.Sequence = r.sequence
+		r.events = append(r.events, event)
+	}
+	return nil
+}
+
+func (r *memoryRepository) GetPending(ctx context.Context, limit int) (*entities.Events, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := entities.Events{}
+	for _, event := range r.events {
+		if event.PublishedAt == nil && event.FailedAt == nil && len(events) < limit {
+			events = append(events, event)
+		}
+	}
+	if len(events) < 1 {
+		return nil, ErrNotFound
+	}
+	return &events, nil
+}
+
+func (r *memoryRepository) Claim(ctx context.Context, id string, now time.Time, until time.Time) (bool, error) {
+	claimed := false
+	err := r.update(id, func(event *entities.Event) {
+		if event.PublishedAt != nil || event.FailedAt != nil {
+			return
+		}
+		if event.LockedUntil != nil && event.LockedUntil.After(now) {
+			return
+		}
+		event.LockedUntil = &until
+		claimed = true
+	})
+	return claimed, err
+}
+
+func (r *memoryRepository) MarkPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	return r.update(id, func(event *entities.Event) {
+		event.PublishedAt = &publishedAt
+		event.LockedUntil = nil
+	})
+}
+
+func (r *memoryRepository) MarkFailed(ctx context.Context, id string) error {
+	return r.update(id, func(event *entities.Event) {
+		event.Attempts++
+		event.LockedUntil = nil
+	})
+}
+
+func (r *memoryRepository) MarkDead(ctx context.Context, id string, failedAt time.Time) error {
+	return r.update(id, func(event *entities.Event) {
+		event.Attempts++
+		event.LockedUntil = nil
+		event.FailedAt = &failedAt
+	})
+}
+
+func (r *memoryRepository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	events := entities.Events{}
+	for _, event := range r.events {
+		if event.CreatedAt.Before(before) && (event.PublishedAt != nil || event.FailedAt != nil) {
+			continue
+		}
+		events = append(events, event)
+	}
+	deleted := int64(len(r.events) - len(events))
+	r.events = events
+	return deleted, nil
+}
+
+func (r *memoryRepository) update(id string, fn func(event *entities.Event)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.events {
+		if r.events[i].ID == id {
+			fn(&r.events[i])
+		}
+	}
+	return nil
+}
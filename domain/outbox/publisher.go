@@ -0,0 +1,80 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/rs/zerolog/log"
+	"news/domain/entities"
+	"sync"
+)
+
+// EventPublisher sends an event to the systems downstream. An error leaves the
+// event in the outbox, it is published again by the next relay pass.
+type EventPublisher interface {
+	Publish(ctx context.Context, event entities.EventDto) error
+}
+
+// logPublisher writes events to the log, it is the publisher until a broker is configured.
+type logPublisher struct{}
+
+func NewLogPublisher() *logPublisher {
+	return &logPublisher{}
+}
+
+func (p *logPublisher) Publish(ctx context.Context, event entities.EventDto) error {
+	value, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	log.Info().RawJSON("event", value).Msg("event published")
+	return nil
+}
+
+// MemoryPublisher keeps the published events, for tests.
+type MemoryPublisher struct {
+	mu     sync.Mutex
+	events []entities.EventDto
+	// Err is returned by Publish when set, without keeping the event.
+	Err error
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, event entities.EventDto) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.Err != nil {
+		return p.Err
+	}
+	p.events = append(p.events, event)
+	return nil
+}
+
+// Events returns the events published so far, in order.
+func (p *MemoryPublisher) Events() []entities.EventDto {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]entities.EventDto(nil), p.events...)
+}
+
+// multiPublisher publishes every event to each publisher in order. When one
+// fails the relay retries the event, the publishers before it see it again.
+type multiPublisher struct {
+	publishers []EventPublisher
+}
+
+func NewMultiPublisher(publishers ...EventPublisher) *multiPublisher {
+	return &multiPublisher{publishers: publishers}
+}
+
+func (p *multiPublisher) Publish(ctx context.Context, event entities.EventDto) error {
+	for _, publisher := range p.publishers {
+		err := publisher.Publish(ctx, event)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"news/shared/Date"
+	"news/shared/logger"
+	"time"
+)
+
+// claimLease is how long a relay holds an event it publishes, another relay
+// takes the event over once it is past, the publish is then repeated.
+const claimLease = time.Minute
+
+// Relay moves the events of the outbox to an EventPublisher. Events are
+// published one at a time in the order they were written, a failing event
+// stops the pass so later events never overtake it, until it failed
+// maxAttempts times: it is then dead and skipped. Every event is claimed
+// before it is published, relays of several instances share the outbox
+// without publishing an event twice.
+type Relay struct {
+	repo        Repository
+	publisher   EventPublisher
+	interval    time.Duration
+	batchSize   int
+	maxAttempts int
+}
+
+func NewRelay(repo Repository, publisher EventPublisher, interval time.Duration, batchSize int, maxAttempts int) *Relay {
+	if interval <= 0 {
+		interval = time.Second
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 10
+	}
+	return &Relay{repo: repo, publisher: publisher, interval: interval, batchSize: batchSize, maxAttempts: maxAttempts}
+}
+
+// Run publishes the pending events every interval until ctx is done.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		_, err := r.Flush(ctx)
+		// a pass cut short by the shutdown is not an error
+		if err != nil && ctx.Err() == nil {
+			logger.ErrorWithStack(ctx, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush publishes every pending event and returns how many were published. The
+// pass stops at an event claimed by another relay, it is ahead of this one.
+func (r *Relay) Flush(ctx context.Context) (published int, err error) {
+	for {
+		events, errs := r.repo.GetPending(ctx, r.batchSize)
+		if errs != nil {
+			if errors.Is(errs, ErrNotFound) {
+				return
+			}
+			return published, errs
+		}
+		for _, event := range *events {
+			now := Date.Now()
+			claimed, errs := r.repo.Claim(ctx, event.ID, now, now.Add(claimLease))
+			if errs != nil || !claimed {
+				return published, errs
+			}
+			err = r.publisher.Publish(ctx, *event.ToDto())
+			if err != nil {
+				if event.Attempts+1 < r.maxAttempts {
+					r.repo.MarkFailed(ctx, event.ID)
+					return
+				}
+				logger.ErrorWithStack(ctx, fmt.Errorf("event %s is dead after %d attempts: %w", event.ID,
+					event.Attempts+1, err))
+				err = r.repo.MarkDead(ctx, event.ID, Date.Now())
+				if err != nil {
+					return
+				}
+				continue
+			}
+			err = r.repo.MarkPublished(ctx, event.ID, Date.Now())
+			if err != nil {
+				return
+			}
+			published++
+		}
+		if len(*events) < r.batchSize {
+			return
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"github.com/magiconair/properties/assert"
+	"news/domain/entities"
+	"news/domain/outbox"
+	"testing"
+	"time"
+)
+
+func newEvents(t *testing.T, box outbox.Recorder, ids ...string) {
+	for _, id := range ids {
+		event, err := entities.NewEvent(entities.EventTagCreated, id, entities.TagDto{ID: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = box.Add(context.Background(), *event)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func aggregateIDs(events []entities.EventDto) (res []string) {
+	for _, event := range events {
+		res = append(res, event.AggregateID)
+	}
+	return
+}
+
+func TestRelay(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("publishes in order across batches", func(t *testing.T) {
+		box, publisher := outbox.NewMemoryRepository(), outbox.NewMemoryPublisher()
+		newEvents(t, box, "tag1", "tag2", "tag3")
+		relay := outbox.NewRelay(box, publisher, time.Second, 2, 3)
+
+		published, err := relay.Flush(ctx)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, published, 3)
+		assert.Equal(t, aggregateIDs(publisher.Events()), []string{"tag1", "tag2", "tag3"})
+
+		published, err = relay.Flush(ctx)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, published, 0)
+	})
+
+	t.Run("failed event is published again", func(t *testing.T) {
+		box, publisher := outbox.NewMemoryRepository(), outbox.NewMemoryPublisher()
+		newEvents(t, box, "tag1", "tag2")
+		relay := outbox.NewRelay(box, publisher, time.Second, 10, 3)
+
+		publisher.Err = errors.New("broker down")
+		published, err := relay.Flush(ctx)
+		assert.Equal(t, err, publisher.Err)
+		assert.Equal(t, published, 0)
+
+		pending, err := box.GetPending(ctx, 10)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(*pending), 2)
+		assert.Equal(t, (*pending)[0].Attempts, 1)
+		assert.Equal(t, (*pending)[1].Attempts, 0)
+
+		publisher.Err = nil
+		published, err = relay.Flush(ctx)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, published, 2)
+		assert.Equal(t, aggregateIDs(publisher.Events()), []string{"tag1", "tag2"})
+	})
+
+	t.Run("event failing every attempt is dead and skipped", func(t *testing.T) {
+		box, publisher := outbox.NewMemoryRepository(), outbox.NewMemoryPublisher()
+		newEvents(t, box, "tag1", "tag2")
+		relay := outbox.NewRelay(box, publisher, time.Second, 10, 2)
+
+		publisher.Err = errors.New("broker down")
+		_, err := relay.Flush(ctx)
+		assert.Equal(t, err, publisher.Err)
+		// the second attempt at tag1 is its last, tag2 fails its first
+		_, err = relay.Flush(ctx)
+		assert.Equal(t, err, publisher.Err)
+
+		pending, err := box.GetPending(ctx, 10)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(*pending), 1)
+		assert.Equal(t, (*pending)[0].AggregateID, "tag2")
+
+		publisher.Err = nil
+		published, err := relay.Flush(ctx)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, published, 1)
+		assert.Equal(t, aggregateIDs(publisher.Events()), []string{"tag2"})
+	})
+
+	t.Run("event claimed by another relay stops the pass", func(t *testing.T) {
+		box, publisher := outbox.NewMemoryRepository(), outbox.NewMemoryPublisher()
+		newEvents(t, box, "tag1", "tag2")
+		relay := outbox.NewRelay(box, publisher, time.Second, 10, 3)
+
+		pending, err := box.GetPending(ctx, 1)
+		assert.Equal(t, err, nil)
+		now := time.Now()
+		claimed, err := box.Claim(ctx, (*pending)[0].ID, now, now.Add(time.Hour))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, claimed, true)
+
+		published, err := relay.Flush(ctx)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, published, 0)
+		assert.Equal(t, len(publisher.Events()), 0)
+	})
+
+	t.Run("run stops with its context", func(t *testing.T) {
+		box, publisher := outbox.NewMemoryRepository(), outbox.NewMemoryPublisher()
+		newEvents(t, box, "tag1")
+		relay := outbox.NewRelay(box, publisher, time.Millisecond, 10, 3)
+
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			relay.Run(runCtx)
+			close(done)
+		}()
+		newEvents(t, box, "tag2")
+		deadline := time.Now().Add(time.Second)
+		for len(publisher.Events()) < 2 && time.Now().Before(deadline) {
+			time.Sleep(time.Millisecond)
+		}
+		cancel()
+		<-done
+		assert.Equal(t, aggregateIDs(publisher.Events()), []string{"tag1", "tag2"})
+	})
+}
@@ -0,0 +1,145 @@
+// Package outbox publishes the domain events the repositories write next to
+// their changes. Events are inserted in the transaction of the change and a
+// Relay publishes them afterwards, so an event is never lost once the change
+// is committed but may be published more than once.
+package outbox
+
+//go:generate go run github.com/golang/mock/mockgen -source repository.go -destination mock/repository_mock.go -package outbox_mock
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"net/http"
+	"news/domain/entities"
+	"news/shared/database"
+	"news/shared/failure"
+	"news/shared/logger"
+	"time"
+)
+
+// ErrNotFound is returned by GetPending when no event is pending.
+var ErrNotFound = failure.New(http.StatusNotFound, "outbox.event_not_found", "event not found")
+
+type Repository interface {
+	GetPending(ctx context.Context, limit int) (*entities.Events, error)
+	// Claim leases a pending event to the caller until until, it returns false
+	// when another relay holds a lease that isn't over at now.
+	Claim(ctx context.Context, id string, now time.Time, until time.Time) (bool, error)
+	MarkPublished(ctx context.Context, id string, publishedAt time.Time) error
+	// MarkFailed counts a failed attempt and releases the event, it is published again.
+	MarkFailed(ctx context.Context, id string) error
+	// MarkDead counts a failed attempt and takes the event out of the pending ones for good.
+	MarkDead(ctx context.Context, id string, failedAt time.Time) error
+	// Purge deletes the published and dead events written before before, it
+	// returns how many it deleted.
+	Purge(ctx context.Context, before time.Time) (int64, error)
+}
+
+// Recorder stores events written without a SQL transaction, by the memory repositories.
+type Recorder interface {
+	Add(ctx context.Context, events ...entities.Event) error
+}
+
+type repository struct {
+	DB *sqlx.DB
+}
+
+func NewRepository(DB *sqlx.DB) *repository {
+	return &repository{DB: DB}
+}
+
+// Insert writes events in tx, they are only visible to the relay once tx commits.
+func Insert(ctx context.Context, tx *sqlx.Tx, events entities.Events) (err error) {
+	if len(events) == 0 {
+		return
+	}
+	query := "INSERT INTO `outbox`(`id`, `type`, `aggregate_id`, `payload`, `attempts`, `createdAt`) " +
+		"VALUES (:id, :type, :aggregate_id, :payload, :attempts, :createdAt)"
+	stmt, err := tx.PrepareNamedContext(ctx, database.Rebind(tx, query))
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+	for _, event := range events {
+		_, err = stmt.ExecContext(ctx, event)
+		if err != nil {
+			logger.ErrorWithStack(ctx, err)
+			return failure.InternalServerError.Wrap(err)
+		}
+	}
+	return
+}
+
+// GetPending returns the oldest events neither published nor dead in the order
+// they were written, leased ones included.
+func (r *repository) GetPending(ctx context.Context, limit int) (events *entities.Events, err error) {
+	events = new(entities.Events)
+	query := "SELECT `sequence`, `id`, `type`, `aggregate_id`, `payload`, `attempts`, `lockedUntil`, `createdAt`, " +
+		"`publishedAt`, `failedAt` FROM `outbox` WHERE `publishedAt` IS NULL AND `failedAt` IS NULL " +
+		"ORDER BY `sequence` LIMIT ?"
+	err = r.DB.SelectContext(ctx, events, database.Rebind(r.DB, query), limit)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
+		return
+	}
+	if len(*events) < 1 {
+		err = ErrNotFound
+	}
+	return
+}
+
+// Claim is a conditional update, of the relays racing for an event only one changes the row.
+func (r *repository) Claim(ctx context.Context, id string, now time.Time, until time.Time) (bool, error) {
+	query := "UPDATE `outbox` SET `lockedUntil` = ? WHERE id = ? AND `publishedAt` IS NULL AND `failedAt` IS NULL " +
+		"AND (`lockedUntil` IS NULL OR `lockedUntil` <= ?)"
+	result, err := r.DB.ExecContext(ctx, database.Rebind(r.DB, query), until, id, now)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return false, failure.InternalServerError.Wrap(err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return false, failure.InternalServerError.Wrap(err)
+	}
+	return affected == 1, nil
+}
+
+func (r *repository) MarkPublished(ctx context.Context, id string, publishedAt time.Time) error {
+	return r.exec(ctx, "UPDATE `outbox` SET `publishedAt` = ?, `lockedUntil` = NULL WHERE id = ?", publishedAt, id)
+}
+
+func (r *repository) MarkFailed(ctx context.Context, id string) error {
+	return r.exec(ctx, "UPDATE `outbox` SET attempts = attempts + 1, `lockedUntil` = NULL WHERE id = ?", id)
+}
+
+func (r *repository) MarkDead(ctx context.Context, id string, failedAt time.Time) error {
+	return r.exec(ctx, "UPDATE `outbox` SET attempts = attempts + 1, `lockedUntil` = NULL, `failedAt` = ? WHERE id = ?",
+		failedAt, id)
+}
+
+// Purge never deletes pending events, however old they are.
+func (r *repository) Purge(ctx context.Context, before time.Time) (int64, error) {
+	query := "DELETE FROM `outbox` WHERE `createdAt` < ? AND (`publishedAt` IS NOT NULL OR `failedAt` IS NOT NULL)"
+	result, err := r.DB.ExecContext(ctx, database.Rebind(r.DB, query), before)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return 0, failure.InternalServerError.Wrap(err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return 0, failure.InternalServerError.Wrap(err)
+	}
+	return deleted, nil
+}
+
+func (r *repository) exec(ctx context.Context, query string, args ...interface{}) error {
+	_, err := r.DB.ExecContext(ctx, database.Rebind(r.DB, query), args...)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+	return nil
+}
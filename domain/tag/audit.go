@@ -0,0 +1,19 @@
+package tag
+
+import (
+	"context"
+	"news/domain/audit"
+	"news/domain/entities"
+)
+
+// auditEntry returns the audit log entry of storing tag over old by the actor
+// of ctx, old is nil for a new tag. The repositories write it with the tag.
+func auditEntry(ctx context.Context, old *entities.Tag, tag *entities.Tag) (*entities.AuditEntry, error) {
+	switch {
+	case old == nil:
+		return audit.NewEntry(ctx, entities.AuditCreate, entities.AuditTag, tag.ID, nil, tag.ToDto())
+	case tag.Status == entities.TagDelete && old.Status != entities.TagDelete:
+		return audit.NewEntry(ctx, entities.AuditDelete, entities.AuditTag, tag.ID, old.ToDto(), nil)
+	}
+	return audit.NewEntry(ctx, entities.AuditUpdate, entities.AuditTag, tag.ID, old.ToDto(), tag.ToDto())
+}
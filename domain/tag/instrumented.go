@@ -0,0 +1,53 @@
+package tag
+
+import (
+	"context"
+	"news/domain/entities"
+	"news/shared/metrics"
+	"time"
+)
+
+// instrumentedRepository observes the duration of every call of the wrapped
+// repository in the repository_query_duration_seconds metric.
+type instrumentedRepository struct {
+	repo Repository
+}
+
+func NewInstrumentedRepository(repo Repository) *instrumentedRepository {
+	return &instrumentedRepository{repo: repo}
+}
+
+func (r *instrumentedRepository) CreateTag(ctx context.Context, tag *entities.Tag) (result *entities.Tag, err error) {
+	defer metrics.ObserveQuery("tag", "CreateTag", time.Now())
+	return r.repo.CreateTag(ctx, tag)
+}
+
+func (r *instrumentedRepository) GetAllTag(ctx context.Context) (result *entities.Tags, err error) {
+	defer metrics.ObserveQuery("tag", "GetAllTag", time.Now())
+	return r.repo.GetAllTag(ctx)
+}
+
+func (r *instrumentedRepository) GetTagLike(ctx context.Context, like string) (result *entities.Tags, err error) {
+	defer metrics.ObserveQuery("tag", "GetTagLike", time.Now())
+	return r.repo.GetTagLike(ctx, like)
+}
+
+func (r *instrumentedRepository) GetTagByIds(ctx context.Context, id []string) (result *entities.Tags, err error) {
+	defer metrics.ObserveQuery("tag", "GetTagByIds", time.Now())
+	return r.repo.GetTagByIds(ctx, id)
+}
+
+func (r *instrumentedRepository) GetTagByNames(ctx context.Context, names []string) (result *entities.Tags, err error) {
+	defer metrics.ObserveQuery("tag", "GetTagByNames", time.Now())
+	return r.repo.GetTagByNames(ctx, names)
+}
+
+func (r *instrumentedRepository) UpdateTag(ctx context.Context, tag *entities.Tag) (result *entities.Tag, err error) {
+	defer metrics.ObserveQuery("tag", "UpdateTag", time.Now())
+	return r.repo.UpdateTag(ctx, tag)
+}
+
+func (r *instrumentedRepository) DeleteTag(ctx context.Context, id string) (err error) {
+	defer metrics.ObserveQuery("tag", "DeleteTag", time.Now())
+	return r.repo.DeleteTag(ctx, id)
+}
@@ -0,0 +1,153 @@
+package tag
+
+import (
+	"context"
+	"news/domain/audit"
+	"news/domain/entities"
+	"news/domain/outbox"
+	"news/shared/failure"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// memoryRepository keeps tags in a map, it behaves like the SQL repository and
+// passes the same contract.
+type memoryRepository struct {
+	mu   sync.RWMutex
+	tags map[string]entities.Tag
+	// Outbox receives the events of every change, they are dropped while it is nil.
+	Outbox outbox.Recorder
+	// Audit receives the audit log entry of every change, they are dropped while it is nil.
+	Audit audit.Recorder
+}
+
+func NewMemoryRepository() *memoryRepository {
+	return &memoryRepository{tags: map[string]entities.Tag{}}
+}
+
+func (r *memoryRepository) CreateTag(ctx context.Context, tag *entities.Tag) (result *entities.Tag, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, stored := range r.tags {
+		if id == tag.ID {
+			return nil, failure.InternalServerError
+		}
+		if stored.Name == tag.Name {
+			return nil, ErrNameTaken
+		}
+	}
+	r.tags[tag.ID] = *tag
+	return tag, r.record(ctx, nil, tag)
+}
+
+// record adds the events of storing tag over old to the outbox and its entry to
+// the audit log, old is nil for a new tag.
+func (r *memoryRepository) record(ctx context.Context, old *entities.Tag, tag *entities.Tag) error {
+	if r.Outbox != nil {
+		events, err := entities.TagEvents(old, tag)
+		if err != nil {
+			return failure.InternalServerError.Wrap(err)
+		}
+		err = r.Outbox.Add(ctx, events...)
+		if err != nil {
+			return err
+		}
+	}
+	if r.Audit == nil {
+		return nil
+	}
+	entry, err := auditEntry(ctx, old, tag)
+	if err != nil {
+		return err
+	}
+	return r.Audit.CreateEntry(ctx, entry)
+}
+
+func (r *memoryRepository) GetAllTag(ctx context.Context) (result *entities.Tags, err error) {
+	return r.selectTag(func(tag entities.Tag) bool {
+		return true
+	})
+}
+
+// GetTagLike matches case insensitively like the default MySQL collation.
+func (r *memoryRepository) GetTagLike(ctx context.Context, like string) (result *entities.Tags, err error) {
+	like = strings.ToLower(like)
+	return r.selectTag(func(tag entities.Tag) bool {
+		return strings.Contains(strings.ToLower(tag.Name), like) && tag.Status == entities.TagActive
+	})
+}
+
+func (r *memoryRepository) GetTagByIds(ctx context.Context, ids []string) (result *entities.Tags, err error) {
+	return r.selectTag(func(tag entities.Tag) bool {
+		return contains(ids, tag.ID) && tag.Status == entities.TagActive
+	})
+}
+
+func (r *memoryRepository) GetTagByNames(ctx context.Context, names []string) (result *entities.Tags, err error) {
+	return r.selectTag(func(tag entities.Tag) bool {
+		return contains(names, tag.Name)
+	})
+}
+
+func (r *memoryRepository) UpdateTag(ctx context.Context, tag *entities.Tag) (result *entities.Tag, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.tags[tag.ID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	if tag.Version != 0 && tag.Version != stored.Version {
+		return nil, ErrVersionConflict
+	}
+	for id, other := range r.tags {
+		if id != tag.ID && tag.Name != "" && other.Name == tag.Name {
+			return nil, ErrNameTaken
+		}
+	}
+	old := stored
+	stored.UpdateTag(tag)
+	r.tags[tag.ID] = stored
+	return &stored, r.record(ctx, &old, &stored)
+}
+
+func (r *memoryRepository) DeleteTag(ctx context.Context, id string) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored, ok := r.tags[id]
+	if !ok {
+		return ErrNotFound
+	}
+	old := stored
+	stored.Delete()
+	r.tags[id] = stored
+	return r.record(ctx, &old, &stored)
+}
+
+// selectTag returns the matching tags by name, or NotFound when none match.
+func (r *memoryRepository) selectTag(match func(tag entities.Tag) bool) (*entities.Tags, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tags := entities.Tags{}
+	for _, tag := range r.tags {
+		if match(tag) {
+			tags = append(tags, tag)
+		}
+	}
+	if len(tags) < 1 {
+		return nil, ErrNotFound
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		return tags[i].Name < tags[j].Name
+	})
+	return &tags, nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
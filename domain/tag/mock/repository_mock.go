@@ -94,6 +94,21 @@ func (mr *MockRepositoryMockRecorder) GetTagByIds(ctx, id interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagByIds", reflect.TypeOf((*MockRepository)(nil).GetTagByIds), ctx, id)
 }
 
+// GetTagByNames mocks base method.
+func (m *MockRepository) GetTagByNames(ctx context.Context, names []string) (*entities.Tags, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagByNames", ctx, names)
+	ret0, _ := ret[0].(*entities.Tags)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagByNames indicates an expected call of GetTagByNames.
+func (mr *MockRepositoryMockRecorder) GetTagByNames(ctx, names interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagByNames", reflect.TypeOf((*MockRepository)(nil).GetTagByNames), ctx, names)
+}
+
 // GetTagLike mocks base method.
 func (m *MockRepository) GetTagLike(ctx context.Context, like string) (*entities.Tags, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,21 @@
+package tag
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"news/domain/entities"
+)
+
+// postgresRepository shares the SQL of repository. Postgres compares text case
+// sensitively, so names are matched with ILIKE to behave like MySQL.
+type postgresRepository struct {
+	*repository
+}
+
+func NewPostgresRepository(DB *sqlx.DB) *postgresRepository {
+	return &postgresRepository{repository: NewRepository(DB)}
+}
+
+func (r *postgresRepository) GetTagLike(ctx context.Context, like string) (result *entities.Tags, err error) {
+	return r.selectTag(ctx, "WHERE name ILIKE ? and status = ?", "%"+like+"%", entities.TagActive)
+}
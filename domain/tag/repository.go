@@ -4,7 +4,11 @@ package tag
 import (
 	"context"
 	"github.com/jmoiron/sqlx"
+	"net/http"
+	"news/domain/audit"
 	"news/domain/entities"
+	"news/domain/outbox"
+	"news/shared/database"
 	"news/shared/failure"
 	"news/shared/logger"
 )
@@ -14,10 +18,21 @@ type Repository interface {
 	GetAllTag(ctx context.Context) (result *entities.Tags, err error)
 	GetTagLike(ctx context.Context, like string) (result *entities.Tags, err error)
 	GetTagByIds(ctx context.Context, id []string) (result *entities.Tags, err error)
+	GetTagByNames(ctx context.Context, names []string) (result *entities.Tags, err error)
 	UpdateTag(ctx context.Context, tag *entities.Tag) (result *entities.Tag, err error)
 	DeleteTag(ctx context.Context, id string) (err error)
 }
 
+var (
+	ErrNotFound = failure.New(http.StatusNotFound, "tag.not_found", "tag not found")
+	// ErrVersionConflict is returned by writes expecting a version of the tag
+	// that is not the stored one anymore.
+	ErrVersionConflict = failure.New(http.StatusConflict, "tag.version_conflict", "tag was changed by another write")
+	// ErrNameTaken is returned by writes of a tag with the name of another tag,
+	// deleted tags keep their name.
+	ErrNameTaken = failure.New(http.StatusConflict, "tag.name_taken", "a tag with this name already exists")
+)
+
 type repository struct {
 	DB *sqlx.DB
 }
@@ -27,17 +42,10 @@ func NewRepository(DB *sqlx.DB) *repository {
 }
 
 func (r *repository) CreateTag(ctx context.Context, tag *entities.Tag) (result *entities.Tag, err error) {
-	query := "INSERT INTO `tags`(`id`, `name`, `status`) VALUES (:id, :name, :status)"
-	stmt, err := r.DB.PrepareNamedContext(ctx, query)
+	query := "INSERT INTO `tags`(`id`, `name`, `status`, `version`, `createdAt`, `updatedAt`) " +
+		"VALUES (:id, :name, :status, :version, :createdAt, :updatedAt)"
+	err = r.write(ctx, nil, tag, query)
 	if err != nil {
-		logger.ErrorWithStack(err)
-		err = failure.InternalServerError
-		return
-	}
-	_, err = stmt.Exec(tag)
-	if err != nil {
-		logger.ErrorWithStack(err)
-		err = failure.InternalServerError
 		return
 	}
 	return tag, nil
@@ -48,14 +56,25 @@ func (r *repository) GetAllTag(ctx context.Context) (result *entities.Tags, err
 }
 
 func (r *repository) GetTagLike(ctx context.Context, like string) (result *entities.Tags, err error) {
-	return r.selectTag(ctx, "WHERE name LIKE '%?%' and status = ?", like, entities.TagActive)
+	return r.selectTag(ctx, "WHERE name LIKE ? and status = ?", "%"+like+"%", entities.TagActive)
 }
 
 func (r *repository) GetTagByIds(ctx context.Context, id []string) (result *entities.Tags, err error) {
 	query, args, err := sqlx.In("WHERE id IN (?) and status = ?", id, entities.TagActive)
 	if err != nil {
-		logger.ErrorWithStack(err)
-		err = failure.InternalServerError
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
+		return
+	}
+	return r.selectTag(ctx, query, args...)
+}
+
+// GetTagByNames also returns deleted tags, names stay unique after a tag is deleted.
+func (r *repository) GetTagByNames(ctx context.Context, names []string) (result *entities.Tags, err error) {
+	query, args, err := sqlx.In("WHERE name IN (?)", names)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
 		return
 	}
 	return r.selectTag(ctx, query, args...)
@@ -67,8 +86,12 @@ func (r *repository) UpdateTag(ctx context.Context, tag *entities.Tag) (result *
 		return
 	}
 	result = &(*oldTag)[0]
+	if tag.Version != 0 && tag.Version != result.Version {
+		return nil, ErrVersionConflict
+	}
+	old := *result
 	result.UpdateTag(tag)
-	err = r.updateTag(ctx, result)
+	err = r.write(ctx, &old, result, updateTagQuery)
 	return
 }
 
@@ -78,39 +101,85 @@ func (r *repository) DeleteTag(ctx context.Context, id string) (err error) {
 		return
 	}
 	deletedTag := &(*oldTag)[0]
+	old := *deletedTag
 	deletedTag.Delete()
-	err = r.updateTag(ctx, deletedTag)
+	err = r.write(ctx, &old, deletedTag, updateTagQuery)
 	return
 }
 
 func (r *repository) selectTag(ctx context.Context, where string, args ...interface{}) (tags *entities.Tags, err error) {
 	tags = new(entities.Tags)
-	query := "SELECT `id`, `name`, `status` FROM `tags` " + where
-	err = r.DB.SelectContext(ctx, tags, query, args...)
+	query := "SELECT `id`, `name`, `status`, `version`, `createdAt`, `updatedAt` FROM `tags` " + where
+	err = r.DB.SelectContext(ctx, tags, database.Rebind(r.DB, query), args...)
 	if err != nil {
-		logger.ErrorWithStack(err)
-		err = failure.InternalServerError
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
 		return
 	}
 	if len(*tags) < 1 {
-		err = failure.NotFound("tag not found")
+		err = ErrNotFound
 	}
 	return
 }
 
-func (r *repository) updateTag(ctx context.Context, tag *entities.Tag) (err error) {
-	query := "UPDATE `tags` SET name = :name, status = :status WHERE id = :id"
-	stmt, err := r.DB.PrepareNamedContext(ctx, query)
+// updateTagQuery writes a tag over the version before its own.
+const updateTagQuery = "UPDATE `tags` SET name = :name, status = :status, version = :version, " +
+	"`updatedAt` = :updatedAt WHERE id = :id AND version = :version - 1"
+
+// write runs the named query with tag and writes the outbox events and the audit
+// log entry of storing tag over old in the same transaction, old is nil for a new tag. A query that
+// writes no row means another write replaced the version tag expects.
+func (r *repository) write(ctx context.Context, old *entities.Tag, tag *entities.Tag, query string) (err error) {
+	events, err := entities.TagEvents(old, tag)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+	entry, err := auditEntry(ctx, old, tag)
+	if err != nil {
+		return
+	}
+	tx, err := r.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+	stmt, err := tx.PrepareNamed(database.Rebind(tx, query))
+	if err != nil {
+		tx.Rollback()
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+	result, err := stmt.Exec(tag)
+	if database.IsUniqueViolation(err) {
+		tx.Rollback()
+		return ErrNameTaken.Wrap(err)
+	}
+	if err != nil {
+		tx.Rollback()
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+	written, err := result.RowsAffected()
+	if err != nil {
+		tx.Rollback()
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+	if written == 0 {
+		tx.Rollback()
+		return ErrVersionConflict
+	}
+	err = outbox.Insert(ctx, tx, events)
 	if err != nil {
-		logger.ErrorWithStack(err)
-		err = failure.InternalServerError
+		tx.Rollback()
 		return
 	}
-	_, err = stmt.Exec(tag)
+	err = audit.Insert(ctx, tx, entry)
 	if err != nil {
-		logger.ErrorWithStack(err)
-		err = failure.InternalServerError
+		tx.Rollback()
 		return
 	}
+	tx.Commit()
 	return
 }
@@ -0,0 +1,78 @@
+package tag_test
+
+import (
+	"news/domain/audit"
+	"news/domain/outbox"
+	"news/domain/tag"
+	"news/domain/tag/tagtest"
+	"news/infras"
+	"news/infras/dbtest"
+	"testing"
+)
+
+func TestMemoryRepositoryContract(t *testing.T) {
+	tagtest.RepositoryContract(t, func(t *testing.T) tag.Repository {
+		return tag.NewMemoryRepository()
+	})
+}
+
+func TestRepositoryContract(t *testing.T) {
+	for _, driver := range dbtest.Drivers() {
+		driver := driver
+		t.Run(driver, func(t *testing.T) {
+			tagtest.RepositoryContract(t, func(t *testing.T) tag.Repository {
+				db := dbtest.Open(t, driver)
+				if driver == infras.DriverPostgres {
+					return tag.NewPostgresRepository(db)
+				}
+				return tag.NewRepository(db)
+			})
+		})
+	}
+}
+
+func TestMemoryRepositoryOutbox(t *testing.T) {
+	tagtest.OutboxContract(t, func(t *testing.T) (tag.Repository, outbox.Repository) {
+		repo, box := tag.NewMemoryRepository(), outbox.NewMemoryRepository()
+		repo.Outbox = box
+		return repo, box
+	})
+}
+
+func TestRepositoryOutbox(t *testing.T) {
+	for _, driver := range dbtest.Drivers() {
+		driver := driver
+		t.Run(driver, func(t *testing.T) {
+			tagtest.OutboxContract(t, func(t *testing.T) (tag.Repository, outbox.Repository) {
+				db := dbtest.Open(t, driver)
+				if driver == infras.DriverPostgres {
+					return tag.NewPostgresRepository(db), outbox.NewRepository(db)
+				}
+				return tag.NewRepository(db), outbox.NewRepository(db)
+			})
+		})
+	}
+}
+
+func TestMemoryRepositoryAudit(t *testing.T) {
+	tagtest.AuditContract(t, func(t *testing.T) (tag.Repository, audit.Repository) {
+		repo, log := tag.NewMemoryRepository(), audit.NewMemoryRepository()
+		repo.Audit = log
+		return repo, log
+	})
+}
+
+func TestRepositoryAudit(t *testing.T) {
+	for _, driver := range dbtest.Drivers() {
+		driver := driver
+		t.Run(driver, func(t *testing.T) {
+			tagtest.AuditContract(t, func(t *testing.T) (tag.Repository, audit.Repository) {
+				db := dbtest.Open(t, driver)
+				if driver == infras.DriverPostgres {
+					return tag.NewPostgresRepository(db), audit.NewRepository(db)
+				}
+				return tag.NewRepository(db), audit.NewRepository(db)
+			})
+		})
+	}
+}
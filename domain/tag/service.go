@@ -10,6 +10,7 @@ type Service interface {
 	Update(ctx context.Context, dto *entities.TagDto) (*entities.TagDto, error)
 	Delete(ctx context.Context, id string) error
 	GetAll(ctx context.Context) (*[]entities.TagDto, error)
+	GetByID(ctx context.Context, id string) (*entities.TagDto, error)
 	Search(ctx context.Context, name string) (*[]entities.TagDto, error)
 }
 
@@ -31,7 +32,12 @@ func (s service) Create(ctx context.Context, dto *entities.CreateTag) (result *e
 }
 
 func (s service) Update(ctx context.Context, dto *entities.TagDto) (result *entities.TagDto, err error) {
-	tag, err := s.repo.UpdateTag(ctx, dto.ToTag())
+	update := dto.ToTag()
+	update.Version = dto.Version
+	tag, err := s.repo.UpdateTag(ctx, update)
+	if err != nil {
+		return
+	}
 	result = tag.ToDto()
 	return
 }
@@ -49,6 +55,19 @@ func (s service) GetAll(ctx context.Context) (result *[]entities.TagDto, err err
 	return
 }
 
+func (s service) GetByID(ctx context.Context, id string) (result *entities.TagDto, err error) {
+	tags, err := s.repo.GetTagByIds(ctx, []string{id})
+	if err != nil {
+		return
+	}
+	if len(*tags) < 1 {
+		err = ErrNotFound
+		return
+	}
+	result = (*tags)[0].ToDto()
+	return
+}
+
 func (s service) Search(ctx context.Context, name string) (result *[]entities.TagDto, err error) {
 	tags, err := s.repo.GetTagLike(ctx, name)
 	if err != nil {
@@ -0,0 +1,221 @@
+// Package tagtest holds the behaviour every tag.Repository implementation must
+// have, backends run it from their own tests.
+package tagtest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/magiconair/properties/assert"
+	"news/domain/audit"
+	"news/domain/entities"
+	"news/domain/outbox"
+	"news/domain/tag"
+	"sort"
+	"testing"
+	"time"
+)
+
+var baseTime = time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+// newTag returns an active tag ready to be stored, created at baseTime.
+func newTag(id string, name string) *entities.Tag {
+	return &entities.Tag{ID: id, Name: name, Status: entities.TagActive, Version: 1, CreatedAt: baseTime,
+		UpdatedAt: baseTime}
+}
+
+func names(tags *entities.Tags) (res []string) {
+	for _, tag := range *tags {
+		res = append(res, tag.Name)
+	}
+	sort.Strings(res)
+	return
+}
+
+// RepositoryContract runs the suite, newRepo must return an empty repository on every call.
+func RepositoryContract(t *testing.T, newRepo func(t *testing.T) tag.Repository) {
+	ctx := context.Background()
+
+	create := func(t *testing.T, repo tag.Repository, tags ...string) {
+		for i, name := range tags {
+			_, err := repo.CreateTag(ctx, newTag("tag"+string(rune('1'+i)), name))
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	t.Run("create and get by ids", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, "football", "tennis", "golf")
+
+		actual, err := repo.GetTagByIds(ctx, []string{"tag1", "tag3"})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, names(actual), []string{"football", "golf"})
+	})
+
+	t.Run("get all", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, "football", "tennis")
+
+		actual, err := repo.GetAllTag(ctx)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, names(actual), []string{"football", "tennis"})
+	})
+
+	t.Run("duplicate name is taken", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, "football", "tennis")
+
+		_, err := repo.CreateTag(ctx, newTag("other", "football"))
+		assert.Equal(t, errors.Is(err, tag.ErrNameTaken), true)
+		_, err = repo.UpdateTag(ctx, &entities.Tag{ID: "tag2", Name: "football"})
+		assert.Equal(t, errors.Is(err, tag.ErrNameTaken), true)
+	})
+
+	t.Run("get like", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, "football", "basketball", "tennis", "ball games")
+		err := repo.DeleteTag(ctx, "tag4")
+		assert.Equal(t, err, nil)
+
+		actual, err := repo.GetTagLike(ctx, "ball")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, names(actual), []string{"basketball", "football"})
+
+		actual, err = repo.GetTagLike(ctx, "BALL")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, names(actual), []string{"basketball", "football"})
+
+		_, err = repo.GetTagLike(ctx, "golf")
+		assert.Equal(t, err, tag.ErrNotFound)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		repo := newRepo(t)
+
+		_, err := repo.GetAllTag(ctx)
+		assert.Equal(t, err, tag.ErrNotFound)
+		_, err = repo.GetTagByIds(ctx, []string{"tag1"})
+		assert.Equal(t, err, tag.ErrNotFound)
+		_, err = repo.GetTagByNames(ctx, []string{"football"})
+		assert.Equal(t, err, tag.ErrNotFound)
+		_, err = repo.UpdateTag(ctx, &entities.Tag{ID: "tag1", Name: "soccer"})
+		assert.Equal(t, err, tag.ErrNotFound)
+		err = repo.DeleteTag(ctx, "tag1")
+		assert.Equal(t, err, tag.ErrNotFound)
+	})
+
+	t.Run("update", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, "football")
+
+		actual, err := repo.UpdateTag(ctx, &entities.Tag{ID: "tag1", Name: "soccer"})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, actual.Name, "soccer")
+		assert.Equal(t, actual.Status, entities.TagActive)
+
+		tags, err := repo.GetTagByIds(ctx, []string{"tag1"})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, names(tags), []string{"soccer"})
+		assert.Equal(t, (*tags)[0].CreatedAt.Equal(baseTime), true)
+		assert.Equal(t, (*tags)[0].UpdatedAt.After(baseTime), true)
+	})
+
+	t.Run("update checks and increments the version", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, "football")
+
+		actual, err := repo.UpdateTag(ctx, &entities.Tag{ID: "tag1", Name: "soccer", Version: 1})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, actual.Version, 2)
+
+		_, err = repo.UpdateTag(ctx, &entities.Tag{ID: "tag1", Name: "futsal", Version: 1})
+		assert.Equal(t, err, tag.ErrVersionConflict)
+
+		tags, err := repo.GetTagByIds(ctx, []string{"tag1"})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, names(tags), []string{"soccer"})
+		assert.Equal(t, (*tags)[0].Version, 2)
+	})
+
+	t.Run("deleted tags are only found by name", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, "football", "tennis")
+
+		err := repo.DeleteTag(ctx, "tag2")
+		assert.Equal(t, err, nil)
+
+		_, err = repo.GetTagByIds(ctx, []string{"tag2"})
+		assert.Equal(t, err, tag.ErrNotFound)
+
+		actual, err := repo.GetTagByNames(ctx, []string{"tennis"})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, (*actual)[0].Status, entities.TagDelete)
+	})
+}
+
+// OutboxContract checks the events a repository writes to its outbox, newRepo must
+// return an empty repository and the outbox it writes to on every call.
+func OutboxContract(t *testing.T, newRepo func(t *testing.T) (tag.Repository, outbox.Repository)) {
+	ctx := context.Background()
+
+	repo, box := newRepo(t)
+	_, err := repo.CreateTag(ctx, newTag("tag1", "football"))
+	assert.Equal(t, err, nil)
+	_, err = repo.CreateTag(ctx, newTag("tag2", "football"))
+	assert.Equal(t, err != nil, true)
+	_, err = repo.UpdateTag(ctx, &entities.Tag{ID: "tag1", Name: "football"})
+	assert.Equal(t, err, nil)
+	_, err = repo.UpdateTag(ctx, &entities.Tag{ID: "tag1", Name: "soccer"})
+	assert.Equal(t, err, nil)
+	err = repo.DeleteTag(ctx, "tag1")
+	assert.Equal(t, err, nil)
+
+	events, err := box.GetPending(ctx, 100)
+	assert.Equal(t, err, nil)
+	var types []string
+	for _, event := range *events {
+		assert.Equal(t, event.AggregateID, "tag1")
+		types = append(types, string(event.Type))
+	}
+	assert.Equal(t, types, []string{"TagCreated", "TagRenamed", "TagDeleted"})
+
+	var payload entities.TagRenamedPayload
+	err = json.Unmarshal([]byte((*events)[1].Payload), &payload)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, payload.Name, "soccer")
+	assert.Equal(t, payload.PreviousName, "football")
+}
+
+// AuditContract checks the audit log entries a repository writes with its changes,
+// newRepo must return an empty repository and the audit log it writes to.
+func AuditContract(t *testing.T, newRepo func(t *testing.T) (tag.Repository, audit.Repository)) {
+	ctx := audit.NewContext(context.Background(), audit.Metadata{Actor: "budi", IP: "10.0.0.1", RequestID: "request1"})
+
+	repo, log := newRepo(t)
+	_, err := repo.CreateTag(ctx, newTag("tag1", "football"))
+	assert.Equal(t, err, nil)
+	_, err = repo.CreateTag(ctx, newTag("tag2", "football"))
+	assert.Equal(t, err != nil, true)
+	_, err = repo.UpdateTag(ctx, &entities.Tag{ID: "tag1", Name: "soccer"})
+	assert.Equal(t, err, nil)
+	err = repo.DeleteTag(ctx, "tag1")
+	assert.Equal(t, err, nil)
+
+	entries, err := log.GetEntries(ctx, &entities.AuditFilter{EntityType: entities.AuditTag, Limit: 100})
+	assert.Equal(t, err, nil)
+	actions := map[string]entities.AuditEntry{}
+	for _, entry := range *entries {
+		assert.Equal(t, entry.Actor, "budi")
+		assert.Equal(t, entry.EntityID, "tag1")
+		actions[entry.Action] = entry
+	}
+	assert.Equal(t, len(actions), 3)
+	var before, after entities.TagDto
+	assert.Equal(t, json.Unmarshal([]byte(*actions[entities.AuditUpdate].Before), &before), nil)
+	assert.Equal(t, json.Unmarshal([]byte(*actions[entities.AuditUpdate].After), &after), nil)
+	assert.Equal(t, before.Name, "football")
+	assert.Equal(t, after.Name, "soccer")
+	assert.Equal(t, actions[entities.AuditDelete].After == nil, true)
+}
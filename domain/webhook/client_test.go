@@ -0,0 +1,38 @@
+package webhook_test
+
+import (
+	"errors"
+	"github.com/magiconair/properties/assert"
+	"net/http"
+	"net/http/httptest"
+	"news/domain/webhook"
+	"testing"
+	"time"
+)
+
+func TestClient(t *testing.T) {
+	t.Run("refuses addresses that aren't public", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {}))
+		t.Cleanup(server.Close)
+		urls := []string{server.URL, "http://10.0.0.1", "http://172.16.0.1", "http://192.168.1.1",
+			"http://169.254.169.254/latest/meta-data", "http://0.0.0.0", "http://100.64.0.1", "http://198.18.0.1",
+			"http://[::1]", "http://[fe80::1]", "http://[fd00::1]"}
+		client := webhook.NewClient(time.Second)
+		for _, url := range urls {
+			_, err := client.Get(url)
+			assert.Equal(t, errors.Is(err, webhook.ErrAddressNotAllowed), true, url)
+		}
+	})
+
+	t.Run("doesn't follow redirects", func(t *testing.T) {
+		server := httptest.NewServer(http.RedirectHandler("http://169.254.169.254", http.StatusFound))
+		t.Cleanup(server.Close)
+		client := webhook.NewClient(time.Second)
+		// the test server is on the loopback
+		client.Transport = server.Client().Transport
+		res, err := client.Get(server.URL)
+		assert.Equal(t, err, nil)
+		res.Body.Close()
+		assert.Equal(t, res.StatusCode, http.StatusFound)
+	})
+}
@@ -0,0 +1,182 @@
+package webhook
+
+//go:generate go run github.com/golang/mock/mockgen -source dispatcher.go -destination mock/dispatcher_mock.go -package webhook_mock
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"news/domain/entities"
+	"news/shared/Date"
+	"news/shared/failure"
+	"news/shared/logger"
+	"strconv"
+	"time"
+)
+
+const (
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Sign returns the signature header of a body sent at timestamp, receivers
+// compute the HMAC-SHA256 of "<timestamp>.<body>" with their secret and compare.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10) + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+type Sender interface {
+	Send(ctx context.Context, delivery *entities.WebhookDelivery) error
+}
+
+// Dispatcher sends the pending deliveries. A delivery succeeds on any 2xx
+// response, anything else is retried with exponential backoff. Every attempt
+// claims its delivery first, so dispatchers on several instances and
+// redeliveries never send it twice at once.
+type Dispatcher struct {
+	repo        Repository
+	client      *http.Client
+	maxAttempts int
+	backoff     time.Duration
+	interval    time.Duration
+	batchSize   int
+	lease       time.Duration
+}
+
+func NewDispatcher(repo Repository, client *http.Client, maxAttempts int, backoff time.Duration,
+	interval time.Duration) *Dispatcher {
+	if maxAttempts <= 0 {
+		maxAttempts = 8
+	}
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+	if interval <= 0 {
+		interval = time.Second
+	}
+	// an attempt holds its delivery at most as long as the request, and a minute to store its outcome
+	return &Dispatcher{repo: repo, client: client, maxAttempts: maxAttempts, backoff: backoff, interval: interval,
+		batchSize: 100, lease: time.Minute + client.Timeout}
+}
+
+// Run sends the due deliveries every interval until ctx is done.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		_, err := d.Flush(ctx)
+		// a pass cut short by the shutdown is not an error
+		if err != nil && ctx.Err() == nil {
+			logger.ErrorWithStack(ctx, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush makes one attempt at every due delivery and returns how many were attempted.
+func (d *Dispatcher) Flush(ctx context.Context) (attempted int, err error) {
+	deliveries, err := d.repo.GetDueDeliveries(ctx, Date.Now(), d.batchSize)
+	if err != nil {
+		if failure.GetStatus(err) == http.StatusNotFound {
+			return 0, nil
+		}
+		return
+	}
+	for i := range *deliveries {
+		delivery := &(*deliveries)[i]
+		var claimed bool
+		now := Date.Now()
+		claimed, err = d.repo.ClaimDueDelivery(ctx, delivery.ID, now, now.Add(d.lease))
+		if err != nil {
+			return
+		}
+		// another dispatcher or a redelivery is sending it
+		if !claimed {
+			continue
+		}
+		err = d.attempt(ctx, delivery)
+		if err != nil {
+			return
+		}
+		attempted++
+	}
+	return
+}
+
+// Send makes one attempt at the delivery and stores its outcome in it, the
+// error is about claiming the delivery or storing the outcome.
+func (d *Dispatcher) Send(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	now := Date.Now()
+	claimed, err := d.repo.ClaimDelivery(ctx, delivery.ID, now, now.Add(d.lease))
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return ErrDeliveryInFlight
+	}
+	// the attempt before the claim may have changed it
+	stored, err := d.repo.GetDeliveryByID(ctx, delivery.ID)
+	if err != nil {
+		return err
+	}
+	*delivery = *stored
+	return d.attempt(ctx, delivery)
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	webhook, err := d.repo.GetWebhookByID(ctx, delivery.WebhookID)
+	if err != nil {
+		if failure.GetStatus(err) != http.StatusNotFound {
+			return err
+		}
+		delivery.Fail(0, "webhook deleted", 0, d.backoff)
+		return d.repo.UpdateDelivery(ctx, delivery)
+	}
+
+	code, err := d.post(ctx, webhook, delivery)
+	switch {
+	case err != nil:
+		delivery.Fail(code, err.Error(), d.maxAttempts, d.backoff)
+	case code < 200 || code > 299:
+		delivery.Fail(code, fmt.Sprintf("unexpected status %d", code), d.maxAttempts, d.backoff)
+	default:
+		delivery.Succeed(code)
+	}
+	return d.repo.UpdateDelivery(ctx, delivery)
+}
+
+func (d *Dispatcher) post(ctx context.Context, webhook *entities.Webhook, delivery *entities.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := Date.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderDelivery, delivery.ID)
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(webhook.Secret, timestamp, body))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(res.Body, 64<<10))
+	return res.StatusCode, nil
+}
@@ -0,0 +1,228 @@
+package webhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/magiconair/properties/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"news/domain/entities"
+	"news/domain/webhook"
+	"news/shared/Date"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+// receiver is a partner endpoint answering with the next status of statuses,
+// 200 once they run out, and checking the signature of every request.
+type receiver struct {
+	mu       sync.Mutex
+	statuses []int
+	events   []entities.EventDto
+	invalid  int
+}
+
+func newReceiver(t *testing.T, secret string, statuses ...int) (*receiver, *httptest.Server) {
+	r := &receiver{statuses: statuses}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		body, _ := io.ReadAll(req.Body)
+		timestamp, _ := strconv.ParseInt(req.Header.Get(webhook.HeaderTimestamp), 10, 64)
+		if req.Header.Get(webhook.HeaderSignature) != webhook.Sign(secret, timestamp, body) {
+			r.invalid++
+		}
+		var event entities.EventDto
+		json.Unmarshal(body, &event)
+		r.events = append(r.events, event)
+		status := http.StatusOK
+		if len(r.statuses) > 0 {
+			status, r.statuses = r.statuses[0], r.statuses[1:]
+		}
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return r, server
+}
+
+func (r *receiver) received() (events []entities.EventDto, invalid int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append(events, r.events...), r.invalid
+}
+
+func newEvent(id string, eventType entities.EventType) entities.EventDto {
+	return entities.EventDto{ID: id, Type: string(eventType), AggregateID: "news1", Payload: json.RawMessage(`{"id":"news1"}`),
+		OccurredAt: Date.Now()}
+}
+
+func TestSign(t *testing.T) {
+	assert.Equal(t, webhook.Sign("secret", 1651399200, []byte(`{"id":"1"}`)),
+		"sha256=be6b531ceb86da0804bdba8fdf657ea88902f3ba2e6cd64dec72d9069b958eb3")
+}
+
+func TestDispatcher(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	Date.Now = func() time.Time {
+		return now
+	}
+	defer func() {
+		Date.Now = time.Now
+	}()
+
+	setup := func(t *testing.T, eventTypes []string, statuses ...int) (webhook.Repository, *receiver, *webhook.Dispatcher) {
+		repo := webhook.NewMemoryRepository()
+		r, server := newReceiver(t, "secret", statuses...)
+		err := repo.CreateWebhook(ctx, entities.NewWebhook(server.URL, eventTypes, "secret"))
+		assert.Equal(t, err, nil)
+		return repo, r, webhook.NewDispatcher(repo, server.Client(), 3, time.Second, time.Second)
+	}
+
+	t.Run("delivers signed events the webhook subscribed to", func(t *testing.T) {
+		repo, r, dispatcher := setup(t, []string{"NewsPublished"})
+		publisher := webhook.NewPublisher(repo)
+		assert.Equal(t, publisher.Publish(ctx, newEvent("event1", entities.EventNewsCreated)), nil)
+		assert.Equal(t, publisher.Publish(ctx, newEvent("event2", entities.EventNewsPublished)), nil)
+		// the relay publishes an event again after a failure
+		assert.Equal(t, publisher.Publish(ctx, newEvent("event2", entities.EventNewsPublished)), nil)
+
+		attempted, err := dispatcher.Flush(ctx)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, attempted, 1)
+		events, invalid := r.received()
+		assert.Equal(t, invalid, 0)
+		assert.Equal(t, len(events), 1)
+		assert.Equal(t, events[0].ID, "event2")
+		assert.Equal(t, events[0].AggregateID, "news1")
+
+		webhooks, _ := repo.GetWebhooks(ctx)
+		deliveries, err := repo.GetDeliveriesByWebhookID(ctx, (*webhooks)[0].ID)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, (*deliveries)[0].Status, entities.DeliverySucceeded)
+		assert.Equal(t, (*deliveries)[0].ResponseCode, http.StatusOK)
+	})
+
+	t.Run("retries with exponential backoff", func(t *testing.T) {
+		repo, r, dispatcher := setup(t, nil, http.StatusInternalServerError, http.StatusBadGateway)
+		assert.Equal(t, webhook.NewPublisher(repo).Publish(ctx, newEvent("event1", entities.EventNewsCreated)), nil)
+
+		_, err := dispatcher.Flush(ctx)
+		assert.Equal(t, err, nil)
+		webhooks, _ := repo.GetWebhooks(ctx)
+		deliveries, _ := repo.GetDeliveriesByWebhookID(ctx, (*webhooks)[0].ID)
+		delivery := (*deliveries)[0]
+		assert.Equal(t, delivery.Status, entities.DeliveryPending)
+		assert.Equal(t, delivery.Attempts, 1)
+		assert.Equal(t, delivery.LastError, "unexpected status 500")
+		assert.Equal(t, *delivery.NextAttemptAt, now.Add(time.Second))
+
+		// not due yet
+		attempted, _ := dispatcher.Flush(ctx)
+		assert.Equal(t, attempted, 0)
+
+		now = now.Add(time.Second)
+		attempted, _ = dispatcher.Flush(ctx)
+		assert.Equal(t, attempted, 1)
+		updated, _ := repo.GetDeliveryByID(ctx, delivery.ID)
+		assert.Equal(t, updated.Attempts, 2)
+		assert.Equal(t, *updated.NextAttemptAt, now.Add(2*time.Second))
+
+		now = now.Add(2 * time.Second)
+		attempted, _ = dispatcher.Flush(ctx)
+		assert.Equal(t, attempted, 1)
+		updated, _ = repo.GetDeliveryByID(ctx, delivery.ID)
+		assert.Equal(t, updated.Status, entities.DeliverySucceeded)
+		assert.Equal(t, updated.Attempts, 3)
+		assert.Equal(t, *updated.DeliveredAt, now)
+		events, _ := r.received()
+		assert.Equal(t, len(events), 3)
+	})
+
+	t.Run("fails after the last attempt", func(t *testing.T) {
+		repo, _, dispatcher := setup(t, nil, http.StatusInternalServerError, http.StatusInternalServerError,
+			http.StatusInternalServerError)
+		assert.Equal(t, webhook.NewPublisher(repo).Publish(ctx, newEvent("event1", entities.EventNewsCreated)), nil)
+
+		for i := 0; i < 3; i++ {
+			dispatcher.Flush(ctx)
+			now = now.Add(time.Minute)
+		}
+		webhooks, _ := repo.GetWebhooks(ctx)
+		deliveries, _ := repo.GetDeliveriesByWebhookID(ctx, (*webhooks)[0].ID)
+		assert.Equal(t, (*deliveries)[0].Status, entities.DeliveryFailed)
+		assert.Equal(t, (*deliveries)[0].Attempts, 3)
+		assert.Equal(t, (*deliveries)[0].NextAttemptAt == nil, true)
+	})
+
+	t.Run("deleted webhook fails its deliveries", func(t *testing.T) {
+		repo, r, dispatcher := setup(t, nil)
+		assert.Equal(t, webhook.NewPublisher(repo).Publish(ctx, newEvent("event1", entities.EventNewsCreated)), nil)
+		webhooks, _ := repo.GetWebhooks(ctx)
+		assert.Equal(t, repo.DeleteWebhook(ctx, (*webhooks)[0].ID), nil)
+
+		_, err := dispatcher.Flush(ctx)
+		assert.Equal(t, err, nil)
+		deliveries, _ := repo.GetDeliveriesByWebhookID(ctx, (*webhooks)[0].ID)
+		assert.Equal(t, (*deliveries)[0].Status, entities.DeliveryFailed)
+		assert.Equal(t, (*deliveries)[0].LastError, "webhook deleted")
+		events, _ := r.received()
+		assert.Equal(t, len(events), 0)
+	})
+
+	t.Run("skips deliveries another attempt is sending", func(t *testing.T) {
+		repo, r, dispatcher := setup(t, nil)
+		assert.Equal(t, webhook.NewPublisher(repo).Publish(ctx, newEvent("event1", entities.EventNewsCreated)), nil)
+		webhooks, _ := repo.GetWebhooks(ctx)
+		deliveries, _ := repo.GetDeliveriesByWebhookID(ctx, (*webhooks)[0].ID)
+		delivery := (*deliveries)[0]
+		claimed, err := repo.ClaimDelivery(ctx, delivery.ID, now, now.Add(time.Minute))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, claimed, true)
+
+		attempted, err := dispatcher.Flush(ctx)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, attempted, 0)
+		assert.Equal(t, dispatcher.Send(ctx, &delivery), webhook.ErrDeliveryInFlight)
+		events, _ := r.received()
+		assert.Equal(t, len(events), 0)
+
+		// the lease of an attempt that never stored its outcome runs out
+		now = now.Add(time.Minute)
+		attempted, err = dispatcher.Flush(ctx)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, attempted, 1)
+		updated, _ := repo.GetDeliveryByID(ctx, delivery.ID)
+		assert.Equal(t, updated.Status, entities.DeliverySucceeded)
+		assert.Equal(t, updated.LockedUntil == nil, true)
+	})
+}
+
+func TestDeliveryBackoff(t *testing.T) {
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	Date.Now = func() time.Time {
+		return now
+	}
+	defer func() {
+		Date.Now = time.Now
+	}()
+
+	sliceTest := []struct {
+		attempts int
+		next     time.Duration
+	}{
+		{attempts: 0, next: time.Second},
+		{attempts: 3, next: 8 * time.Second},
+		{attempts: 20, next: 24 * time.Hour},
+		// doubling that many times would overflow
+		{attempts: 70, next: 24 * time.Hour},
+	}
+	for _, test := range sliceTest {
+		delivery := entities.WebhookDelivery{Status: entities.DeliveryPending, Attempts: test.attempts}
+		delivery.Fail(http.StatusInternalServerError, "unexpected status 500", 100, time.Second)
+		assert.Equal(t, *delivery.NextAttemptAt, now.Add(test.next), strconv.Itoa(test.attempts))
+	}
+}
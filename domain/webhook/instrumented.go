@@ -0,0 +1,83 @@
+package webhook
+
+import (
+	"context"
+	"news/domain/entities"
+	"news/shared/metrics"
+	"time"
+)
+
+// instrumentedRepository observes the duration of every call of the wrapped
+// repository in the repository_query_duration_seconds metric.
+type instrumentedRepository struct {
+	repo Repository
+}
+
+func NewInstrumentedRepository(repo Repository) *instrumentedRepository {
+	return &instrumentedRepository{repo: repo}
+}
+
+func (r *instrumentedRepository) CreateWebhook(ctx context.Context, webhook *entities.Webhook) error {
+	defer metrics.ObserveQuery("webhook", "CreateWebhook", time.Now())
+	return r.repo.CreateWebhook(ctx, webhook)
+}
+
+func (r *instrumentedRepository) GetWebhookByID(ctx context.Context, id string) (*entities.Webhook, error) {
+	defer metrics.ObserveQuery("webhook", "GetWebhookByID", time.Now())
+	return r.repo.GetWebhookByID(ctx, id)
+}
+
+func (r *instrumentedRepository) GetWebhooks(ctx context.Context) (*entities.Webhooks, error) {
+	defer metrics.ObserveQuery("webhook", "GetWebhooks", time.Now())
+	return r.repo.GetWebhooks(ctx)
+}
+
+func (r *instrumentedRepository) DeleteWebhook(ctx context.Context, id string) error {
+	defer metrics.ObserveQuery("webhook", "DeleteWebhook", time.Now())
+	return r.repo.DeleteWebhook(ctx, id)
+}
+
+func (r *instrumentedRepository) CreateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	defer metrics.ObserveQuery("webhook", "CreateDelivery", time.Now())
+	return r.repo.CreateDelivery(ctx, delivery)
+}
+
+func (r *instrumentedRepository) GetDeliveryByID(ctx context.Context, id string) (*entities.WebhookDelivery, error) {
+	defer metrics.ObserveQuery("webhook", "GetDeliveryByID", time.Now())
+	return r.repo.GetDeliveryByID(ctx, id)
+}
+
+func (r *instrumentedRepository) GetDeliveriesByWebhookID(ctx context.Context, webhookID string) (*entities.WebhookDeliveries, error) {
+	defer metrics.ObserveQuery("webhook", "GetDeliveriesByWebhookID", time.Now())
+	return r.repo.GetDeliveriesByWebhookID(ctx, webhookID)
+}
+
+func (r *instrumentedRepository) GetDeliveriesByEventID(ctx context.Context, eventID string) (*entities.WebhookDeliveries, error) {
+	defer metrics.ObserveQuery("webhook", "GetDeliveriesByEventID", time.Now())
+	return r.repo.GetDeliveriesByEventID(ctx, eventID)
+}
+
+func (r *instrumentedRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) (*entities.WebhookDeliveries, error) {
+	defer metrics.ObserveQuery("webhook", "GetDueDeliveries", time.Now())
+	return r.repo.GetDueDeliveries(ctx, now, limit)
+}
+
+func (r *instrumentedRepository) ClaimDelivery(ctx context.Context, id string, now time.Time, until time.Time) (bool, error) {
+	defer metrics.ObserveQuery("webhook", "ClaimDelivery", time.Now())
+	return r.repo.ClaimDelivery(ctx, id, now, until)
+}
+
+func (r *instrumentedRepository) ClaimDueDelivery(ctx context.Context, id string, now time.Time, until time.Time) (bool, error) {
+	defer metrics.ObserveQuery("webhook", "ClaimDueDelivery", time.Now())
+	return r.repo.ClaimDueDelivery(ctx, id, now, until)
+}
+
+func (r *instrumentedRepository) UpdateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	defer metrics.ObserveQuery("webhook", "UpdateDelivery", time.Now())
+	return r.repo.UpdateDelivery(ctx, delivery)
+}
+
+func (r *instrumentedRepository) PurgeDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	defer metrics.ObserveQuery("webhook", "PurgeDeliveries", time.Now())
+	return r.repo.PurgeDeliveries(ctx, before)
+}
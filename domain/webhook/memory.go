@@ -0,0 +1,218 @@
+package webhook
+
+import (
+	"context"
+	"news/domain/entities"
+	"news/shared/failure"
+	"sort"
+	"sync"
+	"time"
+)
+
+// memoryRepository keeps webhooks and deliveries in maps, it behaves like the SQL repository.
+type memoryRepository struct {
+	mu         sync.RWMutex
+	webhooks   map[string]entities.Webhook
+	deliveries map[string]entities.WebhookDelivery
+}
+
+func NewMemoryRepository() *memoryRepository {
+	return &memoryRepository{webhooks: map[string]entities.Webhook{}, deliveries: map[string]entities.WebhookDelivery{}}
+}
+
+func (r *memoryRepository) CreateWebhook(ctx context.Context, webhook *entities.Webhook) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.webhooks[webhook.ID]; ok {
+		return failure.InternalServerError
+	}
+	r.webhooks[webhook.ID] = *webhook
+	return nil
+}
+
+func (r *memoryRepository) GetWebhookByID(ctx context.Context, id string) (webhook *entities.Webhook, err error) {
+	webhooks, err := r.selectWebhook(func(webhook entities.Webhook) bool {
+		return webhook.ID == id
+	})
+	if err != nil {
+		return
+	}
+	return &(*webhooks)[0], nil
+}
+
+func (r *memoryRepository) GetWebhooks(ctx context.Context) (*entities.Webhooks, error) {
+	return r.selectWebhook(func(webhook entities.Webhook) bool {
+		return true
+	})
+}
+
+func (r *memoryRepository) DeleteWebhook(ctx context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	webhook, ok := r.webhooks[id]
+	if !ok || webhook.Status != entities.WebhookActive {
+		return ErrNotFound
+	}
+	webhook.Delete()
+	r.webhooks[id] = webhook
+	return nil
+}
+
+// selectWebhook returns the matching active webhooks oldest first, or NotFound when none match.
+func (r *memoryRepository) selectWebhook(match func(webhook entities.Webhook) bool) (*entities.Webhooks, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	webhooks := entities.Webhooks{}
+	for _, webhook := range r.webhooks {
+		if webhook.Status == entities.WebhookActive && match(webhook) {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	if len(webhooks) < 1 {
+		return nil, ErrNotFound
+	}
+	sort.Slice(webhooks, func(i, j int) bool {
+		if webhooks[i].CreatedAt.Equal(webhooks[j].CreatedAt) {
+			return webhooks[i].ID < webhooks[j].ID
+		}
+		return webhooks[i].CreatedAt.Before(webhooks[j].CreatedAt)
+	})
+	return &webhooks, nil
+}
+
+func (r *memoryRepository) CreateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id, stored := range r.deliveries {
+		if id == delivery.ID || (stored.WebhookID == delivery.WebhookID && stored.EventID == delivery.EventID) {
+			return failure.InternalServerError
+		}
+	}
+	r.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func (r *memoryRepository) GetDeliveryByID(ctx context.Context, id string) (delivery *entities.WebhookDelivery, err error) {
+	deliveries, err := r.selectDelivery(func(delivery entities.WebhookDelivery) bool {
+		return delivery.ID == id
+	}, nil)
+	if err != nil {
+		return
+	}
+	return &(*deliveries)[0], nil
+}
+
+func (r *memoryRepository) GetDeliveriesByWebhookID(ctx context.Context, webhookID string) (*entities.WebhookDeliveries, error) {
+	return r.selectDelivery(func(delivery entities.WebhookDelivery) bool {
+		return delivery.WebhookID == webhookID
+	}, func(a, b entities.WebhookDelivery) bool {
+		if a.CreatedAt.Equal(b.CreatedAt) {
+			return a.ID < b.ID
+		}
+		return a.CreatedAt.After(b.CreatedAt)
+	})
+}
+
+func (r *memoryRepository) GetDeliveriesByEventID(ctx context.Context, eventID string) (*entities.WebhookDeliveries, error) {
+	return r.selectDelivery(func(delivery entities.WebhookDelivery) bool {
+		return delivery.EventID == eventID
+	}, nil)
+}
+
+func (r *memoryRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) (*entities.WebhookDeliveries, error) {
+	deliveries, err := r.selectDelivery(func(delivery entities.WebhookDelivery) bool {
+		return due(delivery, now) && !inFlight(delivery, now)
+	}, func(a, b entities.WebhookDelivery) bool {
+		if a.NextAttemptAt.Equal(*b.NextAttemptAt) {
+			return a.ID < b.ID
+		}
+		return a.NextAttemptAt.Before(*b.NextAttemptAt)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(*deliveries) > limit {
+		*deliveries = (*deliveries)[:limit]
+	}
+	return deliveries, nil
+}
+
+func (r *memoryRepository) ClaimDelivery(ctx context.Context, id string, now time.Time, until time.Time) (bool, error) {
+	return r.claim(id, now, until, func(delivery entities.WebhookDelivery) bool {
+		return true
+	})
+}
+
+func (r *memoryRepository) ClaimDueDelivery(ctx context.Context, id string, now time.Time, until time.Time) (bool, error) {
+	return r.claim(id, now, until, func(delivery entities.WebhookDelivery) bool {
+		return due(delivery, now)
+	})
+}
+
+func (r *memoryRepository) claim(id string, now time.Time, until time.Time,
+	match func(delivery entities.WebhookDelivery) bool) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delivery, ok := r.deliveries[id]
+	if !ok || !match(delivery) || inFlight(delivery, now) {
+		return false, nil
+	}
+	delivery.LockedUntil = &until
+	r.deliveries[id] = delivery
+	return true, nil
+}
+
+func due(delivery entities.WebhookDelivery, now time.Time) bool {
+	return delivery.Status == entities.DeliveryPending && delivery.NextAttemptAt != nil && !delivery.NextAttemptAt.After(now)
+}
+
+func inFlight(delivery entities.WebhookDelivery, now time.Time) bool {
+	return delivery.LockedUntil != nil && delivery.LockedUntil.After(now)
+}
+
+func (r *memoryRepository) UpdateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.deliveries[delivery.ID]; !ok {
+		return nil
+	}
+	updated := *delivery
+	updated.LockedUntil = nil
+	r.deliveries[delivery.ID] = updated
+	return nil
+}
+
+func (r *memoryRepository) PurgeDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var deleted int64
+	for id, delivery := range r.deliveries {
+		if delivery.CreatedAt.Before(before) && delivery.Status != entities.DeliveryPending {
+			delete(r.deliveries, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+// selectDelivery returns the matching deliveries sorted by less, or NotFound when none match.
+func (r *memoryRepository) selectDelivery(match func(delivery entities.WebhookDelivery) bool,
+	less func(a, b entities.WebhookDelivery) bool) (*entities.WebhookDeliveries, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	deliveries := entities.WebhookDeliveries{}
+	for _, delivery := range r.deliveries {
+		if match(delivery) {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	if len(deliveries) < 1 {
+		return nil, ErrDeliveryNotFound
+	}
+	if less != nil {
+		sort.Slice(deliveries, func(i, j int) bool {
+			return less(deliveries[i], deliveries[j])
+		})
+	}
+	return &deliveries, nil
+}
@@ -0,0 +1,58 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"news/domain/entities"
+	"news/shared/failure"
+	"news/shared/logger"
+)
+
+// publisher is the outbox.EventPublisher of webhooks: it turns an event into a
+// pending delivery for every webhook subscribed to it, the Dispatcher sends them.
+type publisher struct {
+	repo Repository
+}
+
+func NewPublisher(repo Repository) *publisher {
+	return &publisher{repo: repo}
+}
+
+// Publish skips the webhooks that already have a delivery of the event, so an
+// event the relay publishes again is not delivered twice.
+func (p *publisher) Publish(ctx context.Context, event entities.EventDto) error {
+	webhooks, err := p.repo.GetWebhooks(ctx)
+	if err != nil {
+		if failure.GetStatus(err) == http.StatusNotFound {
+			return nil
+		}
+		return err
+	}
+	delivered := map[string]bool{}
+	deliveries, err := p.repo.GetDeliveriesByEventID(ctx, event.ID)
+	if err != nil && failure.GetStatus(err) != http.StatusNotFound {
+		return err
+	}
+	if err == nil {
+		for _, delivery := range *deliveries {
+			delivered[delivery.WebhookID] = true
+		}
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+	for _, webhook := range *webhooks {
+		if delivered[webhook.ID] || !webhook.Subscribed(event.Type) {
+			continue
+		}
+		err = p.repo.CreateDelivery(ctx, entities.NewWebhookDelivery(webhook.ID, event, string(payload)))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+package webhook
+
+//go:generate go run github.com/golang/mock/mockgen -source repository.go -destination mock/repository_mock.go -package webhook_mock
+
+import (
+	"context"
+	"github.com/jmoiron/sqlx"
+	"net/http"
+	"news/domain/entities"
+	"news/shared/database"
+	"news/shared/failure"
+	"news/shared/logger"
+	"time"
+)
+
+type Repository interface {
+	CreateWebhook(ctx context.Context, webhook *entities.Webhook) error
+	GetWebhookByID(ctx context.Context, id string) (*entities.Webhook, error)
+	GetWebhooks(ctx context.Context) (*entities.Webhooks, error)
+	DeleteWebhook(ctx context.Context, id string) error
+	CreateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error
+	GetDeliveryByID(ctx context.Context, id string) (*entities.WebhookDelivery, error)
+	GetDeliveriesByWebhookID(ctx context.Context, webhookID string) (*entities.WebhookDeliveries, error)
+	GetDeliveriesByEventID(ctx context.Context, eventID string) (*entities.WebhookDeliveries, error)
+	GetDueDeliveries(ctx context.Context, now time.Time, limit int) (*entities.WebhookDeliveries, error)
+	// ClaimDelivery marks the delivery in flight until until, whatever its status. It
+	// returns false while another attempt holds it at now.
+	ClaimDelivery(ctx context.Context, id string, now time.Time, until time.Time) (bool, error)
+	// ClaimDueDelivery is ClaimDelivery for a pending delivery due at now only.
+	ClaimDueDelivery(ctx context.Context, id string, now time.Time, until time.Time) (bool, error)
+	// UpdateDelivery stores the outcome of an attempt and releases the delivery.
+	UpdateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error
+	// PurgeDeliveries deletes the succeeded and failed deliveries created before
+	// before, it returns how many it deleted.
+	PurgeDeliveries(ctx context.Context, before time.Time) (int64, error)
+}
+
+const deliveryColumns = "id, webhook_id, event_id, event_type, payload, status, attempts, response_code, last_error, " +
+	"`nextAttemptAt`, `lockedUntil`, `createdAt`, `deliveredAt`"
+
+var (
+	ErrNotFound         = failure.New(http.StatusNotFound, "webhook.not_found", "webhook not found")
+	ErrDeliveryNotFound = failure.New(http.StatusNotFound, "webhook.delivery_not_found", "delivery not found")
+	// ErrDeliveryInFlight is returned by attempts at a delivery another attempt is sending.
+	ErrDeliveryInFlight = failure.New(http.StatusConflict, "webhook.delivery_in_flight",
+		"the delivery is being sent")
+)
+
+type repository struct {
+	DB *sqlx.DB
+}
+
+func NewRepository(DB *sqlx.DB) *repository {
+	return &repository{DB: DB}
+}
+
+func (r *repository) CreateWebhook(ctx context.Context, webhook *entities.Webhook) error {
+	query := "INSERT INTO `webhooks`(`id`, `url`, `event_types`, `secret`, `status`, `createdAt`) " +
+		"VALUES (:id, :url, :event_types, :secret, :status, :createdAt)"
+	return r.namedExec(ctx, query, webhook)
+}
+
+// GetWebhookByID only returns active webhooks.
+func (r *repository) GetWebhookByID(ctx context.Context, id string) (webhook *entities.Webhook, err error) {
+	webhooks, err := r.selectWebhook(ctx, "WHERE id = ? AND status = ?", id, entities.WebhookActive)
+	if err != nil {
+		return
+	}
+	webhook = &(*webhooks)[0]
+	return
+}
+
+func (r *repository) GetWebhooks(ctx context.Context) (*entities.Webhooks, error) {
+	return r.selectWebhook(ctx, "WHERE status = ?", entities.WebhookActive)
+}
+
+func (r *repository) DeleteWebhook(ctx context.Context, id string) (err error) {
+	webhook, err := r.GetWebhookByID(ctx, id)
+	if err != nil {
+		return
+	}
+	webhook.Delete()
+	return r.namedExec(ctx, "UPDATE `webhooks` SET status = :status WHERE id = :id", webhook)
+}
+
+func (r *repository) selectWebhook(ctx context.Context, where string, args ...interface{}) (webhooks *entities.Webhooks, err error) {
+	webhooks = new(entities.Webhooks)
+	query := "SELECT id, url, event_types, secret, status, `createdAt` FROM `webhooks` " + where + " ORDER BY `createdAt`, id"
+	err = r.DB.SelectContext(ctx, webhooks, database.Rebind(r.DB, query), args...)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
+		return
+	}
+	if len(*webhooks) < 1 {
+		err = ErrNotFound
+	}
+	return
+}
+
+func (r *repository) CreateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	query := "INSERT INTO `webhook_deliveries`(" + deliveryColumns + ") VALUES (:id, :webhook_id, :event_id, :event_type, " +
+		":payload, :status, :attempts, :response_code, :last_error, :nextAttemptAt, :lockedUntil, :createdAt, :deliveredAt)"
+	return r.namedExec(ctx, query, delivery)
+}
+
+func (r *repository) GetDeliveryByID(ctx context.Context, id string) (delivery *entities.WebhookDelivery, err error) {
+	deliveries, err := r.selectDelivery(ctx, "WHERE id = ?", id)
+	if err != nil {
+		return
+	}
+	delivery = &(*deliveries)[0]
+	return
+}
+
+// GetDeliveriesByWebhookID returns the delivery log of a webhook, newest first.
+func (r *repository) GetDeliveriesByWebhookID(ctx context.Context, webhookID string) (*entities.WebhookDeliveries, error) {
+	return r.selectDelivery(ctx, "WHERE webhook_id = ? ORDER BY `createdAt` desc, id", webhookID)
+}
+
+func (r *repository) GetDeliveriesByEventID(ctx context.Context, eventID string) (*entities.WebhookDeliveries, error) {
+	return r.selectDelivery(ctx, "WHERE event_id = ?", eventID)
+}
+
+// GetDueDeliveries returns the pending deliveries to attempt at now that aren't in
+// flight, longest waiting first.
+func (r *repository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) (*entities.WebhookDeliveries, error) {
+	return r.selectDelivery(ctx, "WHERE status = ? AND `nextAttemptAt` <= ? AND (`lockedUntil` IS NULL OR `lockedUntil` <= ?) "+
+		"ORDER BY `nextAttemptAt`, id LIMIT ?", entities.DeliveryPending, now, now, limit)
+}
+
+// ClaimDelivery is a conditional update, of the attempts racing for a delivery only one changes the row.
+func (r *repository) ClaimDelivery(ctx context.Context, id string, now time.Time, until time.Time) (bool, error) {
+	return r.claim(ctx, "", id, now, until)
+}
+
+func (r *repository) ClaimDueDelivery(ctx context.Context, id string, now time.Time, until time.Time) (bool, error) {
+	return r.claim(ctx, "AND status = ? AND `nextAttemptAt` <= ? ", id, now, until, entities.DeliveryPending, now)
+}
+
+func (r *repository) claim(ctx context.Context, where string, id string, now time.Time, until time.Time,
+	args ...interface{}) (bool, error) {
+	query := "UPDATE `webhook_deliveries` SET `lockedUntil` = ? WHERE id = ? " + where +
+		"AND (`lockedUntil` IS NULL OR `lockedUntil` <= ?)"
+	args = append(append([]interface{}{until, id}, args...), now)
+	result, err := r.DB.ExecContext(ctx, database.Rebind(r.DB, query), args...)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return false, failure.InternalServerError.Wrap(err)
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return false, failure.InternalServerError.Wrap(err)
+	}
+	return affected == 1, nil
+}
+
+func (r *repository) UpdateDelivery(ctx context.Context, delivery *entities.WebhookDelivery) error {
+	query := "UPDATE `webhook_deliveries` SET status = :status, attempts = :attempts, response_code = :response_code, " +
+		"last_error = :last_error, `nextAttemptAt` = :nextAttemptAt, `lockedUntil` = NULL, `deliveredAt` = :deliveredAt " +
+		"WHERE id = :id"
+	return r.namedExec(ctx, query, delivery)
+}
+
+// PurgeDeliveries never deletes pending deliveries, they are still attempted.
+func (r *repository) PurgeDeliveries(ctx context.Context, before time.Time) (int64, error) {
+	query := "DELETE FROM `webhook_deliveries` WHERE `createdAt` < ? AND status IN (?, ?)"
+	result, err := r.DB.ExecContext(ctx, database.Rebind(r.DB, query), before, entities.DeliverySucceeded,
+		entities.DeliveryFailed)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return 0, failure.InternalServerError.Wrap(err)
+	}
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return 0, failure.InternalServerError.Wrap(err)
+	}
+	return deleted, nil
+}
+
+func (r *repository) selectDelivery(ctx context.Context, where string, args ...interface{}) (deliveries *entities.WebhookDeliveries, err error) {
+	deliveries = new(entities.WebhookDeliveries)
+	query := "SELECT " + deliveryColumns + " FROM `webhook_deliveries` " + where
+	err = r.DB.SelectContext(ctx, deliveries, database.Rebind(r.DB, query), args...)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		err = failure.InternalServerError.Wrap(err)
+		return
+	}
+	if len(*deliveries) < 1 {
+		err = ErrDeliveryNotFound
+	}
+	return
+}
+
+func (r *repository) namedExec(ctx context.Context, query string, arg interface{}) error {
+	stmt, err := r.DB.PrepareNamedContext(ctx, database.Rebind(r.DB, query))
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+	_, err = stmt.ExecContext(ctx, arg)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return failure.InternalServerError.Wrap(err)
+	}
+	return nil
+}
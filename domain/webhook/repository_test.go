@@ -0,0 +1,170 @@
+package webhook_test
+
+import (
+	"context"
+	"github.com/magiconair/properties/assert"
+	"news/domain/entities"
+	"news/domain/webhook"
+	"news/infras/dbtest"
+	"testing"
+	"time"
+)
+
+func repositoryContract(t *testing.T, newRepo func(t *testing.T) webhook.Repository) {
+	ctx := context.Background()
+	baseTime := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	newWebhook := func(id string, minutes int) *entities.Webhook {
+		return &entities.Webhook{ID: id, URL: "https://example.com/" + id, EventTypes: "NewsCreated,NewsPublished",
+			Secret: "secret", Status: entities.WebhookActive, CreatedAt: baseTime.Add(time.Duration(minutes) * time.Minute)}
+	}
+	newDelivery := func(id string, webhookID string, eventID string, minutes int) *entities.WebhookDelivery {
+		at := baseTime.Add(time.Duration(minutes) * time.Minute)
+		return &entities.WebhookDelivery{ID: id, WebhookID: webhookID, EventID: eventID, EventType: "NewsCreated",
+			Payload: `{"id":"` + eventID + `"}`, Status: entities.DeliveryPending, NextAttemptAt: &at, CreatedAt: at}
+	}
+
+	t.Run("webhooks", func(t *testing.T) {
+		repo := newRepo(t)
+		_, err := repo.GetWebhooks(ctx)
+		assert.Equal(t, err, webhook.ErrNotFound)
+
+		assert.Equal(t, repo.CreateWebhook(ctx, newWebhook("hook2", 1)), nil)
+		assert.Equal(t, repo.CreateWebhook(ctx, newWebhook("hook1", 0)), nil)
+
+		actual, err := repo.GetWebhookByID(ctx, "hook1")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, actual.SliceEventTypes(), []string{"NewsCreated", "NewsPublished"})
+		assert.Equal(t, actual.Secret, "secret")
+
+		assert.Equal(t, repo.DeleteWebhook(ctx, "hook1"), nil)
+		assert.Equal(t, repo.DeleteWebhook(ctx, "hook1"), webhook.ErrNotFound)
+		_, err = repo.GetWebhookByID(ctx, "hook1")
+		assert.Equal(t, err, webhook.ErrNotFound)
+		webhooks, err := repo.GetWebhooks(ctx)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(*webhooks), 1)
+		assert.Equal(t, (*webhooks)[0].ID, "hook2")
+	})
+
+	t.Run("deliveries", func(t *testing.T) {
+		repo := newRepo(t)
+		assert.Equal(t, repo.CreateDelivery(ctx, newDelivery("delivery1", "hook1", "event1", 0)), nil)
+		assert.Equal(t, repo.CreateDelivery(ctx, newDelivery("delivery2", "hook1", "event2", 2)), nil)
+		assert.Equal(t, repo.CreateDelivery(ctx, newDelivery("delivery3", "hook2", "event1", 1)), nil)
+		// one delivery per webhook and event
+		assert.Equal(t, repo.CreateDelivery(ctx, newDelivery("delivery4", "hook1", "event1", 3)) != nil, true)
+
+		deliveries, err := repo.GetDeliveriesByWebhookID(ctx, "hook1")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(*deliveries), 2)
+		assert.Equal(t, (*deliveries)[0].ID, "delivery2")
+
+		deliveries, err = repo.GetDeliveriesByEventID(ctx, "event1")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(*deliveries), 2)
+
+		due, err := repo.GetDueDeliveries(ctx, baseTime.Add(time.Minute), 10)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(*due), 2)
+		assert.Equal(t, (*due)[0].ID, "delivery1")
+		assert.Equal(t, (*due)[1].ID, "delivery3")
+
+		delivery := (*due)[0]
+		delivery.Succeed(204)
+		assert.Equal(t, repo.UpdateDelivery(ctx, &delivery), nil)
+		actual, err := repo.GetDeliveryByID(ctx, "delivery1")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, actual.Status, entities.DeliverySucceeded)
+		assert.Equal(t, actual.ResponseCode, 204)
+		assert.Equal(t, actual.DeliveredAt != nil, true)
+		assert.Equal(t, actual.NextAttemptAt == nil, true)
+
+		due, err = repo.GetDueDeliveries(ctx, baseTime.Add(time.Hour), 1)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(*due), 1)
+		assert.Equal(t, (*due)[0].ID, "delivery3")
+
+		_, err = repo.GetDeliveryByID(ctx, "missing")
+		assert.Equal(t, err, webhook.ErrDeliveryNotFound)
+	})
+
+	t.Run("claimed deliveries are leased to one attempt", func(t *testing.T) {
+		repo := newRepo(t)
+		assert.Equal(t, repo.CreateDelivery(ctx, newDelivery("delivery1", "hook1", "event1", 0)), nil)
+		assert.Equal(t, repo.CreateDelivery(ctx, newDelivery("delivery2", "hook1", "event2", 10)), nil)
+		now := baseTime.Add(time.Minute)
+
+		// not due yet
+		claimed, err := repo.ClaimDueDelivery(ctx, "delivery2", now, now.Add(time.Minute))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, claimed, false)
+
+		claimed, err = repo.ClaimDueDelivery(ctx, "delivery1", now, now.Add(time.Minute))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, claimed, true)
+		claimed, err = repo.ClaimDelivery(ctx, "delivery1", now, now.Add(time.Minute))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, claimed, false)
+		_, err = repo.GetDueDeliveries(ctx, now, 10)
+		assert.Equal(t, err, webhook.ErrDeliveryNotFound)
+
+		// storing the outcome releases it, a redelivery claims it whatever its status
+		actual, _ := repo.GetDeliveryByID(ctx, "delivery1")
+		actual.Succeed(200)
+		assert.Equal(t, repo.UpdateDelivery(ctx, actual), nil)
+		claimed, err = repo.ClaimDueDelivery(ctx, "delivery1", now, now.Add(time.Minute))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, claimed, false)
+		claimed, err = repo.ClaimDelivery(ctx, "delivery1", now, now.Add(time.Minute))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, claimed, true)
+
+		// the lease runs out
+		claimed, err = repo.ClaimDelivery(ctx, "delivery1", now.Add(time.Minute), now.Add(2*time.Minute))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, claimed, true)
+	})
+
+	t.Run("purge deletes old finished deliveries only", func(t *testing.T) {
+		repo := newRepo(t)
+		assert.Equal(t, repo.CreateDelivery(ctx, newDelivery("delivery1", "hook1", "event1", 0)), nil)
+		assert.Equal(t, repo.CreateDelivery(ctx, newDelivery("delivery2", "hook1", "event2", 1)), nil)
+		assert.Equal(t, repo.CreateDelivery(ctx, newDelivery("delivery3", "hook1", "event3", 2)), nil)
+		assert.Equal(t, repo.CreateDelivery(ctx, newDelivery("delivery4", "hook1", "event4", 60)), nil)
+		for _, id := range []string{"delivery1", "delivery4"} {
+			actual, _ := repo.GetDeliveryByID(ctx, id)
+			actual.Succeed(200)
+			assert.Equal(t, repo.UpdateDelivery(ctx, actual), nil)
+		}
+		actual, _ := repo.GetDeliveryByID(ctx, "delivery2")
+		actual.Fail(500, "server error", 1, time.Second)
+		assert.Equal(t, repo.UpdateDelivery(ctx, actual), nil)
+
+		deleted, err := repo.PurgeDeliveries(ctx, baseTime.Add(30*time.Minute))
+		assert.Equal(t, err, nil)
+		assert.Equal(t, deleted, int64(2))
+		deliveries, err := repo.GetDeliveriesByWebhookID(ctx, "hook1")
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(*deliveries), 2)
+		assert.Equal(t, (*deliveries)[0].ID, "delivery4")
+		assert.Equal(t, (*deliveries)[1].ID, "delivery3")
+	})
+}
+
+func TestMemoryRepository(t *testing.T) {
+	repositoryContract(t, func(t *testing.T) webhook.Repository {
+		return webhook.NewMemoryRepository()
+	})
+}
+
+func TestRepository(t *testing.T) {
+	for _, driver := range dbtest.Drivers() {
+		driver := driver
+		t.Run(driver, func(t *testing.T) {
+			repositoryContract(t, func(t *testing.T) webhook.Repository {
+				return webhook.NewRepository(dbtest.Open(t, driver))
+			})
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+	"news/domain/entities"
+	"news/shared/failure"
+	"news/shared/logger"
+)
+
+type Service interface {
+	Create(ctx context.Context, dto *entities.CreateWebhook) (*entities.WebhookDto, error)
+	GetAll(ctx context.Context) (*[]entities.WebhookDto, error)
+	Delete(ctx context.Context, id string) error
+	GetDeliveries(ctx context.Context, webhookID string) (*[]entities.WebhookDeliveryDto, error)
+	Redeliver(ctx context.Context, webhookID string, deliveryID string) (*entities.WebhookDeliveryDto, error)
+}
+
+type service struct {
+	repo   Repository
+	sender Sender
+}
+
+func NewService(repo Repository, sender Sender) *service {
+	return &service{repo: repo, sender: sender}
+}
+
+// Create returns the secret deliveries are signed with, a random one when the dto has none.
+// It is never returned again.
+func (s service) Create(ctx context.Context, dto *entities.CreateWebhook) (result *entities.WebhookDto, err error) {
+	secret := dto.Secret
+	if secret == "" {
+		secret, err = newSecret(ctx)
+		if err != nil {
+			return
+		}
+	}
+	webhook := entities.NewWebhook(dto.URL, dto.EventTypes, secret)
+	err = s.repo.CreateWebhook(ctx, webhook)
+	if err != nil {
+		return
+	}
+	result = webhook.ToDto()
+	result.Secret = secret
+	return
+}
+
+func newSecret(ctx context.Context) (string, error) {
+	value := make([]byte, 32)
+	_, err := rand.Read(value)
+	if err != nil {
+		logger.ErrorWithStack(ctx, err)
+		return "", failure.InternalServerError.Wrap(err)
+	}
+	return hex.EncodeToString(value), nil
+}
+
+func (s service) GetAll(ctx context.Context) (result *[]entities.WebhookDto, err error) {
+	webhooks, err := s.repo.GetWebhooks(ctx)
+	if err != nil {
+		if failure.GetStatus(err) == http.StatusNotFound {
+			return &[]entities.WebhookDto{}, nil
+		}
+		return
+	}
+	result = webhooks.ToWebhooksDto()
+	return
+}
+
+func (s service) Delete(ctx context.Context, id string) error {
+	return s.repo.DeleteWebhook(ctx, id)
+}
+
+func (s service) GetDeliveries(ctx context.Context, webhookID string) (result *[]entities.WebhookDeliveryDto, err error) {
+	_, err = s.repo.GetWebhookByID(ctx, webhookID)
+	if err != nil {
+		return
+	}
+	deliveries, err := s.repo.GetDeliveriesByWebhookID(ctx, webhookID)
+	if err != nil {
+		if failure.GetStatus(err) == http.StatusNotFound {
+			return &[]entities.WebhookDeliveryDto{}, nil
+		}
+		return
+	}
+	result = deliveries.ToDeliveriesDto()
+	return
+}
+
+// Redeliver makes one more attempt at a delivery right away, whatever its status.
+func (s service) Redeliver(ctx context.Context, webhookID string, deliveryID string) (result *entities.WebhookDeliveryDto, err error) {
+	_, err = s.repo.GetWebhookByID(ctx, webhookID)
+	if err != nil {
+		return
+	}
+	delivery, err := s.repo.GetDeliveryByID(ctx, deliveryID)
+	if err != nil {
+		return
+	}
+	if delivery.WebhookID != webhookID {
+		err = ErrDeliveryNotFound
+		return
+	}
+	err = s.sender.Send(ctx, delivery)
+	if err != nil {
+		return
+	}
+	result = delivery.ToDto()
+	return
+}
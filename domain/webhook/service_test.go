@@ -0,0 +1,111 @@
+package webhook_test
+
+import (
+	"context"
+	"github.com/golang/mock/gomock"
+	"github.com/magiconair/properties/assert"
+	"news/domain/entities"
+	"news/domain/webhook"
+	webhook_mock "news/domain/webhook/mock"
+	"news/shared/Date"
+	"news/shared/IDGEN"
+	"testing"
+	"time"
+)
+
+func TestWebhookService(t *testing.T) {
+	IDGEN.NewUUID = func() string {
+		return "d2668631-1563-46bd-9498-5bfac7eed17a"
+	}
+	mockTime := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	Date.Now = func() time.Time {
+		return mockTime
+	}
+	defer func() {
+		Date.Now = time.Now
+	}()
+
+	t.Run("testCreate", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		mockRepo := webhook_mock.NewMockRepository(ctrl)
+		service := webhook.NewService(mockRepo, webhook_mock.NewMockSender(ctrl))
+		ctx := context.Background()
+
+		expected := entities.NewWebhook("https://example.com/hook", []string{"NewsPublished"}, "secret")
+		mockRepo.EXPECT().CreateWebhook(ctx, expected).Return(nil)
+		actual, err := service.Create(ctx, &entities.CreateWebhook{URL: "https://example.com/hook",
+			EventTypes: []string{"NewsPublished"}, Secret: "secret"})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, actual.Secret, "secret")
+		assert.Equal(t, actual.EventTypes, []string{"NewsPublished"})
+
+		mockRepo.EXPECT().CreateWebhook(ctx, gomock.Any()).Return(nil)
+		actual, err = service.Create(ctx, &entities.CreateWebhook{URL: "https://example.com/hook"})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, len(actual.Secret), 64)
+		assert.Equal(t, actual.EventTypes, []string{})
+	})
+
+	t.Run("testRedeliver", func(t *testing.T) {
+		hook := entities.NewWebhook("https://example.com/hook", nil, "secret")
+		delivery := entities.NewWebhookDelivery(hook.ID, entities.EventDto{ID: "event1", Type: "NewsCreated"}, "{}")
+
+		sliceTest := []struct {
+			testTitle      string
+			mockSetup      func(ctx context.Context, repo *webhook_mock.MockRepository, sender *webhook_mock.MockSender)
+			webhookID      string
+			expectedResult *entities.WebhookDeliveryDto
+			expectedError  error
+		}{
+			{
+				testTitle: "redeliver success",
+				mockSetup: func(ctx context.Context, repo *webhook_mock.MockRepository, sender *webhook_mock.MockSender) {
+					repo.EXPECT().GetWebhookByID(ctx, hook.ID).Return(hook, nil)
+					repo.EXPECT().GetDeliveryByID(ctx, delivery.ID).Return(delivery, nil)
+					sender.EXPECT().Send(ctx, delivery).DoAndReturn(func(ctx context.Context, d *entities.WebhookDelivery) error {
+						d.Succeed(200)
+						return nil
+					})
+				},
+				webhookID: hook.ID,
+				expectedResult: &entities.WebhookDeliveryDto{ID: delivery.ID, WebhookID: hook.ID, EventID: "event1",
+					EventType: "NewsCreated", Status: "succeeded", Attempts: 1, ResponseCode: 200, CreatedAt: mockTime,
+					DeliveredAt: &mockTime},
+			},
+			{
+				testTitle: "delivery of another webhook",
+				mockSetup: func(ctx context.Context, repo *webhook_mock.MockRepository, sender *webhook_mock.MockSender) {
+					repo.EXPECT().GetWebhookByID(ctx, "other").Return(&entities.Webhook{ID: "other"}, nil)
+					repo.EXPECT().GetDeliveryByID(ctx, delivery.ID).Return(delivery, nil)
+				},
+				webhookID:     "other",
+				expectedError: webhook.ErrDeliveryNotFound,
+			},
+			{
+				testTitle: "webhook not found",
+				mockSetup: func(ctx context.Context, repo *webhook_mock.MockRepository, sender *webhook_mock.MockSender) {
+					repo.EXPECT().GetWebhookByID(ctx, "missing").Return(nil, webhook.ErrNotFound)
+				},
+				webhookID:     "missing",
+				expectedError: webhook.ErrNotFound,
+			},
+		}
+
+		for _, test := range sliceTest {
+			t.Run(test.testTitle, func(t *testing.T) {
+				ctrl := gomock.NewController(t)
+				defer ctrl.Finish()
+				mockRepo := webhook_mock.NewMockRepository(ctrl)
+				mockSender := webhook_mock.NewMockSender(ctrl)
+				service := webhook.NewService(mockRepo, mockSender)
+				ctx := context.Background()
+				test.mockSetup(ctx, mockRepo, mockSender)
+
+				actual, err := service.Redeliver(ctx, test.webhookID, delivery.ID)
+				assert.Equal(t, err, test.expectedError)
+				assert.Equal(t, actual, test.expectedResult)
+			})
+		}
+	})
+}
@@ -0,0 +1,31 @@
+package infras
+
+import (
+	"fmt"
+	"news/configs"
+
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+	DriverSQLite   = "sqlite"
+	// DriverMemory keeps everything in process memory, it has no database to open.
+	DriverMemory = "memory"
+)
+
+// DatabaseNewClient opens the database selected by DB.DRIVER, mysql when empty.
+func DatabaseNewClient(cfg configs.Config) (*sqlx.DB, error) {
+	switch cfg.DB.Driver {
+	case "", DriverMySQL:
+		return MysqlNewClient(cfg)
+	case DriverPostgres:
+		return PostgresNewClient(cfg)
+	case DriverSQLite:
+		return SqliteNewClient(cfg)
+	case DriverMemory:
+		return nil, fmt.Errorf("database driver %q has no database", cfg.DB.Driver)
+	}
+	return nil, fmt.Errorf("database driver %q not supported", cfg.DB.Driver)
+}
@@ -0,0 +1,81 @@
+// Package dbtest opens migrated databases for repository tests. SQLite always
+// runs, MySQL and Postgres run when TEST_MYSQL_DSN or TEST_POSTGRES_DSN is set.
+// Their databases are wiped: every migration is reverted and applied again, so
+// a database whose name doesn't contain "test" is refused.
+package dbtest
+
+import (
+	"context"
+	"news/configs"
+	"news/infras"
+	"news/migrations"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+)
+
+var dsnEnv = map[string]string{
+	infras.DriverMySQL:    "TEST_MYSQL_DSN",
+	infras.DriverPostgres: "TEST_POSTGRES_DSN",
+}
+
+var currentDatabase = map[string]string{
+	infras.DriverMySQL:    "SELECT DATABASE()",
+	infras.DriverPostgres: "SELECT current_database()",
+}
+
+// Drivers lists every driver a repository should be tested against.
+func Drivers() []string {
+	return []string{infras.DriverSQLite, infras.DriverMySQL, infras.DriverPostgres}
+}
+
+// Open returns an empty database with the latest schema, the test is skipped
+// when the driver has no DSN configured.
+func Open(t *testing.T, driver string) *sqlx.DB {
+	t.Helper()
+	var db *sqlx.DB
+	var err error
+	if driver == infras.DriverSQLite {
+		var cfg configs.Config
+		cfg.DB.SQLite.Path = filepath.Join(t.TempDir(), "news.db")
+		db, err = infras.SqliteNewClient(cfg)
+	} else {
+		dsn := os.Getenv(dsnEnv[driver])
+		if dsn == "" {
+			t.Skipf("%s is not set", dsnEnv[driver])
+		}
+		db, err = sqlx.Open(driver, dsn)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	if driver != infras.DriverSQLite {
+		var name string
+		err = db.GetContext(ctx, &name, currentDatabase[driver])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(strings.ToLower(name), "test") {
+			t.Fatalf("%s points at database %q, the tests wipe it: use a database named with test", dsnEnv[driver], name)
+		}
+		_, err = migrator.Down(ctx, len(migrator.Migrations()))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = migrator.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
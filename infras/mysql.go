@@ -14,7 +14,6 @@ func MysqlNewClient(cfg configs.Config) (*sqlx.DB, error) {
 	ds := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?parseTime=true", cfg.DB.MySQL.Username,
 		cfg.DB.MySQL.Password,
 		cfg.DB.MySQL.Host, cfg.DB.MySQL.Port, cfg.DB.MySQL.Name)
-	fmt.Println(ds)
 	client, err := sqlx.Open("mysql", ds)
 	if err != nil {
 		log.
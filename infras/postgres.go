@@ -0,0 +1,43 @@
+package infras
+
+import (
+	"fmt"
+	"news/configs"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	"github.com/rs/zerolog/log"
+)
+
+func PostgresNewClient(cfg configs.Config) (*sqlx.DB, error) {
+	ds := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=%s timezone=%s",
+		cfg.DB.Postgres.Host, cfg.DB.Postgres.Port, cfg.DB.Postgres.Username, cfg.DB.Postgres.Password,
+		cfg.DB.Postgres.Name, cfg.DB.Postgres.SSLMode, cfg.DB.Postgres.Timezone)
+	client, err := sqlx.Open("postgres", ds)
+	if err != nil {
+		log.
+			Fatal().
+			Err(err).
+			Str("name", cfg.DB.Postgres.Username).
+			Str("host", cfg.DB.Postgres.Host).
+			Str("port", cfg.DB.Postgres.Port).
+			Str("dbName", cfg.DB.Postgres.Name).
+			Msg("Failed connecting to database")
+	} else {
+		log.
+			Info().
+			Str("name", cfg.DB.Postgres.Username).
+			Str("host", cfg.DB.Postgres.Host).
+			Str("port", cfg.DB.Postgres.Port).
+			Str("dbName", cfg.DB.Postgres.Name).
+			Msg("Connected to database")
+	}
+
+	client.SetConnMaxLifetime(time.Minute * 30)
+	client.SetConnMaxIdleTime(time.Minute * 1)
+	client.SetMaxOpenConns(100)
+	client.SetMaxIdleConns(10)
+
+	return client, nil
+}
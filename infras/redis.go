@@ -5,6 +5,7 @@ import (
 	"news/configs"
 
 	"github.com/go-redis/redis"
+	"github.com/rs/zerolog/log"
 )
 
 func RedisNewClient(config configs.Config) *redis.Client {
@@ -13,11 +14,14 @@ func RedisNewClient(config configs.Config) *redis.Client {
 		Password: config.Cache.Redis.Primary.Password,
 	})
 
-	pong, err := client.Ping().Result()
+	_, err := client.Ping().Result()
 	if err != nil {
 		panic(err)
 	}
-	fmt.Println(pong, err)
+	log.Info().
+		Str("host", config.Cache.Redis.Primary.Host).
+		Str("port", config.Cache.Redis.Primary.Port).
+		Msg("Connected to redis")
 
 	return client
 }
\ No newline at end of file
@@ -0,0 +1,35 @@
+package infras
+
+import (
+	"net/url"
+	"news/configs"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/rs/zerolog/log"
+	_ "modernc.org/sqlite"
+)
+
+func SqliteNewClient(cfg configs.Config) (*sqlx.DB, error) {
+	ds := "file:" + cfg.DB.SQLite.Path + "?" + url.Values{
+		"_pragma":      {"foreign_keys(1)", "busy_timeout(5000)", "journal_mode(WAL)"},
+		"_time_format": {"sqlite"},
+	}.Encode()
+	client, err := sqlx.Open("sqlite", ds)
+	if err != nil {
+		log.
+			Fatal().
+			Err(err).
+			Str("path", cfg.DB.SQLite.Path).
+			Msg("Failed opening database")
+	} else {
+		log.
+			Info().
+			Str("path", cfg.DB.SQLite.Path).
+			Msg("Opened database")
+	}
+
+	// sqlite allows a single writer, one connection avoids "database is locked" errors
+	client.SetMaxOpenConns(1)
+
+	return client, nil
+}
@@ -1,32 +1,256 @@
 package main
 
 import (
-	"fmt"
+	"context"
+	"errors"
+	"github.com/go-redis/redis"
+	"github.com/jmoiron/sqlx"
+	"github.com/spf13/pflag"
 	"log"
 	"news/app"
+	"news/app/cli"
+	"news/app/handlers"
 	"news/configs"
+	"news/domain/audit"
+	"news/domain/comment"
+	"news/domain/entities"
+	"news/domain/media"
 	"news/domain/news"
+	"news/domain/outbox"
 	"news/domain/tag"
+	"news/domain/webhook"
 	"news/infras"
+	"news/migrations"
+	"news/shared/health"
+	"news/shared/idempotency"
+	"news/shared/lifecycle"
 	"news/shared/logger"
+	"news/shared/metrics"
+	"news/shared/ratelimit"
+	"news/shared/retention"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 )
 
+type repositories struct {
+	news     news.Repository
+	tag      tag.Repository
+	comment  comment.Repository
+	media    media.Repository
+	outbox   outbox.Repository
+	webhook  webhook.Repository
+	audit    audit.Repository
+	migrator *migrations.Migrator
+	db       *sqlx.DB
+}
+
+// newRepositories builds the repositories of DB.DRIVER, the migrator and db are nil for the memory driver.
+func newRepositories(configuration configs.Config) (repos repositories, err error) {
+	if configuration.DB.Driver == infras.DriverMemory {
+		newsRepo, tagRepo := news.NewMemoryRepository(), tag.NewMemoryRepository()
+		commentRepo := comment.NewMemoryRepository()
+		newsRepo.CountComments = func(newsID string) int {
+			return commentRepo.CountComments(newsID, entities.CommentApproved)
+		}
+		box, auditRepo := outbox.NewMemoryRepository(), audit.NewMemoryRepository()
+		newsRepo.Outbox, tagRepo.Outbox = box, box
+		newsRepo.Audit, tagRepo.Audit = auditRepo, auditRepo
+		repos = repositories{
+			news:    newsRepo,
+			tag:     tagRepo,
+			comment: commentRepo,
+			media:   media.NewMemoryRepository(),
+			outbox:  box,
+			webhook: webhook.NewMemoryRepository(),
+			audit:   auditRepo,
+		}
+		return
+	}
+	db, err := infras.DatabaseNewClient(configuration)
+	if err != nil {
+		return
+	}
+	repos = repositories{
+		db:      db,
+		news:    news.NewRepository(db),
+		tag:     tag.NewRepository(db),
+		comment: comment.NewRepository(db),
+		media:   media.NewRepository(db),
+		outbox:  outbox.NewRepository(db),
+		webhook: webhook.NewRepository(db),
+		audit:   audit.NewRepository(db),
+	}
+	if configuration.DB.Driver == infras.DriverPostgres {
+		repos.news = news.NewPostgresRepository(db)
+		repos.tag = tag.NewPostgresRepository(db)
+	}
+	err = metrics.RegisterDB(db.DB, configuration.DB.Driver)
+	if err != nil {
+		return
+	}
+	repos.migrator, err = migrations.NewMigrator(db)
+	return
+}
+
+// instrument wraps every repository to observe the duration of its calls.
+func (repos repositories) instrument() repositories {
+	repos.news = news.NewInstrumentedRepository(repos.news)
+	repos.tag = tag.NewInstrumentedRepository(repos.tag)
+	repos.comment = comment.NewInstrumentedRepository(repos.comment)
+	repos.media = media.NewInstrumentedRepository(repos.media)
+	repos.outbox = outbox.NewInstrumentedRepository(repos.outbox)
+	repos.webhook = webhook.NewInstrumentedRepository(repos.webhook)
+	repos.audit = audit.NewInstrumentedRepository(repos.audit)
+	return repos
+}
+
+// newChecker checks the database and redis, the memory driver has no dependency to check.
+func newChecker(configuration configs.Config, db *sqlx.DB, redisClient *redis.Client) *health.Checker {
+	required := map[string]bool{}
+	for _, name := range configuration.Health.Required {
+		required[strings.TrimSpace(name)] = true
+	}
+	var checks []health.Check
+	if db != nil {
+		checks = append(checks, health.Check{Name: "database", Required: required["database"], Ping: db.PingContext})
+	}
+	if redisClient != nil {
+		checks = append(checks, health.Check{Name: "redis", Required: required["redis"],
+			Ping: func(ctx context.Context) error {
+				return redisClient.WithContext(ctx).Ping().Err()
+			}})
+	}
+	return health.NewChecker(checks, time.Duration(configuration.Health.Timeout)*time.Millisecond, health.Build())
+}
+
 func main() {
-	logger.InitLogger()
-	configuration := configs.Get()
-	cache := infras.RedisNewClient(configuration)
-	mysql, err := infras.MysqlNewClient(configuration)
+	configuration, args, err := configs.Load(os.Args[1:])
+	if errors.Is(err, pflag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if len(args) > 0 && args[0] == "config" {
+		err = cli.RunConfig(args, configuration)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	err = configuration.Validate()
 	if err != nil {
-		fmt.Println(err)
+		log.Fatal(err)
 	}
-	newsRepo := news.NewRepository(mysql)
-	tagsRepo := tag.NewRepository(mysql)
-	newsCache := news.NewCacheImpl(cache, configuration.Cache.Redis.Expired.News)
-	newsService := news.NewService(newsRepo, tagsRepo, newsCache)
-	tagService := tag.NewService(tagsRepo)
+	logger.InitLogger(configuration.Server.LogLevel)
+	repos, err := newRepositories(configuration)
+	if err != nil {
+		log.Fatal(err)
+	}
+	repos = repos.instrument()
+	manager := lifecycle.NewManager(time.Duration(configuration.Server.ShutdownTimeout) * time.Millisecond)
+	if repos.db != nil {
+		manager.Close("database", repos.db.Close)
+	}
+	transfer := news.NewTransfer(repos.news, repos.tag, repos.media)
+	if len(args) > 0 {
+		err = cli.Run(args, transfer, repos.migrator)
+		manager.Shutdown()
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if configuration.DB.MigrateOnStart && repos.migrator != nil {
+		_, err = repos.migrator.Up(context.Background())
+		if err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	dispatcher := webhook.NewDispatcher(repos.webhook,
+		webhook.NewClient(time.Duration(configuration.Webhook.Timeout)*time.Millisecond),
+		configuration.Webhook.MaxAttempts, time.Duration(configuration.Webhook.Backoff)*time.Millisecond,
+		time.Duration(configuration.Webhook.Interval)*time.Millisecond)
+	manager.Go("webhook dispatcher", dispatcher.Run)
+	relay := outbox.NewRelay(repos.outbox,
+		outbox.NewMultiPublisher(outbox.NewLogPublisher(), webhook.NewPublisher(repos.webhook)),
+		time.Duration(configuration.Outbox.Interval)*time.Millisecond, configuration.Outbox.BatchSize,
+		configuration.Outbox.MaxAttempts)
+	manager.Go("outbox relay", relay.Run)
+	purger := retention.NewPurger(time.Duration(configuration.Retention.Interval)*time.Millisecond,
+		retention.Job{Name: "outbox", Retention: time.Duration(configuration.Retention.Outbox) * time.Millisecond,
+			Purge: repos.outbox.Purge},
+		retention.Job{Name: "webhook deliveries",
+			Retention: time.Duration(configuration.Retention.Deliveries) * time.Millisecond,
+			Purge:     repos.webhook.PurgeDeliveries})
+	manager.Go("retention purger", purger.Run)
 
-	fmt.Println(cache, mysql)
-	app := app.CreateApp(newsService, tagService)
+	var newsCache news.Cache = news.NewMemoryCache(configuration.Cache.Redis.Expired.News)
+	var limiter ratelimit.Limiter = ratelimit.NewMemoryLimiter()
+	var idempotencyStore idempotency.Store = idempotency.NewMemoryStore()
+	var redisClient *redis.Client
+	if configuration.DB.Driver != infras.DriverMemory {
+		redisClient = infras.RedisNewClient(configuration)
+		manager.Close("redis", redisClient.Close)
+		newsCache = news.NewCacheImpl(redisClient, configuration.Cache.Redis.Expired.News)
+		limiter = ratelimit.NewFallbackLimiter(ratelimit.NewRedisLimiter(redisClient), limiter)
+		idempotencyStore = idempotency.NewRedisStore(redisClient)
+	}
+	auditService := audit.NewService(repos.audit)
+	newsService := news.NewService(repos.news, repos.tag, repos.media, newsCache)
+	tagService := tag.NewService(repos.tag)
+	moderator := comment.NewWordListModerator(configuration.Comment.Moderation.BlockedWords,
+		configuration.Comment.Moderation.AutoApprove)
+	commentService := comment.NewService(repos.comment, repos.news, moderator, newsCache)
+	mediaStore, err := media.NewLocalStore(configuration.Media.Path)
+	if err != nil {
+		log.Fatal(err)
+	}
+	thumbnailSizes, err := media.ParseThumbnailSizes(configuration.Media.Thumbnail.Sizes)
+	if err != nil {
+		log.Fatal(err)
+	}
+	mediaService := media.NewService(repos.media, mediaStore, configuration.Media.MaxSize,
+		configuration.Media.MaxPixels, configuration.Media.AllowedTypes, thumbnailSizes)
 
-	log.Fatal(app.Listen(":" + configuration.Server.Port))
+	webhookService := webhook.NewService(repos.webhook, dispatcher)
+
+	apiKeys, err := handlers.ParseAPIKeys(configuration.Auth.APIKeys)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rateLimit := handlers.RateLimitConfig{KeyBy: configuration.RateLimit.KeyBy, Keys: apiKeys,
+		Read: ratelimit.Budget{Limit: configuration.RateLimit.Read.Limit,
+			Window: time.Duration(configuration.RateLimit.Read.Window) * time.Millisecond},
+		Write: ratelimit.Budget{Limit: configuration.RateLimit.Write.Limit,
+			Window: time.Duration(configuration.RateLimit.Write.Window) * time.Millisecond},
+	}
+	if configuration.RateLimit.Enabled {
+		rateLimit.Limiter = limiter
+	}
+
+	app := app.CreateApp(newsService, tagService, commentService, mediaService, transfer, webhookService,
+		auditService, rateLimit, newChecker(configuration, repos.db, redisClient), handlers.CachePolicies{
+			News:     configuration.HTTPCache.News,
+			NewsList: configuration.HTTPCache.NewsList,
+			Tags:     configuration.HTTPCache.Tags,
+		}, handlers.IdempotencyConfig{
+			Store: idempotencyStore,
+			TTL:   time.Duration(configuration.Idempotency.TTL) * time.Millisecond,
+		}, apiKeys, app.Proxies{Header: configuration.Server.ProxyHeader, Trusted: configuration.Server.TrustedProxies})
+
+	manager.Serve("http", func() error {
+		return app.Listen(":" + configuration.Server.Port)
+	}, app.Shutdown)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	err = manager.Run(ctx)
+	if err != nil {
+		log.Fatal(err)
+	}
 }
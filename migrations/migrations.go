@@ -0,0 +1,224 @@
+package migrations
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"news/shared/database"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+//go:embed mysql/*.sql postgres/*.sql sqlite/*.sql
+var files embed.FS
+
+var fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is one versioned schema change, Up applies it and Down reverts it.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Version   int        `db:"version"`
+	Name      string     `db:"name"`
+	AppliedAt *time.Time `db:"applied_at"`
+}
+
+// Load reads the migrations of a dialect directory, ordered by version. Every
+// migration needs both an up and a down file.
+func Load(fsys fs.FS, dir string) (migrations []Migration, err error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return
+	}
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration %s: file name must look like 0001_name.up.sql", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		content, errs := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if errs != nil {
+			return nil, errs
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		}
+		if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d: up and down files have different names", version)
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %d: both up and down files are required", migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return
+}
+
+// Statements splits a migration file on semicolons ending a line, skipping
+// comment only statements.
+func Statements(content string) (statements []string) {
+	for _, statement := range strings.Split(content, ";\n") {
+		var lines []string
+		for _, line := range strings.Split(statement, "\n") {
+			if trimmed := strings.TrimSpace(line); trimmed != "" && !strings.HasPrefix(trimmed, "--") {
+				lines = append(lines, line)
+			}
+		}
+		statement = strings.TrimSuffix(strings.TrimSpace(strings.Join(lines, "\n")), ";")
+		if statement != "" {
+			statements = append(statements, statement)
+		}
+	}
+	return
+}
+
+type Migrator struct {
+	DB         *sqlx.DB
+	migrations []Migration
+}
+
+// NewMigrator loads the migrations written for the driver of DB, every driver
+// has its own directory named after it.
+func NewMigrator(DB *sqlx.DB) (*Migrator, error) {
+	migrations, err := Load(files, DB.DriverName())
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{DB: DB, migrations: migrations}, nil
+}
+
+func (m *Migrator) Migrations() []Migration {
+	return m.migrations
+}
+
+func (m *Migrator) init(ctx context.Context) error {
+	_, err := m.DB.ExecContext(ctx, database.Rebind(m.DB, "CREATE TABLE IF NOT EXISTS `schema_migrations` ("+
+		"`version` integer NOT NULL, "+
+		"`name` varchar(100) NOT NULL, "+
+		"`applied_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP, "+
+		"PRIMARY KEY (`version`))"))
+	return err
+}
+
+func (m *Migrator) applied(ctx context.Context) (versions map[int]time.Time, err error) {
+	err = m.init(ctx)
+	if err != nil {
+		return
+	}
+	var rows []Status
+	err = m.DB.SelectContext(ctx, &rows, database.Rebind(m.DB, "SELECT version, name, applied_at FROM `schema_migrations`"))
+	if err != nil {
+		return
+	}
+	versions = map[int]time.Time{}
+	for _, row := range rows {
+		versions[row.Version] = *row.AppliedAt
+	}
+	return
+}
+
+// Up applies every pending migration in order and returns the applied ones.
+func (m *Migrator) Up(ctx context.Context) (done []Migration, err error) {
+	versions, err := m.applied(ctx)
+	if err != nil {
+		return
+	}
+	for _, migration := range m.migrations {
+		if _, ok := versions[migration.Version]; ok {
+			continue
+		}
+		err = m.run(ctx, migration.Up, migration, "INSERT INTO `schema_migrations` (version, name) VALUES (?, ?)",
+			migration.Version, migration.Name)
+		if err != nil {
+			return
+		}
+		done = append(done, migration)
+	}
+	return
+}
+
+// Down reverts the last steps applied migrations, newest first.
+func (m *Migrator) Down(ctx context.Context, steps int) (done []Migration, err error) {
+	versions, err := m.applied(ctx)
+	if err != nil {
+		return
+	}
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := versions[migration.Version]; !ok {
+			continue
+		}
+		err = m.run(ctx, migration.Down, migration, "DELETE FROM `schema_migrations` WHERE version = ?",
+			migration.Version)
+		if err != nil {
+			return
+		}
+		done = append(done, migration)
+	}
+	return
+}
+
+// Status lists every known migration, AppliedAt is nil for pending ones.
+func (m *Migrator) Status(ctx context.Context) (statuses []Status, err error) {
+	versions, err := m.applied(ctx)
+	if err != nil {
+		return
+	}
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if appliedAt, ok := versions[migration.Version]; ok {
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return
+}
+
+// run executes a migration file and records it. MySQL commits DDL statements
+// implicitly, so there the transaction only guards the data statements.
+func (m *Migrator) run(ctx context.Context, content string, migration Migration, record string, args ...interface{}) (err error) {
+	tx, err := m.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return
+	}
+	for _, statement := range Statements(content) {
+		_, err = tx.ExecContext(ctx, statement)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+		}
+	}
+	_, err = tx.ExecContext(ctx, database.Rebind(tx, record), args...)
+	if err != nil {
+		tx.Rollback()
+		return
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,90 @@
+package migrations_test
+
+import (
+	"context"
+	"errors"
+	"github.com/jmoiron/sqlx"
+	"github.com/magiconair/properties/assert"
+	_ "modernc.org/sqlite"
+	"news/migrations"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestLoad(t *testing.T) {
+	sliceTest := []struct {
+		testTitle      string
+		input          fstest.MapFS
+		expectedResult []migrations.Migration
+		expectedError  error
+	}{
+		{
+			testTitle: "ordered by version",
+			input: fstest.MapFS{
+				"mysql/0010_second.up.sql":   {Data: []byte("up 10")},
+				"mysql/0010_second.down.sql": {Data: []byte("down 10")},
+				"mysql/0002_first.up.sql":    {Data: []byte("up 2")},
+				"mysql/0002_first.down.sql":  {Data: []byte("down 2")},
+			},
+			expectedResult: []migrations.Migration{
+				{Version: 2, Name: "first", Up: "up 2", Down: "down 2"},
+				{Version: 10, Name: "second", Up: "up 10", Down: "down 10"},
+			},
+		},
+		{
+			testTitle: "error missing down",
+			input: fstest.MapFS{
+				"mysql/0001_first.up.sql": {Data: []byte("up 1")},
+			},
+			expectedError: errors.New("migration 1: both up and down files are required"),
+		},
+		{
+			testTitle: "error file name",
+			input: fstest.MapFS{
+				"mysql/first.sql": {Data: []byte("up 1")},
+			},
+			expectedError: errors.New("migration first.sql: file name must look like 0001_name.up.sql"),
+		},
+	}
+
+	for _, test := range sliceTest {
+		t.Run(test.testTitle, func(t *testing.T) {
+			actual, err := migrations.Load(test.input, "mysql")
+			assert.Equal(t, err, test.expectedError)
+			assert.Equal(t, actual, test.expectedResult)
+		})
+	}
+}
+
+func TestStatements(t *testing.T) {
+	input := "-- a comment\nCREATE TABLE `a` (\n  `id` int\n);\n\n-- only a comment;\nUPDATE `a` SET id = 1;\n"
+	assert.Equal(t, migrations.Statements(input), []string{"CREATE TABLE `a` (\n  `id` int\n)", "UPDATE `a` SET id = 1"})
+}
+
+func TestMigratorSqlite(t *testing.T) {
+	db, err := sqlx.Open("sqlite", filepath.Join(t.TempDir(), "news.db"))
+	assert.Equal(t, err, nil)
+	defer db.Close()
+	migrator, err := migrations.NewMigrator(db)
+	assert.Equal(t, err, nil)
+	ctx := context.Background()
+
+	done, err := migrator.Up(ctx)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(done), 14)
+
+	statuses, err := migrator.Status(ctx)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, statuses[13].Name, "webhook_delivery_lease")
+	assert.Equal(t, statuses[13].AppliedAt != nil, true)
+
+	done, err = migrator.Down(ctx, 14)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, done[0].Name, "webhook_delivery_lease")
+	assert.Equal(t, done[13].Name, "baseline")
+
+	done, err = migrator.Up(ctx)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, len(done), 14)
+}
@@ -0,0 +1,29 @@
+-- schema of the original news.sql dump, existing databases already have these tables
+CREATE TABLE IF NOT EXISTS `news` (
+  `ID` varchar(36) NOT NULL,
+  `slug` varchar(160) NOT NULL,
+  `title` varchar(120) NOT NULL,
+  `content` text NOT NULL,
+  `status` enum('1','2','3') NOT NULL,
+  `topic` varchar(60) NOT NULL,
+  `createdAt` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `deletedAt` datetime DEFAULT NULL,
+  PRIMARY KEY (`ID`),
+  UNIQUE KEY `slug` (`slug`)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+
+CREATE TABLE IF NOT EXISTS `news_tags` (
+  `news_id` varchar(36) NOT NULL,
+  `tag_id` varchar(36) NOT NULL,
+  PRIMARY KEY (`news_id`,`tag_id`),
+  KEY `news_id` (`news_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+
+CREATE TABLE IF NOT EXISTS `tags` (
+  `id` varchar(36) NOT NULL,
+  `name` varchar(50) NOT NULL,
+  `status` enum('1','2') NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `name_unique` (`name`),
+  KEY `name` (`name`)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
@@ -0,0 +1,2 @@
+ALTER TABLE `tags`
+  DROP COLUMN `createdAt`;
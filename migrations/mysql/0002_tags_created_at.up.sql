@@ -0,0 +1,2 @@
+ALTER TABLE `tags`
+  ADD `createdAt` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP;
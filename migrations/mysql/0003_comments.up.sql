@@ -0,0 +1,12 @@
+CREATE TABLE `comments` (
+  `id` varchar(36) NOT NULL,
+  `news_id` varchar(36) NOT NULL,
+  `parent_id` varchar(36) DEFAULT NULL,
+  `author` varchar(60) NOT NULL,
+  `content` text NOT NULL,
+  `status` enum('1','2','3','4') NOT NULL,
+  `createdAt` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `news_id_status` (`news_id`,`status`),
+  KEY `status` (`status`)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
@@ -0,0 +1,4 @@
+ALTER TABLE `news`
+  DROP COLUMN `featured_image_id`;
+
+DROP TABLE `media`;
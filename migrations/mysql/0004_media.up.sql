@@ -0,0 +1,15 @@
+CREATE TABLE `media` (
+  `id` varchar(64) NOT NULL,
+  `name` varchar(255) NOT NULL,
+  `path` varchar(80) NOT NULL,
+  `mime_type` varchar(40) NOT NULL,
+  `size` bigint(20) NOT NULL,
+  `width` int(11) NOT NULL DEFAULT '0',
+  `height` int(11) NOT NULL DEFAULT '0',
+  `thumbnails` varchar(255) NOT NULL DEFAULT '',
+  `createdAt` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+
+ALTER TABLE `news`
+  ADD `featured_image_id` varchar(64) DEFAULT NULL AFTER `topic`;
@@ -0,0 +1,2 @@
+ALTER TABLE `news`
+  ADD `content_format` enum('1','2','3') NOT NULL DEFAULT '1' AFTER `content`;
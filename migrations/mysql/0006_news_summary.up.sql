@@ -0,0 +1,4 @@
+ALTER TABLE `news`
+  ADD `excerpt` varchar(255) NOT NULL DEFAULT '' AFTER `content_format`,
+  ADD `word_count` int(11) NOT NULL DEFAULT '0' AFTER `excerpt`,
+  ADD `reading_time_minutes` int(11) NOT NULL DEFAULT '0' AFTER `word_count`;
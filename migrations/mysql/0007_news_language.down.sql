@@ -0,0 +1,6 @@
+ALTER TABLE `news`
+  DROP KEY `translation_group_id`,
+  DROP KEY `slug_language`,
+  ADD UNIQUE KEY `slug` (`slug`),
+  DROP COLUMN `translation_group_id`,
+  DROP COLUMN `language`;
@@ -0,0 +1,9 @@
+ALTER TABLE `news`
+  ADD `language` varchar(5) NOT NULL DEFAULT 'id' AFTER `topic`,
+  ADD `translation_group_id` varchar(36) NOT NULL DEFAULT '' AFTER `language`,
+  DROP KEY `slug`,
+  ADD UNIQUE KEY `slug_language` (`slug`,`language`),
+  ADD KEY `translation_group_id` (`translation_group_id`);
+
+-- every existing news starts its own translation group
+UPDATE `news` SET `translation_group_id` = `ID` WHERE `translation_group_id` = '';
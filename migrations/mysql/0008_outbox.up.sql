@@ -0,0 +1,13 @@
+CREATE TABLE `outbox` (
+  `sequence` bigint(20) NOT NULL AUTO_INCREMENT,
+  `id` varchar(36) NOT NULL,
+  `type` varchar(40) NOT NULL,
+  `aggregate_id` varchar(64) NOT NULL,
+  `payload` mediumtext NOT NULL,
+  `attempts` int(11) NOT NULL DEFAULT '0',
+  `createdAt` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `publishedAt` datetime DEFAULT NULL,
+  PRIMARY KEY (`sequence`),
+  UNIQUE KEY `outbox_id` (`id`),
+  KEY `outbox_pending` (`publishedAt`, `sequence`)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
@@ -0,0 +1,28 @@
+CREATE TABLE `webhooks` (
+  `id` varchar(36) NOT NULL,
+  `url` varchar(2048) NOT NULL,
+  `event_types` varchar(255) NOT NULL DEFAULT '',
+  `secret` varchar(255) NOT NULL,
+  `status` tinyint(1) NOT NULL DEFAULT '1',
+  `createdAt` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
+
+CREATE TABLE `webhook_deliveries` (
+  `id` varchar(36) NOT NULL,
+  `webhook_id` varchar(36) NOT NULL,
+  `event_id` varchar(36) NOT NULL,
+  `event_type` varchar(40) NOT NULL,
+  `payload` mediumtext NOT NULL,
+  `status` tinyint(1) NOT NULL DEFAULT '1',
+  `attempts` int(11) NOT NULL DEFAULT '0',
+  `response_code` int(11) NOT NULL DEFAULT '0',
+  `last_error` varchar(1024) NOT NULL DEFAULT '',
+  `nextAttemptAt` datetime DEFAULT NULL,
+  `createdAt` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `deliveredAt` datetime DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `webhook_deliveries_event` (`webhook_id`, `event_id`),
+  KEY `webhook_deliveries_due` (`status`, `nextAttemptAt`),
+  KEY `webhook_deliveries_event_id` (`event_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
@@ -0,0 +1,16 @@
+CREATE TABLE `audit_log` (
+  `id` varchar(36) NOT NULL,
+  `actor` varchar(255) NOT NULL,
+  `action` varchar(20) NOT NULL,
+  `entity_type` varchar(20) NOT NULL,
+  `entity_id` varchar(36) NOT NULL,
+  `before_snapshot` mediumtext DEFAULT NULL,
+  `after_snapshot` mediumtext DEFAULT NULL,
+  `ip` varchar(45) NOT NULL DEFAULT '',
+  `request_id` varchar(64) NOT NULL DEFAULT '',
+  `createdAt` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  KEY `audit_log_entity` (`entity_type`, `entity_id`, `createdAt`),
+  KEY `audit_log_actor` (`actor`, `createdAt`),
+  KEY `audit_log_created_at` (`createdAt`)
+) ENGINE=InnoDB DEFAULT CHARSET=latin1;
@@ -0,0 +1,8 @@
+ALTER TABLE `news`
+  ADD `updatedAt` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP AFTER `createdAt`;
+ALTER TABLE `tags`
+  ADD `updatedAt` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP AFTER `createdAt`;
+
+-- rows never changed since they were created
+UPDATE `news` SET `updatedAt` = COALESCE(`deletedAt`, `createdAt`);
+UPDATE `tags` SET `updatedAt` = `createdAt`;
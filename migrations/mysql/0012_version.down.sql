@@ -0,0 +1,4 @@
+ALTER TABLE `news`
+  DROP COLUMN `version`;
+ALTER TABLE `tags`
+  DROP COLUMN `version`;
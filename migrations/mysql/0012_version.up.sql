@@ -0,0 +1,4 @@
+ALTER TABLE `news`
+  ADD `version` int NOT NULL DEFAULT 1 AFTER `status`;
+ALTER TABLE `tags`
+  ADD `version` int NOT NULL DEFAULT 1 AFTER `status`;
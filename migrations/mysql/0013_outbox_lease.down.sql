@@ -0,0 +1,3 @@
+ALTER TABLE `outbox`
+  DROP COLUMN `lockedUntil`,
+  DROP COLUMN `failedAt`;
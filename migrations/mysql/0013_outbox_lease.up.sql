@@ -0,0 +1,3 @@
+ALTER TABLE `outbox`
+  ADD `lockedUntil` datetime DEFAULT NULL AFTER `attempts`,
+  ADD `failedAt` datetime DEFAULT NULL AFTER `publishedAt`;
@@ -0,0 +1,2 @@
+ALTER TABLE `webhook_deliveries`
+  ADD `lockedUntil` datetime DEFAULT NULL AFTER `nextAttemptAt`;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS news_tags;
+DROP TABLE IF EXISTS news;
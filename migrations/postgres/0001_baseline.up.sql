@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS news (
+  id varchar(36) NOT NULL PRIMARY KEY,
+  slug varchar(160) NOT NULL,
+  title varchar(120) NOT NULL,
+  content text NOT NULL,
+  status smallint NOT NULL CHECK (status IN (1, 2, 3)),
+  topic varchar(60) NOT NULL,
+  "createdAt" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "deletedAt" timestamptz DEFAULT NULL,
+  search tsvector GENERATED ALWAYS AS (to_tsvector('simple', title || ' ' || topic)) STORED
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS news_slug ON news (slug);
+CREATE INDEX IF NOT EXISTS news_search ON news USING GIN (search);
+
+CREATE TABLE IF NOT EXISTS news_tags (
+  news_id varchar(36) NOT NULL,
+  tag_id varchar(36) NOT NULL,
+  PRIMARY KEY (news_id, tag_id)
+);
+
+CREATE TABLE IF NOT EXISTS tags (
+  id varchar(36) NOT NULL PRIMARY KEY,
+  name varchar(50) NOT NULL,
+  status smallint NOT NULL CHECK (status IN (1, 2))
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS tags_name ON tags (name);
@@ -0,0 +1 @@
+ALTER TABLE tags ADD "createdAt" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP;
@@ -0,0 +1,12 @@
+CREATE TABLE comments (
+  id varchar(36) NOT NULL PRIMARY KEY,
+  news_id varchar(36) NOT NULL,
+  parent_id varchar(36) DEFAULT NULL,
+  author varchar(60) NOT NULL,
+  content text NOT NULL,
+  status smallint NOT NULL CHECK (status IN (1, 2, 3, 4)),
+  "createdAt" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX comments_news_id_status ON comments (news_id, status);
+CREATE INDEX comments_status ON comments (status);
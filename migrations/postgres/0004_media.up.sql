@@ -0,0 +1,13 @@
+CREATE TABLE media (
+  id varchar(64) NOT NULL PRIMARY KEY,
+  name varchar(255) NOT NULL,
+  path varchar(80) NOT NULL,
+  mime_type varchar(40) NOT NULL,
+  size bigint NOT NULL,
+  width integer NOT NULL DEFAULT 0,
+  height integer NOT NULL DEFAULT 0,
+  thumbnails varchar(255) NOT NULL DEFAULT '',
+  "createdAt" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE news ADD featured_image_id varchar(64) DEFAULT NULL;
@@ -0,0 +1 @@
+ALTER TABLE news ADD content_format smallint NOT NULL DEFAULT 1 CHECK (content_format IN (1, 2, 3));
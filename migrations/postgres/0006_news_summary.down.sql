@@ -0,0 +1,4 @@
+ALTER TABLE news
+  DROP COLUMN reading_time_minutes,
+  DROP COLUMN word_count,
+  DROP COLUMN excerpt;
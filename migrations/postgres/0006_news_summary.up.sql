@@ -0,0 +1,4 @@
+ALTER TABLE news
+  ADD excerpt varchar(255) NOT NULL DEFAULT '',
+  ADD word_count integer NOT NULL DEFAULT 0,
+  ADD reading_time_minutes integer NOT NULL DEFAULT 0;
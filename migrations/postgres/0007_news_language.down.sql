@@ -0,0 +1,7 @@
+DROP INDEX news_translation_group_id;
+DROP INDEX news_slug_language;
+CREATE UNIQUE INDEX news_slug ON news (slug);
+
+ALTER TABLE news
+  DROP COLUMN translation_group_id,
+  DROP COLUMN language;
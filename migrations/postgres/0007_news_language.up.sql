@@ -0,0 +1,10 @@
+ALTER TABLE news
+  ADD language varchar(5) NOT NULL DEFAULT 'id',
+  ADD translation_group_id varchar(36) NOT NULL DEFAULT '';
+
+DROP INDEX news_slug;
+CREATE UNIQUE INDEX news_slug_language ON news (slug, language);
+CREATE INDEX news_translation_group_id ON news (translation_group_id);
+
+-- every existing news starts its own translation group
+UPDATE news SET translation_group_id = id WHERE translation_group_id = '';
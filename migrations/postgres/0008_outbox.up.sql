@@ -0,0 +1,12 @@
+CREATE TABLE outbox (
+  sequence bigserial PRIMARY KEY,
+  id varchar(36) NOT NULL UNIQUE,
+  type varchar(40) NOT NULL,
+  aggregate_id varchar(64) NOT NULL,
+  payload text NOT NULL,
+  attempts integer NOT NULL DEFAULT 0,
+  "createdAt" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  "publishedAt" timestamptz DEFAULT NULL
+);
+
+CREATE INDEX outbox_pending ON outbox ("publishedAt", sequence);
@@ -0,0 +1,16 @@
+CREATE TABLE audit_log (
+  id varchar(36) NOT NULL PRIMARY KEY,
+  actor varchar(255) NOT NULL,
+  action varchar(20) NOT NULL,
+  entity_type varchar(20) NOT NULL,
+  entity_id varchar(36) NOT NULL,
+  before_snapshot text DEFAULT NULL,
+  after_snapshot text DEFAULT NULL,
+  ip varchar(45) NOT NULL DEFAULT '',
+  request_id varchar(64) NOT NULL DEFAULT '',
+  "createdAt" timestamptz NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX audit_log_entity ON audit_log (entity_type, entity_id, "createdAt");
+CREATE INDEX audit_log_actor ON audit_log (actor, "createdAt");
+CREATE INDEX audit_log_created_at ON audit_log ("createdAt");
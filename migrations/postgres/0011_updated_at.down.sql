@@ -0,0 +1,2 @@
+ALTER TABLE news DROP COLUMN "updatedAt";
+ALTER TABLE tags DROP COLUMN "updatedAt";
@@ -0,0 +1,2 @@
+ALTER TABLE outbox ADD "lockedUntil" timestamptz DEFAULT NULL;
+ALTER TABLE outbox ADD "failedAt" timestamptz DEFAULT NULL;
@@ -0,0 +1 @@
+ALTER TABLE webhook_deliveries ADD "lockedUntil" timestamptz DEFAULT NULL;
@@ -0,0 +1 @@
+ALTER TABLE `tags` DROP COLUMN `createdAt`;
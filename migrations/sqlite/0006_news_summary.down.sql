@@ -0,0 +1,3 @@
+ALTER TABLE `news` DROP COLUMN `reading_time_minutes`;
+ALTER TABLE `news` DROP COLUMN `word_count`;
+ALTER TABLE `news` DROP COLUMN `excerpt`;
@@ -0,0 +1,3 @@
+ALTER TABLE `news` ADD `excerpt` varchar(255) NOT NULL DEFAULT '';
+ALTER TABLE `news` ADD `word_count` integer NOT NULL DEFAULT 0;
+ALTER TABLE `news` ADD `reading_time_minutes` integer NOT NULL DEFAULT 0;
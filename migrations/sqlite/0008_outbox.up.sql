@@ -0,0 +1,12 @@
+CREATE TABLE `outbox` (
+  `sequence` integer PRIMARY KEY AUTOINCREMENT,
+  `id` varchar(36) NOT NULL UNIQUE,
+  `type` varchar(40) NOT NULL,
+  `aggregate_id` varchar(64) NOT NULL,
+  `payload` text NOT NULL,
+  `attempts` integer NOT NULL DEFAULT 0,
+  `createdAt` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `publishedAt` datetime DEFAULT NULL
+);
+
+CREATE INDEX `outbox_pending` ON `outbox` (`publishedAt`, `sequence`);
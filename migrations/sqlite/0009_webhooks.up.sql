@@ -0,0 +1,27 @@
+CREATE TABLE `webhooks` (
+  `id` varchar(36) NOT NULL PRIMARY KEY,
+  `url` varchar(2048) NOT NULL,
+  `event_types` varchar(255) NOT NULL DEFAULT '',
+  `secret` varchar(255) NOT NULL,
+  `status` integer NOT NULL DEFAULT 1 CHECK (`status` IN (1, 2)),
+  `createdAt` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE `webhook_deliveries` (
+  `id` varchar(36) NOT NULL PRIMARY KEY,
+  `webhook_id` varchar(36) NOT NULL,
+  `event_id` varchar(36) NOT NULL,
+  `event_type` varchar(40) NOT NULL,
+  `payload` text NOT NULL,
+  `status` integer NOT NULL DEFAULT 1 CHECK (`status` IN (1, 2, 3)),
+  `attempts` integer NOT NULL DEFAULT 0,
+  `response_code` integer NOT NULL DEFAULT 0,
+  `last_error` varchar(1024) NOT NULL DEFAULT '',
+  `nextAttemptAt` datetime DEFAULT NULL,
+  `createdAt` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  `deliveredAt` datetime DEFAULT NULL,
+  UNIQUE (`webhook_id`, `event_id`)
+);
+
+CREATE INDEX `webhook_deliveries_due` ON `webhook_deliveries` (`status`, `nextAttemptAt`);
+CREATE INDEX `webhook_deliveries_event_id` ON `webhook_deliveries` (`event_id`);
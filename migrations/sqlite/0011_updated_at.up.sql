@@ -0,0 +1,6 @@
+ALTER TABLE `news` ADD `updatedAt` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP;
+ALTER TABLE `tags` ADD `updatedAt` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP;
+
+-- rows never changed since they were created
+UPDATE `news` SET `updatedAt` = COALESCE(`deletedAt`, `createdAt`);
+UPDATE `tags` SET `updatedAt` = `createdAt`;
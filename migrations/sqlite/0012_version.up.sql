@@ -0,0 +1,2 @@
+ALTER TABLE `news` ADD `version` integer NOT NULL DEFAULT 1;
+ALTER TABLE `tags` ADD `version` integer NOT NULL DEFAULT 1;
@@ -0,0 +1,2 @@
+ALTER TABLE `outbox` DROP COLUMN `lockedUntil`;
+ALTER TABLE `outbox` DROP COLUMN `failedAt`;
@@ -0,0 +1,2 @@
+ALTER TABLE `outbox` ADD `lockedUntil` datetime DEFAULT NULL;
+ALTER TABLE `outbox` ADD `failedAt` datetime DEFAULT NULL;
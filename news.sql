@@ -1,88 +0,0 @@
--- phpMyAdmin SQL Dump
--- version 4.7.0
--- https://www.phpmyadmin.net/
---
--- Host: 127.0.0.1
--- Generation Time: Apr 02, 2022 at 08:24 AM
--- Server version: 10.1.25-MariaDB
--- PHP Version: 7.2.24
-
-SET SQL_MODE = "NO_AUTO_VALUE_ON_ZERO";
-SET AUTOCOMMIT = 0;
-START TRANSACTION;
-SET time_zone = "+00:00";
-
-
-/*!40101 SET @OLD_CHARACTER_SET_CLIENT=@@CHARACTER_SET_CLIENT */;
-/*!40101 SET @OLD_CHARACTER_SET_RESULTS=@@CHARACTER_SET_RESULTS */;
-/*!40101 SET @OLD_COLLATION_CONNECTION=@@COLLATION_CONNECTION */;
-/*!40101 SET NAMES utf8mb4 */;
-
---
--- Database: `news`
---
-
--- --------------------------------------------------------
-
---
--- Table structure for table `news`
---
-
-
-
-CREATE TABLE `news` (
-  `ID` varchar(36) NOT NULL,
-  `slug` varchar(160) NOT NULL,
-  `title` varchar(120) NOT NULL,
-  `content` text NOT NULL,
-  `status` enum('1','2','3') NOT NULL,
-  `topic` varchar(60) NOT NULL,
-  `createdAt` datetime NOT NULL DEFAULT CURRENT_TIMESTAMP,
-  `deletedAt` datetime DEFAULT NULL
-) ENGINE=InnoDB DEFAULT CHARSET=latin1;
-
---
--- Table structure for table `news_tags`
---
-
-CREATE TABLE `news_tags` (
-  `news_id` varchar(36) NOT NULL,
-  `tag_id` varchar(36) NOT NULL
-) ENGINE=InnoDB DEFAULT CHARSET=latin1;
-
---
--- Table structure for table `tags`
---
-
-CREATE TABLE `tags` (
-  `id` varchar(36) NOT NULL,
-  `name` varchar(50) NOT NULL,
-  `status` enum('1','2') NOT NULL
-) ENGINE=InnoDB DEFAULT CHARSET=latin1;
-
---
--- Indexes for table `news`
---
-ALTER TABLE `news`
-  ADD PRIMARY KEY (`ID`),
-  ADD UNIQUE KEY `slug` (`slug`);
-
---
--- Indexes for table `news_tags`
---
-ALTER TABLE `news_tags`
-  ADD PRIMARY KEY (`news_id`,`tag_id`),
-  ADD KEY `news_id` (`news_id`);
-
---
--- Indexes for table `tags`
---
-ALTER TABLE `tags`
-  ADD PRIMARY KEY (`id`),
-  ADD UNIQUE KEY `name_unique` (`name`),
-  ADD KEY `name` (`name`);
-COMMIT;
-
-/*!40101 SET CHARACTER_SET_CLIENT=@OLD_CHARACTER_SET_CLIENT */;
-/*!40101 SET CHARACTER_SET_RESULTS=@OLD_CHARACTER_SET_RESULTS */;
-/*!40101 SET COLLATION_CONNECTION=@OLD_COLLATION_CONNECTION */;
@@ -0,0 +1,17 @@
+package database
+
+import "strings"
+
+type binder interface {
+	DriverName() string
+	Rebind(query string) string
+}
+
+// Rebind adapts a query written for MySQL, with backtick quoted identifiers and
+// ? placeholders, to the driver of db. Both *sqlx.DB and *sqlx.Tx are binders.
+func Rebind(db binder, query string) string {
+	if db.DriverName() == "postgres" {
+		query = strings.ReplaceAll(query, "`", `"`)
+	}
+	return db.Rebind(query)
+}
@@ -1,36 +1,124 @@
+// Package failure holds the errors the API answers with. Every error has the
+// HTTP status of the response and a stable machine-readable code, like
+// news.not_found, clients can match on while the message is free to change.
 package failure
 
 import (
+	"errors"
 	"net/http"
+	"strings"
 )
 
 type CustomError struct {
 	Message string
-	Code    int
+	// Status is the HTTP status of the response.
+	Status int
+	// Code names the error for clients. Domain codes, like news.not_found, name
+	// one error, the generic ones, like not_found, are shared by unrelated errors.
+	Code string
+	// Violations lists the fields of a request that are not valid.
+	Violations []Violation
+	// Cause is the error behind this one, it is never shown to clients.
+	Cause error
 }
 
-func newCustomError(message string, code int) *CustomError {
-	return &CustomError{Message: message, Code: code}
+// Violation is a field of a request that is not valid.
+type Violation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// New returns an error answered with status, code and message.
+func New(status int, code string, message string) *CustomError {
+	return &CustomError{Message: message, Status: status, Code: code}
 }
 
 func (c CustomError) Error() string {
+	if c.Cause != nil {
+		return c.Message + ": " + c.Cause.Error()
+	}
 	return c.Message
 }
 
-var InternalServerError = newCustomError("internal server error", http.StatusInternalServerError)
+func (c *CustomError) Unwrap() error {
+	return c.Cause
+}
+
+// Is matches any error with the domain code of target, whatever its message or
+// cause. An error with a generic code also needs the message of target.
+func (c *CustomError) Is(target error) bool {
+	t, ok := target.(*CustomError)
+	if !ok || t.Code == "" || t.Code != c.Code {
+		return false
+	}
+	return strings.Contains(c.Code, ".") || t.Message == c.Message
+}
+
+// Wrap returns a copy of the error caused by cause.
+func (c *CustomError) Wrap(cause error) *CustomError {
+	wrapped := *c
+	wrapped.Cause = cause
+	return &wrapped
+}
+
+var InternalServerError = New(http.StatusInternalServerError, "internal_server_error", "internal server error")
 var BadRequestWithString = func(message string) error {
-	return newCustomError(message, http.StatusBadRequest)
+	return New(http.StatusBadRequest, "bad_request", message)
 }
 var Error = func(err error, code int) error {
-	return newCustomError(err.Error(), code)
+	return &CustomError{Message: err.Error(), Status: code, Code: codeOf(code)}
 }
 var NotFound = func(message string) error {
-	return newCustomError(message, http.StatusNotFound)
+	return New(http.StatusNotFound, "not_found", message)
+}
+var TooManyRequests = func(message string) error {
+	return New(http.StatusTooManyRequests, "too_many_requests", message)
+}
+var Conflict = func(message string) error {
+	return New(http.StatusConflict, "conflict", message)
 }
+var UnsupportedMediaType = func(message string) error {
+	return New(http.StatusUnsupportedMediaType, "unsupported_media_type", message)
+}
+var UnprocessableEntity = func(message string) error {
+	return New(http.StatusUnprocessableEntity, "unprocessable_entity", message)
+}
+
+// Validation returns the error of a request with violations, its message joins
+// the message of every violation.
+var Validation = func(violations []Violation) error {
+	messages := make([]string, 0, len(violations))
+	for _, violation := range violations {
+		messages = append(messages, violation.Message)
+	}
+	return &CustomError{Message: strings.Join(messages, ", "), Status: http.StatusBadRequest,
+		Code: "validation.failed", Violations: violations}
+}
+
+// ErrValidation matches every error returned by Validation.
+var ErrValidation = New(http.StatusBadRequest, "validation.failed", "validation failed")
 
-func GetCode(err error) int {
-	if f, ok := err.(*CustomError); ok {
-		return f.Code
+// As returns the CustomError in the chain of err, errors that are not one are
+// internal server errors caused by err.
+func As(err error) *CustomError {
+	var custom *CustomError
+	if errors.As(err, &custom) {
+		return custom
 	}
-	return http.StatusInternalServerError
+	return InternalServerError.Wrap(err)
+}
+
+// GetStatus returns the HTTP status of err.
+func GetStatus(err error) int {
+	return As(err).Status
+}
+
+// GetCode returns the code of err.
+func GetCode(err error) string {
+	return As(err).Code
+}
+
+// codeOf returns the code of the errors created with a bare status.
+func codeOf(status int) string {
+	return strings.ReplaceAll(strings.ToLower(http.StatusText(status)), " ", "_")
 }
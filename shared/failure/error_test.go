@@ -0,0 +1,56 @@
+package failure_test
+
+import (
+	"errors"
+	"fmt"
+	"github.com/magiconair/properties/assert"
+	"net/http"
+	"news/shared/failure"
+	"testing"
+)
+
+var errTaken = failure.New(http.StatusConflict, "tag.name_taken", "a tag with this name already exists")
+
+func TestIs(t *testing.T) {
+	cause := errors.New("duplicate entry")
+	wrapped := errTaken.Wrap(cause)
+	assert.Equal(t, errors.Is(wrapped, errTaken), true)
+	assert.Equal(t, errors.Is(wrapped, cause), true)
+	assert.Equal(t, errors.Is(fmt.Errorf("create: %w", wrapped), errTaken), true)
+	assert.Equal(t, wrapped.Error(), "a tag with this name already exists: duplicate entry")
+	// the sentinel is left untouched
+	assert.Equal(t, errTaken.Cause, nil)
+
+	assert.Equal(t, errors.Is(failure.Conflict("a tag with this name already exists"), errTaken), false)
+	// generic codes are shared by unrelated errors
+	assert.Equal(t, errors.Is(failure.NotFound("tag not found"), failure.NotFound("news not found")), false)
+	assert.Equal(t, errors.Is(failure.InternalServerError.Wrap(cause), failure.InternalServerError), true)
+}
+
+func TestAs(t *testing.T) {
+	wrapped := fmt.Errorf("create: %w", errTaken)
+	assert.Equal(t, failure.GetStatus(wrapped), http.StatusConflict)
+	assert.Equal(t, failure.GetCode(wrapped), "tag.name_taken")
+
+	// errors the API doesn't know are internal, their message is never shown
+	cause := errors.New("connection refused")
+	custom := failure.As(cause)
+	assert.Equal(t, custom.Status, http.StatusInternalServerError)
+	assert.Equal(t, custom.Code, "internal_server_error")
+	assert.Equal(t, custom.Message, "internal server error")
+	assert.Equal(t, errors.Is(custom, cause), true)
+
+	assert.Equal(t, failure.GetCode(failure.Error(errors.New("file too large"), http.StatusRequestEntityTooLarge)),
+		"request_entity_too_large")
+}
+
+func TestValidation(t *testing.T) {
+	err := failure.Validation([]failure.Violation{
+		{Field: "title", Message: "title can't be null"},
+		{Field: "topic", Message: "topic can't be null"},
+	})
+	assert.Equal(t, err.Error(), "title can't be null, topic can't be null")
+	assert.Equal(t, failure.GetStatus(err), http.StatusBadRequest)
+	assert.Equal(t, errors.Is(err, failure.ErrValidation), true)
+	assert.Equal(t, len(failure.As(err).Violations), 2)
+}
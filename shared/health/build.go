@@ -0,0 +1,36 @@
+package health
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Version, Commit and BuildTime are set when building:
+//
+//	go build -ldflags "-X news/shared/health.Version=1.2.0 -X news/shared/health.BuildTime=2022-05-01T10:00:00Z"
+//
+// Commit defaults to the vcs revision go build records.
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
+
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+func Build() BuildInfo {
+	build := BuildInfo{Version: Version, Commit: Commit, BuildTime: BuildTime, GoVersion: runtime.Version()}
+	if info, ok := debug.ReadBuildInfo(); ok && build.Commit == "" {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				build.Commit = setting.Value
+			}
+		}
+	}
+	return build
+}
@@ -0,0 +1,117 @@
+// Package health reports whether the app can serve requests, by checking its
+// dependencies like the database and redis.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+
+	// StatusReady means every dependency is up, StatusDegraded that only optional
+	// ones are down and StatusUnavailable that a required one is.
+	StatusReady       = "ready"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+)
+
+// Check is one dependency, Ping returns an error when it can't be used.
+type Check struct {
+	Name     string
+	Required bool
+	Ping     func(ctx context.Context) error
+}
+
+type CheckResult struct {
+	Status    string  `json:"status"`
+	Required  bool    `json:"required"`
+	LatencyMs float64 `json:"latency_ms"`
+	// Error tells why the dependency is down, it may name hosts and is never sent.
+	Error string `json:"-"`
+}
+
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+	Build  BuildInfo              `json:"build"`
+}
+
+// Ready reports whether the report allows to serve requests.
+func (r *Report) Ready() bool {
+	return r.Status != StatusUnavailable
+}
+
+type Checker struct {
+	checks  []Check
+	timeout time.Duration
+	build   BuildInfo
+}
+
+// DefaultTimeout bounds every ping when no timeout is configured.
+const DefaultTimeout = time.Second
+
+// NewChecker pings every check for at most timeout, DefaultTimeout when it is not positive.
+func NewChecker(checks []Check, timeout time.Duration, build BuildInfo) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &Checker{checks: checks, timeout: timeout, build: build}
+}
+
+// Check pings every dependency at the same time.
+func (c *Checker) Check(ctx context.Context) *Report {
+	report := &Report{Status: StatusReady, Checks: map[string]CheckResult{}, Build: c.build}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, check := range c.checks {
+		wg.Add(1)
+		go func(check Check) {
+			defer wg.Done()
+			result := c.ping(ctx, check)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[check.Name] = result
+		}(check)
+	}
+	wg.Wait()
+
+	for _, result := range report.Checks {
+		if result.Status == StatusUp {
+			continue
+		}
+		if result.Required {
+			report.Status = StatusUnavailable
+			break
+		}
+		report.Status = StatusDegraded
+	}
+	return report
+}
+
+func (c *Checker) ping(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+	start := time.Now()
+	errs := make(chan error, 1)
+	// a ping that ignores ctx still can't hold the report past the timeout
+	go func() {
+		errs <- check.Ping(ctx)
+	}()
+	var err error
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	result := CheckResult{Status: StatusUp, Required: check.Required,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		result.Status = StatusDown
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,71 @@
+package health_test
+
+import (
+	"context"
+	"errors"
+	"github.com/magiconair/properties/assert"
+	"news/shared/health"
+	"testing"
+	"time"
+)
+
+func up(ctx context.Context) error {
+	return nil
+}
+
+func down(ctx context.Context) error {
+	return errors.New("connection refused")
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		checks []health.Check
+		status string
+		ready  bool
+	}{
+		{"no dependency", nil, health.StatusReady, true},
+		{"every dependency up", []health.Check{{Name: "database", Required: true, Ping: up},
+			{Name: "redis", Ping: up}}, health.StatusReady, true},
+		{"optional dependency down", []health.Check{{Name: "database", Required: true, Ping: up},
+			{Name: "redis", Ping: down}}, health.StatusDegraded, true},
+		{"required dependency down", []health.Check{{Name: "database", Required: true, Ping: down},
+			{Name: "redis", Ping: up}}, health.StatusUnavailable, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			report := health.NewChecker(test.checks, time.Second, health.Build()).Check(context.Background())
+			assert.Equal(t, report.Status, test.status)
+			assert.Equal(t, report.Ready(), test.ready)
+			assert.Equal(t, len(report.Checks), len(test.checks))
+		})
+	}
+}
+
+func TestCheckTimeout(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	hanging := func(ctx context.Context) error {
+		<-block
+		return nil
+	}
+	start := time.Now()
+	report := health.NewChecker([]health.Check{{Name: "redis", Required: true, Ping: hanging}},
+		50*time.Millisecond, health.Build()).Check(context.Background())
+	assert.Equal(t, time.Since(start) < time.Second, true)
+	assert.Equal(t, report.Status, health.StatusUnavailable)
+	assert.Equal(t, report.Checks["redis"].Status, health.StatusDown)
+	assert.Equal(t, report.Checks["redis"].Error, context.DeadlineExceeded.Error())
+}
+
+func TestDefaultTimeout(t *testing.T) {
+	ping := func(ctx context.Context) error {
+		deadline, ok := ctx.Deadline()
+		assert.Equal(t, ok, true)
+		assert.Equal(t, time.Until(deadline) > health.DefaultTimeout/2, true)
+		return nil
+	}
+	report := health.NewChecker([]health.Check{{Name: "database", Required: true, Ping: ping}}, 0,
+		health.Build()).Check(context.Background())
+	assert.Equal(t, report.Status, health.StatusReady)
+}
@@ -0,0 +1,40 @@
+// Package idempotency remembers the response of a request under the key the
+// client sent with it, so a retry of the request gets the same response
+// instead of running again.
+package idempotency
+
+import (
+	"context"
+	"time"
+)
+
+// Record is what a key holds: the fingerprint of the request that claimed it
+// and, once the request is done, its response.
+type Record struct {
+	Fingerprint string `json:"fingerprint"`
+	// Token tells the claim of a request in progress from the claims of its
+	// retries, the record of a response has none.
+	Token string `json:"token,omitempty"`
+	// Status is 0 while the request is in progress.
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// Done reports whether the record holds a response.
+func (r Record) Done() bool {
+	return r.Status != 0
+}
+
+type Store interface {
+	// Claim takes key for the request with fingerprint for lock under token, a
+	// value no other request uses. It returns nil then, or the record of the key
+	// when it is already taken.
+	Claim(ctx context.Context, key string, fingerprint string, token string, lock time.Duration) (*Record, error)
+	// Save keeps the response of the request that claimed key for ttl.
+	Save(ctx context.Context, key string, record Record, ttl time.Duration) error
+	// Release frees key while it holds the claim made under token, the request
+	// may run again with it. A claim taken over once the lock was over, or a
+	// saved response, is left alone.
+	Release(ctx context.Context, key string, token string) error
+}
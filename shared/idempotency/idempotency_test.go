@@ -0,0 +1,125 @@
+package idempotency_test
+
+import (
+	"context"
+	"github.com/go-redis/redis"
+	"github.com/magiconair/properties/assert"
+	"news/shared/Date"
+	"news/shared/IDGEN"
+	"news/shared/idempotency"
+	"os"
+	"testing"
+	"time"
+)
+
+// storeContract checks the records a store keeps, newStore must return a store
+// without any record. Expiry is only checked by the memory store, redis keeps
+// its own clock.
+func storeContract(t *testing.T, newStore func(t *testing.T) idempotency.Store, clock bool) {
+	ctx := context.Background()
+
+	t.Run("claim, save and replay", func(t *testing.T) {
+		store := newStore(t)
+		record, err := store.Claim(ctx, "key1", "fingerprint1", "token1", time.Minute)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, record == nil, true)
+
+		// a concurrent retry finds the request in progress
+		record, err = store.Claim(ctx, "key1", "fingerprint1", "token2", time.Minute)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, *record, idempotency.Record{Fingerprint: "fingerprint1", Token: "token1"})
+		assert.Equal(t, record.Done(), false)
+
+		response := idempotency.Record{Fingerprint: "fingerprint1", Status: 201, ContentType: "application/json",
+			Body: []byte(`{"status":201}`)}
+		err = store.Save(ctx, "key1", response, time.Hour)
+		assert.Equal(t, err, nil)
+		record, err = store.Claim(ctx, "key1", "fingerprint2", "token3", time.Minute)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, *record, response)
+		assert.Equal(t, record.Done(), true)
+		// the request that lost its claim doesn't drop the response
+		err = store.Release(ctx, "key1", "token1")
+		assert.Equal(t, err, nil)
+		record, err = store.Claim(ctx, "key1", "fingerprint1", "token4", time.Minute)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, record.Done(), true)
+
+		record, err = store.Claim(ctx, "key2", "fingerprint1", "token5", time.Minute)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, record == nil, true)
+	})
+
+	t.Run("release", func(t *testing.T) {
+		store := newStore(t)
+		_, err := store.Claim(ctx, "key1", "fingerprint1", "token1", time.Minute)
+		assert.Equal(t, err, nil)
+		// only the claim made under the token is released
+		err = store.Release(ctx, "key1", "token2")
+		assert.Equal(t, err, nil)
+		record, err := store.Claim(ctx, "key1", "fingerprint2", "token2", time.Minute)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, record.Token, "token1")
+		err = store.Release(ctx, "key1", "token1")
+		assert.Equal(t, err, nil)
+		record, err = store.Claim(ctx, "key1", "fingerprint2", "token2", time.Minute)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, record == nil, true)
+	})
+
+	if !clock {
+		return
+	}
+	t.Run("expiry", func(t *testing.T) {
+		now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+		Date.Now = func() time.Time {
+			return now
+		}
+		defer func() { Date.Now = time.Now }()
+		store := newStore(t)
+
+		_, err := store.Claim(ctx, "key1", "fingerprint1", "token1", time.Minute)
+		assert.Equal(t, err, nil)
+		// a lost request frees its key once the lock is over
+		now = now.Add(time.Minute)
+		record, err := store.Claim(ctx, "key1", "fingerprint1", "token2", time.Minute)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, record == nil, true)
+		// and can't release the claim of the retry that took the key over
+		err = store.Release(ctx, "key1", "token1")
+		assert.Equal(t, err, nil)
+		record, err = store.Claim(ctx, "key1", "fingerprint1", "token3", time.Minute)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, record.Token, "token2")
+
+		err = store.Save(ctx, "key1", idempotency.Record{Fingerprint: "fingerprint1", Status: 201}, time.Hour)
+		assert.Equal(t, err, nil)
+		now = now.Add(59 * time.Minute)
+		record, err = store.Claim(ctx, "key1", "fingerprint1", "token3", time.Minute)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, record.Status, 201)
+		now = now.Add(time.Minute)
+		record, err = store.Claim(ctx, "key1", "fingerprint1", "token3", time.Minute)
+		assert.Equal(t, err, nil)
+		assert.Equal(t, record == nil, true)
+	})
+}
+
+func TestMemoryStore(t *testing.T) {
+	storeContract(t, func(t *testing.T) idempotency.Store {
+		return idempotency.NewMemoryStore()
+	}, true)
+}
+
+// TestRedisStore runs when TEST_REDIS_ADDR is set, keys are prefixed with a new id on every run.
+func TestRedisStore(t *testing.T) {
+	addr := os.Getenv("TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("TEST_REDIS_ADDR is not set")
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+	storeContract(t, func(t *testing.T) idempotency.Store {
+		return idempotency.NewRedisStoreWithPrefix(client, "test:"+IDGEN.NewUUID()+":")
+	}, false)
+}
@@ -0,0 +1,67 @@
+package idempotency
+
+import (
+	"context"
+	"news/shared/Date"
+	"sync"
+	"time"
+)
+
+type entry struct {
+	record  Record
+	expires time.Time
+}
+
+// memoryStore keeps the records in the process, every instance of the app has its own.
+type memoryStore struct {
+	mu        sync.Mutex
+	entries   map[string]entry
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *memoryStore {
+	return &memoryStore{entries: map[string]entry{}}
+}
+
+func (s *memoryStore) Claim(ctx context.Context, key string, fingerprint string, token string,
+	lock time.Duration) (*Record, error) {
+	now := Date.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+	if stored, ok := s.entries[key]; ok && now.Before(stored.expires) {
+		record := stored.record
+		return &record, nil
+	}
+	s.entries[key] = entry{record: Record{Fingerprint: fingerprint, Token: token}, expires: now.Add(lock)}
+	return nil, nil
+}
+
+func (s *memoryStore) Save(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = entry{record: record, expires: Date.Now().Add(ttl)}
+	return nil
+}
+
+func (s *memoryStore) Release(ctx context.Context, key string, token string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if stored, ok := s.entries[key]; ok && stored.record.Token == token && !stored.record.Done() {
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+// sweep drops the expired records, at most once a minute.
+func (s *memoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < time.Minute {
+		return
+	}
+	s.lastSweep = now
+	for key, stored := range s.entries {
+		if !now.Before(stored.expires) {
+			delete(s.entries, key)
+		}
+	}
+}
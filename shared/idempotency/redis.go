@@ -0,0 +1,77 @@
+package idempotency
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/go-redis/redis"
+	"time"
+)
+
+// claimScript sets the key when it is free and returns nil, or returns the
+// record the key holds.
+var claimScript = redis.NewScript(`
+if redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2]) then
+  return false
+end
+return redis.call('GET', KEYS[1])
+`)
+
+// releaseScript deletes the key while it holds the claim made under the token,
+// reading and deleting in one script so no other claim comes in between.
+var releaseScript = redis.NewScript(`
+local value = redis.call('GET', KEYS[1])
+if value and cjson.decode(value)['token'] == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// redisStore shares the records between every instance using the same redis.
+type redisStore struct {
+	redis  *redis.Client
+	prefix string
+}
+
+func NewRedisStore(client *redis.Client) *redisStore {
+	return NewRedisStoreWithPrefix(client, "idempotency:")
+}
+
+// NewRedisStoreWithPrefix keeps the records under keys starting with prefix.
+func NewRedisStoreWithPrefix(client *redis.Client, prefix string) *redisStore {
+	return &redisStore{redis: client, prefix: prefix}
+}
+
+func (s *redisStore) Claim(ctx context.Context, key string, fingerprint string, token string,
+	lock time.Duration) (*Record, error) {
+	claim, err := json.Marshal(Record{Fingerprint: fingerprint, Token: token})
+	if err != nil {
+		return nil, err
+	}
+	value, err := claimScript.Run(s.redis.WithContext(ctx), []string{s.prefix + key}, claim,
+		lock.Milliseconds()).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	text, _ := value.(string)
+	var record Record
+	err = json.Unmarshal([]byte(text), &record)
+	if err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (s *redisStore) Save(ctx context.Context, key string, record Record, ttl time.Duration) error {
+	value, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	return s.redis.WithContext(ctx).Set(s.prefix+key, value, ttl).Err()
+}
+
+func (s *redisStore) Release(ctx context.Context, key string, token string) error {
+	return releaseScript.Run(s.redis.WithContext(ctx), []string{s.prefix + key}, token).Err()
+}
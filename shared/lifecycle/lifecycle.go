@@ -0,0 +1,143 @@
+// Package lifecycle runs the servers and background workers of the app until it
+// is asked to stop, then stops them in order: servers are drained first so no
+// new work comes in, workers are stopped next and the clients they used, like
+// the database and redis, are closed last.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/rs/zerolog/log"
+	"sync"
+	"time"
+)
+
+type server struct {
+	name  string
+	serve func() error
+	stop  func() error
+}
+
+type closer struct {
+	name  string
+	close func() error
+}
+
+type Manager struct {
+	timeout  time.Duration
+	ctx      context.Context
+	cancel   context.CancelFunc
+	workers  sync.WaitGroup
+	servers  []server
+	closers  []closer
+	shutdown sync.Once
+	err      error
+}
+
+// NewManager gives every shutdown at most timeout to stop the servers and workers.
+func NewManager(timeout time.Duration) *Manager {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{timeout: timeout, ctx: ctx, cancel: cancel}
+}
+
+// Go starts a background worker, its ctx is done when the manager shuts down
+// and it must return soon after.
+func (m *Manager) Go(name string, worker func(ctx context.Context)) {
+	m.workers.Add(1)
+	go func() {
+		defer m.workers.Done()
+		worker(m.ctx)
+		log.Info().Str("worker", name).Msg("worker stopped")
+	}()
+}
+
+// Serve registers a server started by Run, serve blocks until stop has drained it.
+func (m *Manager) Serve(name string, serve func() error, stop func() error) {
+	m.servers = append(m.servers, server{name: name, serve: serve, stop: stop})
+}
+
+// Close registers a client closed on shutdown, clients are closed in the order
+// they were registered.
+func (m *Manager) Close(name string, close func() error) {
+	m.closers = append(m.closers, closer{name: name, close: close})
+}
+
+// Run starts the servers and shuts everything down once ctx is done or a
+// server fails, it returns the error of the failing server or of the shutdown.
+func (m *Manager) Run(ctx context.Context) error {
+	errs := make(chan error, len(m.servers))
+	for _, s := range m.servers {
+		go func(s server) {
+			err := s.serve()
+			if err != nil {
+				err = fmt.Errorf("%s: %w", s.name, err)
+			}
+			errs <- err
+		}(s)
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+		log.Info().Msg("shutting down")
+	case err = <-errs:
+		log.Error().Err(err).Msg("server stopped, shutting down")
+	}
+	shutdownErr := m.Shutdown()
+	if err != nil {
+		return err
+	}
+	return shutdownErr
+}
+
+// Shutdown drains the servers, stops the workers and closes the clients. Servers
+// and workers share the timeout, clients are closed even when it ran out. Only
+// the first call does anything, later ones return its error.
+func (m *Manager) Shutdown() error {
+	m.shutdown.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+		defer cancel()
+		for _, s := range m.servers {
+			m.fail(s.name, wait(ctx, s.stop))
+		}
+		m.cancel()
+		m.fail("workers", wait(ctx, func() error {
+			m.workers.Wait()
+			return nil
+		}))
+		for _, c := range m.closers {
+			m.fail(c.name, c.close())
+		}
+		log.Info().Msg("shut down")
+	})
+	return m.err
+}
+
+// fail logs the error of a shutdown step and keeps the first one.
+func (m *Manager) fail(name string, err error) {
+	if err == nil {
+		return
+	}
+	log.Error().Err(err).Str("step", name).Msg("shutdown")
+	if m.err == nil {
+		m.err = fmt.Errorf("%s: %w", name, err)
+	}
+}
+
+// wait runs step and gives up once ctx is done, the step keeps running in the background.
+func wait(ctx context.Context, step func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- step()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return errors.New("timed out")
+	}
+}
@@ -0,0 +1,104 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"github.com/magiconair/properties/assert"
+	"news/shared/lifecycle"
+	"sync"
+	"testing"
+	"time"
+)
+
+// steps records the order in which the manager stops things.
+type steps struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (s *steps) add(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.names = append(s.names, name)
+}
+
+func (s *steps) get() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.names...)
+}
+
+func TestRun(t *testing.T) {
+	var order steps
+	manager := lifecycle.NewManager(time.Second)
+	stopped := make(chan struct{})
+	manager.Serve("http", func() error {
+		<-stopped
+		return nil
+	}, func() error {
+		order.add("http")
+		close(stopped)
+		return nil
+	})
+	manager.Go("relay", func(ctx context.Context) {
+		<-ctx.Done()
+		order.add("relay")
+	})
+	manager.Close("database", func() error {
+		order.add("database")
+		return nil
+	})
+	manager.Close("redis", func() error {
+		order.add("redis")
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := manager.Run(ctx)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, order.get(), []string{"http", "relay", "database", "redis"})
+
+	// a second shutdown stops nothing again
+	assert.Equal(t, manager.Shutdown(), nil)
+	assert.Equal(t, len(order.get()), 4)
+}
+
+func TestRunServerFails(t *testing.T) {
+	manager := lifecycle.NewManager(time.Second)
+	closed := false
+	manager.Serve("http", func() error {
+		return errors.New("address already in use")
+	}, func() error {
+		return nil
+	})
+	manager.Close("database", func() error {
+		closed = true
+		return nil
+	})
+
+	err := manager.Run(context.Background())
+	assert.Equal(t, err.Error(), "http: address already in use")
+	assert.Equal(t, closed, true)
+}
+
+func TestShutdownTimeout(t *testing.T) {
+	manager := lifecycle.NewManager(50 * time.Millisecond)
+	block := make(chan struct{})
+	defer close(block)
+	manager.Go("stuck", func(ctx context.Context) {
+		<-block
+	})
+	closed := false
+	manager.Close("database", func() error {
+		closed = true
+		return nil
+	})
+
+	start := time.Now()
+	err := manager.Shutdown()
+	assert.Equal(t, time.Since(start) < time.Second, true)
+	assert.Equal(t, err.Error(), "workers: timed out")
+	// clients are closed even when a worker is stuck
+	assert.Equal(t, closed, true)
+}
@@ -1,27 +1,62 @@
 package logger
 
 import (
+	"context"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 	"os"
-	"time"
 )
 
-func InitLogger() {
-	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
-	zerolog.SetGlobalLevel(zerolog.TraceLevel)
+// ContextKey holds the logger of a request. Handlers pass c.Context() on and
+// fasthttp answers Value for string keys with the values set by c.Locals, so
+// the middleware stores it with c.Locals(logger.ContextKey, requestLogger).
+const ContextKey = "logger"
 
-	output := zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: time.RFC3339}
-	// format the output as needed here
+// InitLogger writes JSON lines to stdout from level up, info when level is not valid.
+func InitLogger(level string) {
+	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
+	log.Logger = zerolog.New(os.Stdout).With().Timestamp().Logger()
 
-	log.Logger = log.Output(output)
+	parsed, err := zerolog.ParseLevel(level)
+	if err != nil || level == "" {
+		log.Warn().Str("level", level).Msg("Log level not valid, using info.")
+		parsed = zerolog.InfoLevel
+	}
+	zerolog.SetGlobalLevel(parsed)
 	log.Trace().Msg("Zerolog initialized.")
-	level, _ := zerolog.ParseLevel("info")
-	zerolog.SetGlobalLevel(level)
 }
 
-// ErrorWithStack logs and error and its stack trace with custom formatting.
-func ErrorWithStack(err error) {
-	log.Error().Msgf("%+v", errors.WithStack(err))
+// WithContext returns a copy of ctx carrying the logger, for callers outside fiber.
+func WithContext(ctx context.Context, logger *zerolog.Logger) context.Context {
+	return context.WithValue(ctx, ContextKey, logger)
+}
+
+// Ctx returns the logger of the request ctx belongs to, or the global logger.
+func Ctx(ctx context.Context) *zerolog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(ContextKey).(*zerolog.Logger); ok {
+			return logger
+		}
+	}
+	return &log.Logger
+}
+
+// AddStr adds a field to the rest of the lines logged for the request, like the
+// access log. It does nothing outside a request.
+func AddStr(ctx context.Context, key string, value string) {
+	if ctx == nil {
+		return
+	}
+	if logger, ok := ctx.Value(ContextKey).(*zerolog.Logger); ok {
+		logger.UpdateContext(func(c zerolog.Context) zerolog.Context {
+			return c.Str(key, value)
+		})
+	}
+}
+
+// ErrorWithStack logs and error and its stack trace with custom formatting,
+// with the fields of the request logger in ctx.
+func ErrorWithStack(ctx context.Context, err error) {
+	Ctx(ctx).Error().Msgf("%+v", errors.WithStack(err))
 }
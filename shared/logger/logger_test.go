@@ -0,0 +1,48 @@
+package logger_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/magiconair/properties/assert"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"news/shared/logger"
+	"strings"
+	"testing"
+)
+
+func TestRequestLogger(t *testing.T) {
+	var output bytes.Buffer
+	requestLogger := zerolog.New(&output).With().Str("request_id", "request-1").Logger()
+	ctx := logger.WithContext(context.Background(), &requestLogger)
+
+	logger.AddStr(ctx, "cache", "hit")
+	logger.ErrorWithStack(ctx, errors.New("query failed"))
+
+	var line map[string]string
+	err := json.Unmarshal(output.Bytes(), &line)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, line["level"], "error")
+	assert.Equal(t, line["request_id"], "request-1")
+	assert.Equal(t, line["cache"], "hit")
+	assert.Equal(t, strings.HasPrefix(line["message"], "query failed"), true)
+}
+
+func TestGlobalLogger(t *testing.T) {
+	var output bytes.Buffer
+	global := log.Logger
+	log.Logger = zerolog.New(&output)
+	defer func() { log.Logger = global }()
+
+	// outside a request fields are not added to the global logger
+	logger.AddStr(context.Background(), "cache", "hit")
+	logger.ErrorWithStack(context.Background(), errors.New("query failed"))
+
+	var line map[string]string
+	err := json.Unmarshal(output.Bytes(), &line)
+	assert.Equal(t, err, nil)
+	assert.Equal(t, line["cache"], "")
+	assert.Equal(t, line["request_id"], "")
+}
@@ -0,0 +1,51 @@
+package markup
+
+import (
+	"bytes"
+	"html"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	goldmarkhtml "github.com/yuin/goldmark/renderer/html"
+)
+
+// policy is the allow-list every rendered document goes through, it keeps
+// formatting, links, images and tables and drops scripts, styles and event handlers.
+var policy = bluemonday.UGCPolicy()
+
+var markdown = goldmark.New(
+	goldmark.WithExtensions(extension.GFM),
+	goldmark.WithRendererOptions(goldmarkhtml.WithUnsafe()),
+)
+
+// Plain escapes text and turns blank line separated blocks into paragraphs.
+func Plain(content string) string {
+	var buf strings.Builder
+	for _, paragraph := range strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		buf.WriteString("<p>")
+		buf.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>"))
+		buf.WriteString("</p>\n")
+	}
+	return buf.String()
+}
+
+// Markdown renders CommonMark with GitHub extensions, raw HTML inside the
+// markdown is sanitized like any other HTML content.
+func Markdown(content string) string {
+	var buf bytes.Buffer
+	err := markdown.Convert([]byte(content), &buf)
+	if err != nil {
+		return Plain(content)
+	}
+	return HTML(buf.String())
+}
+
+func HTML(content string) string {
+	return policy.Sanitize(content)
+}
@@ -0,0 +1,105 @@
+package markup_test
+
+import (
+	"github.com/magiconair/properties/assert"
+	"news/shared/markup"
+	"testing"
+)
+
+func TestMarkup(t *testing.T) {
+	sliceTest := []struct {
+		testTitle string
+		render    func(string) string
+		input     string
+		expected  string
+	}{
+		{
+			testTitle: "plain paragraphs",
+			render:    markup.Plain,
+			input:     "first line\nsecond line\n\nnext paragraph",
+			expected:  "<p>first line<br>second line</p>\n<p>next paragraph</p>\n",
+		},
+		{
+			testTitle: "plain escapes html",
+			render:    markup.Plain,
+			input:     "<b>bold</b> & more",
+			expected:  "<p>&lt;b&gt;bold&lt;/b&gt; &amp; more</p>\n",
+		},
+		{
+			testTitle: "markdown",
+			render:    markup.Markdown,
+			input:     "# Judul\n\nteks **tebal**",
+			expected:  "<h1>Judul</h1>\n<p>teks <strong>tebal</strong></p>\n",
+		},
+		{
+			testTitle: "markdown drops script",
+			render:    markup.Markdown,
+			input:     "teks\n\n<script>alert(1)</script>",
+			expected:  "<p>teks</p>\n",
+		},
+		{
+			testTitle: "markdown drops javascript links",
+			render:    markup.Markdown,
+			input:     "[klik](javascript:alert(1))",
+			expected:  "<p>klik</p>\n",
+		},
+		{
+			testTitle: "html drops event handlers",
+			render:    markup.HTML,
+			input:     `<p onclick="alert(1)">teks <a href="https://example.com">link</a></p>`,
+			expected:  `<p>teks <a href="https://example.com" rel="nofollow">link</a></p>`,
+		},
+	}
+
+	for _, test := range sliceTest {
+		t.Run(test.testTitle, func(t *testing.T) {
+			assert.Equal(t, test.render(test.input), test.expected)
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	t.Run("testText", func(t *testing.T) {
+		actual := markup.Text("<h1>Judul</h1>\n<p>teks <strong>tebal</strong> &amp; lain</p><p>lagi</p>")
+		assert.Equal(t, actual, "Judul teks tebal & lain lagi")
+	})
+
+	t.Run("testExcerpt", func(t *testing.T) {
+		sliceTest := []struct {
+			testTitle string
+			input     string
+			maxLength int
+			expected  string
+		}{
+			{
+				testTitle: "short text",
+				input:     "Satu kalimat saja.",
+				maxLength: 50,
+				expected:  "Satu kalimat saja.",
+			},
+			{
+				testTitle: "cut at sentence",
+				input:     "Kalimat pertama. Kalimat kedua! Kalimat ketiga yang panjang sekali.",
+				maxLength: 40,
+				expected:  "Kalimat pertama. Kalimat kedua!",
+			},
+			{
+				testTitle: "decimal is not a sentence end",
+				input:     "Harga naik 2.5 persen hari ini dan besok",
+				maxLength: 20,
+				expected:  "Harga naik 2.5…",
+			},
+			{
+				testTitle: "long sentence cut at word",
+				input:     "Kalimat pertama yang sangat panjang, tanpa titik",
+				maxLength: 37,
+				expected:  "Kalimat pertama yang sangat panjang…",
+			},
+		}
+		for _, test := range sliceTest {
+			t.Run(test.testTitle, func(t *testing.T) {
+				assert.Equal(t, markup.Excerpt(test.input, test.maxLength), test.expected)
+			})
+		}
+	})
+}
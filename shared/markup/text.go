@@ -0,0 +1,55 @@
+package markup
+
+import (
+	"html"
+	"strings"
+	"unicode"
+
+	"github.com/microcosm-cc/bluemonday"
+)
+
+var strip = bluemonday.StrictPolicy().AddSpaceWhenStrippingTag(true)
+
+// Text strips every tag from rendered HTML and collapses whitespace.
+func Text(content string) string {
+	return strings.Join(strings.Fields(html.UnescapeString(strip.Sanitize(content))), " ")
+}
+
+// Excerpt returns the leading sentences of text that fit in maxLength characters.
+// When even the first sentence is too long it is cut at a word boundary and
+// ends with an ellipsis.
+func Excerpt(text string, maxLength int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text
+	}
+
+	end := 0
+	for i := 0; i < maxLength; i++ {
+		if isSentenceEnd(runes, i) {
+			end = i + 1
+		}
+	}
+	if end > 0 {
+		return string(runes[:end])
+	}
+
+	end = maxLength
+	for end > 0 && !unicode.IsSpace(runes[end]) {
+		end--
+	}
+	if end == 0 {
+		end = maxLength
+	}
+	return strings.TrimRightFunc(string(runes[:end]), func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + "…"
+}
+
+func isSentenceEnd(runes []rune, i int) bool {
+	switch runes[i] {
+	case '.', '!', '?':
+		return i+1 >= len(runes) || unicode.IsSpace(runes[i+1])
+	}
+	return false
+}
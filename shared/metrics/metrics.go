@@ -0,0 +1,63 @@
+// Package metrics holds the Prometheus metrics of the app, they are exposed on
+// /metrics in the text format.
+package metrics
+
+import (
+	"database/sql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"time"
+)
+
+// Registry only holds the metrics of this package and the go and process
+// collectors, so tests can build the app several times.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests is labelled with the route pattern, not the path, to keep the number of series bounded.
+	HTTPRequests = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Duration of the HTTP requests by method, route and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "news_cache_requests_total",
+		Help: "News cache lookups by key family (all, topic, status, slug) and result (hit, miss).",
+	}, []string{"family", "result"})
+
+	QueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "repository_query_duration_seconds",
+		Help:    "Duration of the repository calls by repository and method.",
+		Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"repository", "method"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequests,
+		CacheRequests,
+		QueryDuration,
+	)
+}
+
+// RegisterDB exposes the connection pool statistics of db, labelled with name.
+func RegisterDB(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// Cache counts a lookup of the news cache.
+func Cache(family string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	CacheRequests.WithLabelValues(family, result).Inc()
+}
+
+// ObserveQuery records a repository call started at start, call it deferred.
+func ObserveQuery(repository string, method string, start time.Time) {
+	QueryDuration.WithLabelValues(repository, method).Observe(time.Since(start).Seconds())
+}
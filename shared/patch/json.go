@@ -0,0 +1,321 @@
+package patch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Operation is one operation of a JSON patch, Path and From are JSON pointers.
+type Operation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+
+	path  []string
+	from  []string
+	value interface{}
+}
+
+// JSONPatch is a RFC 6902 JSON patch, its operations are applied in order and
+// the document is left untouched when one of them fails.
+type JSONPatch []Operation
+
+func ParseJSONPatch(body []byte) (JSONPatch, error) {
+	var operations JSONPatch
+	err := decode(body, &operations)
+	if err != nil {
+		return nil, invalid(err.Error())
+	}
+	for i := range operations {
+		err = operations[i].parse()
+		if err != nil {
+			return nil, invalid(fmt.Sprintf("operation %d: %s", i, err))
+		}
+	}
+	return operations, nil
+}
+
+func (o *Operation) parse() (err error) {
+	if o.Path == nil {
+		return errors.New("path is missing")
+	}
+	o.path, err = pointer(*o.Path)
+	if err != nil {
+		return
+	}
+	switch o.Op {
+	case "add", "replace", "test":
+		if o.Value == nil {
+			return errors.New("value is missing")
+		}
+		return decode(o.Value, &o.value)
+	case "move", "copy":
+		if o.From == nil {
+			return errors.New("from is missing")
+		}
+		o.from, err = pointer(*o.From)
+		return
+	case "remove":
+		return nil
+	}
+	return fmt.Errorf("op %q not supported", o.Op)
+}
+
+func (p JSONPatch) Apply(doc []byte) ([]byte, error) {
+	var target interface{}
+	err := decode(doc, &target)
+	if err != nil {
+		return nil, err
+	}
+	for i, operation := range p {
+		target, err = operation.apply(target)
+		if err != nil {
+			return nil, unapplicable(fmt.Sprintf("operation %d, %s %s: %s", i, operation.Op, *operation.Path, err))
+		}
+	}
+	return json.Marshal(target)
+}
+
+func (o Operation) apply(doc interface{}) (interface{}, error) {
+	switch o.Op {
+	case "add":
+		return add(doc, o.path, clone(o.value))
+	case "remove":
+		doc, _, err := remove(doc, o.path)
+		return doc, err
+	case "replace":
+		doc, _, err := remove(doc, o.path)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, o.path, clone(o.value))
+	case "move":
+		if len(o.from) < len(o.path) && reflect.DeepEqual(o.from, o.path[:len(o.from)]) {
+			return nil, fmt.Errorf("%s can't be moved into itself", *o.From)
+		}
+		doc, value, err := remove(doc, o.from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, o.path, value)
+	case "copy":
+		value, err := get(doc, o.from)
+		if err != nil {
+			return nil, err
+		}
+		return add(doc, o.path, clone(value))
+	case "test":
+		value, err := get(doc, o.path)
+		if err != nil {
+			return nil, err
+		}
+		if !equal(value, o.value) {
+			return nil, errors.New("value is not the tested one")
+		}
+		return doc, nil
+	}
+	return nil, fmt.Errorf("op %q not supported", o.Op)
+}
+
+// pointer splits a RFC 6901 JSON pointer in its unescaped reference tokens.
+func pointer(text string) ([]string, error) {
+	if text == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(text, "/") {
+		return nil, fmt.Errorf("%q is not a JSON pointer", text)
+	}
+	tokens := strings.Split(text[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+	}
+	return tokens, nil
+}
+
+func get(doc interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		var err error
+		doc, err = child(doc, token)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+// add returns doc with value added at path, the member of an object is
+// replaced and the element of an array is inserted.
+func add(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	return change(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch parent := parent.(type) {
+		case map[string]interface{}:
+			parent[token] = value
+			return parent, nil
+		case []interface{}:
+			i := len(parent)
+			if token != "-" {
+				var err error
+				i, err = index(token, len(parent)+1)
+				if err != nil {
+					return nil, err
+				}
+			}
+			parent = append(parent, nil)
+			copy(parent[i+1:], parent[i:])
+			parent[i] = value
+			return parent, nil
+		}
+		return nil, fmt.Errorf("%q is not in an object or an array", token)
+	}, func() (interface{}, error) {
+		return value, nil
+	})
+}
+
+// remove returns doc without the value at path, and the value.
+func remove(doc interface{}, path []string) (result interface{}, removed interface{}, err error) {
+	result, err = change(doc, path, func(parent interface{}, token string) (interface{}, error) {
+		switch parent := parent.(type) {
+		case map[string]interface{}:
+			value, ok := parent[token]
+			if !ok {
+				return nil, fmt.Errorf("member %q is missing", token)
+			}
+			removed = value
+			delete(parent, token)
+			return parent, nil
+		case []interface{}:
+			i, err := index(token, len(parent))
+			if err != nil {
+				return nil, err
+			}
+			removed = parent[i]
+			return append(parent[:i:i], parent[i+1:]...), nil
+		}
+		return nil, fmt.Errorf("%q is not in an object or an array", token)
+	}, func() (interface{}, error) {
+		removed = doc
+		return nil, nil
+	})
+	return
+}
+
+// change returns doc with the parent of the last token of path replaced by the
+// result of fn, or with the result of root when path is the whole document.
+func change(doc interface{}, path []string, fn func(parent interface{}, token string) (interface{}, error),
+	root func() (interface{}, error)) (interface{}, error) {
+	if len(path) == 0 {
+		return root()
+	}
+	if len(path) == 1 {
+		return fn(doc, path[0])
+	}
+	value, err := child(doc, path[0])
+	if err != nil {
+		return nil, err
+	}
+	value, err = change(value, path[1:], fn, root)
+	if err != nil {
+		return nil, err
+	}
+	switch doc := doc.(type) {
+	case map[string]interface{}:
+		doc[path[0]] = value
+	case []interface{}:
+		i, _ := index(path[0], len(doc))
+		doc[i] = value
+	}
+	return doc, nil
+}
+
+func child(doc interface{}, token string) (interface{}, error) {
+	switch doc := doc.(type) {
+	case map[string]interface{}:
+		value, ok := doc[token]
+		if !ok {
+			return nil, fmt.Errorf("member %q is missing", token)
+		}
+		return value, nil
+	case []interface{}:
+		i, err := index(token, len(doc))
+		if err != nil {
+			return nil, err
+		}
+		return doc[i], nil
+	}
+	return nil, fmt.Errorf("%q is not in an object or an array", token)
+}
+
+// index reads an array index lower than size, without leading zeros.
+func index(token string, size int) (int, error) {
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || strconv.Itoa(i) != token {
+		return 0, fmt.Errorf("%q is not an array index", token)
+	}
+	if i >= size {
+		return 0, fmt.Errorf("index %d is out of bounds", i)
+	}
+	return i, nil
+}
+
+// clone returns a deep copy of a decoded JSON value, so a value added twice
+// isn't shared.
+func clone(value interface{}) interface{} {
+	switch value := value.(type) {
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(value))
+		for name, member := range value {
+			result[name] = clone(member)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(value))
+		for i, element := range value {
+			result[i] = clone(element)
+		}
+		return result
+	}
+	return value
+}
+
+// equal compares decoded JSON values, numbers by their value.
+func equal(a interface{}, b interface{}) bool {
+	switch a := a.(type) {
+	case json.Number:
+		b, ok := b.(json.Number)
+		if !ok {
+			return false
+		}
+		x, errA := a.Float64()
+		y, errB := b.Float64()
+		return errA == nil && errB == nil && x == y
+	case map[string]interface{}:
+		b, ok := b.(map[string]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for name, member := range a {
+			other, ok := b[name]
+			if !ok || !equal(member, other) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		b, ok := b.([]interface{})
+		if !ok || len(a) != len(b) {
+			return false
+		}
+		for i := range a {
+			if !equal(a[i], b[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return a == b
+}
@@ -0,0 +1,46 @@
+package patch
+
+import "encoding/json"
+
+// MergePatch is a RFC 7396 merge patch: its members replace the ones of the
+// document, objects are merged recursively and null removes a member.
+type MergePatch struct {
+	patch interface{}
+}
+
+func ParseMergePatch(body []byte) (*MergePatch, error) {
+	var patch interface{}
+	err := decode(body, &patch)
+	if err != nil {
+		return nil, invalid(err.Error())
+	}
+	return &MergePatch{patch: patch}, nil
+}
+
+func (p *MergePatch) Apply(doc []byte) ([]byte, error) {
+	var target interface{}
+	err := decode(doc, &target)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(merge(target, p.patch))
+}
+
+func merge(target interface{}, patch interface{}) interface{} {
+	members, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	object, ok := target.(map[string]interface{})
+	if !ok {
+		object = map[string]interface{}{}
+	}
+	for name, value := range members {
+		if value == nil {
+			delete(object, name)
+			continue
+		}
+		object[name] = merge(object[name], value)
+	}
+	return object
+}
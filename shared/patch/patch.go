@@ -0,0 +1,61 @@
+// Package patch applies RFC 7396 JSON merge patches and RFC 6902 JSON patches
+// to JSON documents.
+package patch
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime"
+	"net/http"
+	"news/shared/failure"
+)
+
+const (
+	MergePatchType = "application/merge-patch+json"
+	JSONPatchType  = "application/json-patch+json"
+)
+
+// Patch changes a JSON document.
+type Patch interface {
+	Apply(doc []byte) ([]byte, error)
+}
+
+// Parse reads body as the patch of contentType. application/json is read as a
+// merge patch, the document a plain JSON body describes.
+func Parse(contentType string, body []byte) (Patch, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		mediaType = ""
+	}
+	switch mediaType {
+	case MergePatchType, "application/json":
+		return ParseMergePatch(body)
+	case JSONPatchType:
+		return ParseJSONPatch(body)
+	}
+	return nil, failure.New(http.StatusUnsupportedMediaType, "patch.unsupported_media_type",
+		"content type not supported, use "+MergePatchType+" or "+JSONPatchType)
+}
+
+func invalid(reason string) error {
+	return failure.New(http.StatusBadRequest, "patch.not_valid", "patch not valid: "+reason)
+}
+
+func unapplicable(reason string) error {
+	return failure.New(http.StatusConflict, "patch.not_applicable", "patch can't be applied: "+reason)
+}
+
+// decode reads a JSON value keeping numbers as they are written.
+func decode(data []byte, v interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	err := decoder.Decode(v)
+	if err != nil {
+		return err
+	}
+	if decoder.More() {
+		return errors.New("more than one JSON value")
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package patch_test
+
+import (
+	"encoding/json"
+	"github.com/magiconair/properties/assert"
+	"net/http"
+	"news/shared/failure"
+	"news/shared/patch"
+	"testing"
+)
+
+// canonical re-encodes a JSON document with sorted members.
+func canonical(t *testing.T, doc string) string {
+	var value interface{}
+	err := json.Unmarshal([]byte(doc), &value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(result)
+}
+
+func TestMergePatch(t *testing.T) {
+	// the examples of RFC 7396 appendix A
+	sliceTest := []struct {
+		doc      string
+		patch    string
+		expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+	for _, test := range sliceTest {
+		t.Run(test.patch, func(t *testing.T) {
+			p, err := patch.ParseMergePatch([]byte(test.patch))
+			assert.Equal(t, err, nil)
+			actual, err := p.Apply([]byte(test.doc))
+			assert.Equal(t, err, nil)
+			assert.Equal(t, string(actual), canonical(t, test.expected))
+		})
+	}
+}
+
+func TestJSONPatch(t *testing.T) {
+	sliceTest := []struct {
+		testTitle string
+		doc       string
+		patch     string
+		expected  string
+		code      int
+	}{
+		{
+			testTitle: "add an object member",
+			doc:       `{"foo":"bar"}`,
+			patch:     `[{"op":"add","path":"/baz","value":"qux"}]`,
+			expected:  `{"baz":"qux","foo":"bar"}`,
+		},
+		{
+			testTitle: "add an array element",
+			doc:       `{"foo":["bar","baz"]}`,
+			patch:     `[{"op":"add","path":"/foo/1","value":"qux"}]`,
+			expected:  `{"foo":["bar","qux","baz"]}`,
+		},
+		{
+			testTitle: "add at the end of an array",
+			doc:       `{"foo":["bar"]}`,
+			patch:     `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`,
+			expected:  `{"foo":["bar",["abc","def"]]}`,
+		},
+		{
+			testTitle: "remove an object member",
+			doc:       `{"baz":"qux","foo":"bar"}`,
+			patch:     `[{"op":"remove","path":"/baz"}]`,
+			expected:  `{"foo":"bar"}`,
+		},
+		{
+			testTitle: "remove an array element",
+			doc:       `{"foo":["bar","qux","baz"]}`,
+			patch:     `[{"op":"remove","path":"/foo/1"}]`,
+			expected:  `{"foo":["bar","baz"]}`,
+		},
+		{
+			testTitle: "replace a value",
+			doc:       `{"baz":"qux","foo":"bar"}`,
+			patch:     `[{"op":"replace","path":"/baz","value":"boo"}]`,
+			expected:  `{"baz":"boo","foo":"bar"}`,
+		},
+		{
+			testTitle: "move a value",
+			doc:       `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`,
+			patch:     `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`,
+			expected:  `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`,
+		},
+		{
+			testTitle: "move an array element",
+			doc:       `{"foo":["all","grass","cows","eat"]}`,
+			patch:     `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`,
+			expected:  `{"foo":["all","cows","eat","grass"]}`,
+		},
+		{
+			testTitle: "copy a value",
+			doc:       `{"foo":{"bar":1}}`,
+			patch:     `[{"op":"copy","from":"/foo","path":"/baz"},{"op":"replace","path":"/baz/bar","value":2}]`,
+			expected:  `{"baz":{"bar":2},"foo":{"bar":1}}`,
+		},
+		{
+			testTitle: "test then change",
+			doc:       `{"baz":"qux","foo":["a",2,"c"]}`,
+			patch: `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0},
+				{"op":"add","path":"/a~1b","value":null}]`,
+			expected: `{"a/b":null,"baz":"qux","foo":["a",2,"c"]}`,
+		},
+		{
+			testTitle: "failed test leaves the document untouched",
+			doc:       `{"baz":"qux"}`,
+			patch:     `[{"op":"replace","path":"/baz","value":"boo"},{"op":"test","path":"/baz","value":"qux"}]`,
+			code:      http.StatusConflict,
+		},
+		{
+			testTitle: "remove a missing member",
+			doc:       `{"foo":"bar"}`,
+			patch:     `[{"op":"remove","path":"/baz"}]`,
+			code:      http.StatusConflict,
+		},
+		{
+			testTitle: "add out of the bounds of an array",
+			doc:       `{"foo":["bar"]}`,
+			patch:     `[{"op":"add","path":"/foo/2","value":"baz"}]`,
+			code:      http.StatusConflict,
+		},
+		{
+			testTitle: "move into itself",
+			doc:       `{"foo":{"bar":1}}`,
+			patch:     `[{"op":"move","from":"/foo","path":"/foo/bar"}]`,
+			code:      http.StatusConflict,
+		},
+		{
+			testTitle: "unknown op",
+			doc:       `{}`,
+			patch:     `[{"op":"merge","path":"/foo","value":1}]`,
+			code:      http.StatusBadRequest,
+		},
+		{
+			testTitle: "value missing",
+			doc:       `{}`,
+			patch:     `[{"op":"add","path":"/foo"}]`,
+			code:      http.StatusBadRequest,
+		},
+		{
+			testTitle: "path not a pointer",
+			doc:       `{}`,
+			patch:     `[{"op":"remove","path":"foo"}]`,
+			code:      http.StatusBadRequest,
+		},
+		{
+			testTitle: "not an array",
+			doc:       `{}`,
+			patch:     `{"op":"remove","path":"/foo"}`,
+			code:      http.StatusBadRequest,
+		},
+	}
+	for _, test := range sliceTest {
+		t.Run(test.testTitle, func(t *testing.T) {
+			p, err := patch.ParseJSONPatch([]byte(test.patch))
+			if err == nil {
+				var actual []byte
+				actual, err = p.Apply([]byte(test.doc))
+				if test.code == 0 {
+					assert.Equal(t, err, nil)
+					assert.Equal(t, string(actual), canonical(t, test.expected))
+					return
+				}
+			}
+			assert.Equal(t, failure.GetStatus(err), test.code)
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	_, err := patch.Parse("application/merge-patch+json; charset=utf-8", []byte(`{"a":1}`))
+	assert.Equal(t, err, nil)
+	_, err = patch.Parse("application/json", []byte(`{"a":1}`))
+	assert.Equal(t, err, nil)
+	_, err = patch.Parse("application/json-patch+json", []byte(`[]`))
+	assert.Equal(t, err, nil)
+	_, err = patch.Parse("text/plain", []byte(`{"a":1}`))
+	assert.Equal(t, failure.GetStatus(err), http.StatusUnsupportedMediaType)
+	_, err = patch.Parse("application/merge-patch+json", []byte(`{"a":`))
+	assert.Equal(t, failure.GetStatus(err), http.StatusBadRequest)
+}
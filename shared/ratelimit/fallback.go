@@ -0,0 +1,26 @@
+package ratelimit
+
+import (
+	"context"
+	"news/shared/logger"
+)
+
+// fallbackLimiter uses fallback while primary fails, a redis outage then limits
+// per instance instead of failing every request.
+type fallbackLimiter struct {
+	primary  Limiter
+	fallback Limiter
+}
+
+func NewFallbackLimiter(primary Limiter, fallback Limiter) *fallbackLimiter {
+	return &fallbackLimiter{primary: primary, fallback: fallback}
+}
+
+func (l *fallbackLimiter) Allow(ctx context.Context, key string, budget Budget) (Result, error) {
+	res, err := l.primary.Allow(ctx, key, budget)
+	if err == nil {
+		return res, nil
+	}
+	logger.ErrorWithStack(ctx, err)
+	return l.fallback.Allow(ctx, key, budget)
+}
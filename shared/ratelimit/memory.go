@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"news/shared/Date"
+	"sync"
+	"time"
+)
+
+type counter struct {
+	index    int64
+	previous int
+	current  int
+	window   time.Duration
+}
+
+// memoryLimiter counts in the process, every instance of the app has its own budgets.
+type memoryLimiter struct {
+	mu        sync.Mutex
+	counters  map[string]*counter
+	lastSweep time.Time
+}
+
+func NewMemoryLimiter() *memoryLimiter {
+	return &memoryLimiter{counters: map[string]*counter{}}
+}
+
+func (l *memoryLimiter) Allow(ctx context.Context, key string, budget Budget) (Result, error) {
+	if budget.Limit <= 0 || budget.Window <= 0 {
+		return Result{Allowed: true}, nil
+	}
+	now := Date.Now()
+	index, elapsed := window(now, budget)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+	c, ok := l.counters[key]
+	if !ok {
+		c = &counter{index: index, window: budget.Window}
+		l.counters[key] = c
+	}
+	c.window = budget.Window
+	if c.index != index {
+		if c.index == index-1 {
+			c.previous = c.current
+		} else {
+			c.previous = 0
+		}
+		c.index, c.current = index, 0
+	}
+	if float64(c.previous)*weight(elapsed, budget)+float64(c.current)+1 > float64(budget.Limit) {
+		return result(false, c.previous, c.current, elapsed, budget), nil
+	}
+	c.current++
+	return result(true, c.previous, c.current, elapsed, budget), nil
+}
+
+// sweep drops the counters that have not been used for two windows, at most once a minute.
+func (l *memoryLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+	for key, c := range l.counters {
+		index, _ := window(now, Budget{Window: c.window})
+		if c.index < index-1 {
+			delete(l.counters, key)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// Package ratelimit counts requests per key with a sliding window: the count of
+// the previous fixed window is weighted by how much of it still overlaps the
+// sliding one and added to the count of the current window.
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"time"
+)
+
+// Budget allows Limit requests per Window, a zero Limit or Window allows everything.
+type Budget struct {
+	Limit  int
+	Window time.Duration
+}
+
+// Result is the state of a key after a request, RetryAfter is only set when the
+// request is not allowed.
+type Result struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	Reset      time.Duration
+	RetryAfter time.Duration
+}
+
+type Limiter interface {
+	Allow(ctx context.Context, key string, budget Budget) (Result, error)
+}
+
+// window locates now in the fixed windows of the budget.
+func window(now time.Time, budget Budget) (index int64, elapsed time.Duration) {
+	size := budget.Window.Nanoseconds()
+	index = now.UnixNano() / size
+	return index, time.Duration(now.UnixNano() - index*size)
+}
+
+// weight is the share of the previous window still inside the sliding window.
+func weight(elapsed time.Duration, budget Budget) float64 {
+	return 1 - float64(elapsed)/float64(budget.Window)
+}
+
+// result builds the Result from the counts of the previous and current window,
+// current already includes the request when it was allowed.
+func result(allowed bool, previous int, current int, elapsed time.Duration, budget Budget) Result {
+	estimated := float64(previous)*weight(elapsed, budget) + float64(current)
+	res := Result{
+		Allowed:   allowed,
+		Limit:     budget.Limit,
+		Remaining: int(math.Max(0, float64(budget.Limit)-math.Ceil(estimated))),
+		Reset:     budget.Window - elapsed,
+	}
+	if allowed {
+		return res
+	}
+	// the weighted count of the older window has to drop by the excess before one more request fits
+	if current < budget.Limit {
+		excess := estimated + 1 - float64(budget.Limit)
+		res.RetryAfter = time.Duration(excess / float64(previous) * float64(budget.Window))
+		return res
+	}
+	// the current window is full, it becomes the older window first
+	excess := float64(current + 1 - budget.Limit)
+	res.RetryAfter = budget.Window - elapsed + time.Duration(excess/float64(current)*float64(budget.Window))
+	return res
+}
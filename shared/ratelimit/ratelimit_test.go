@@ -0,0 +1,116 @@
+package ratelimit_test
+
+import (
+	"context"
+	"github.com/go-redis/redis"
+	"github.com/magiconair/properties/assert"
+	"news/shared/Date"
+	"news/shared/IDGEN"
+	"news/shared/ratelimit"
+	"os"
+	"testing"
+	"time"
+)
+
+// limiterContract moves the clock with Date.Now, newLimiter must return a limiter without any count.
+func limiterContract(t *testing.T, newLimiter func(t *testing.T) ratelimit.Limiter) {
+	ctx := context.Background()
+	budget := ratelimit.Budget{Limit: 4, Window: time.Minute}
+	// a minute boundary, the fixed windows start there
+	start := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	now := start
+	Date.Now = func() time.Time {
+		return now
+	}
+	defer func() { Date.Now = time.Now }()
+	allow := func(limiter ratelimit.Limiter, key string) ratelimit.Result {
+		result, err := limiter.Allow(ctx, key, budget)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	t.Run("budget per key", func(t *testing.T) {
+		limiter := newLimiter(t)
+		now = start.Add(15 * time.Second)
+		for i := 3; i >= 0; i-- {
+			result := allow(limiter, "client1")
+			assert.Equal(t, result.Allowed, true)
+			assert.Equal(t, result.Remaining, i)
+			assert.Equal(t, result.Reset, 45*time.Second)
+		}
+		result := allow(limiter, "client1")
+		assert.Equal(t, result.Allowed, false)
+		assert.Equal(t, result.Remaining, 0)
+		// the window is full, it has to become the previous one and a quarter of it has to slide out
+		assert.Equal(t, result.RetryAfter, 60*time.Second)
+		assert.Equal(t, allow(limiter, "client2").Allowed, true)
+	})
+
+	t.Run("sliding window", func(t *testing.T) {
+		limiter := newLimiter(t)
+		now = start
+		for i := 0; i < 4; i++ {
+			allow(limiter, "client1")
+		}
+		// half of the previous window still counts, 2 of its 4 requests
+		now = start.Add(90 * time.Second)
+		assert.Equal(t, allow(limiter, "client1").Allowed, true)
+		assert.Equal(t, allow(limiter, "client1").Allowed, true)
+		result := allow(limiter, "client1")
+		assert.Equal(t, result.Allowed, false)
+		assert.Equal(t, result.RetryAfter, 15*time.Second)
+
+		now = start.Add(105 * time.Second)
+		result = allow(limiter, "client1")
+		assert.Equal(t, result.Allowed, true)
+		assert.Equal(t, result.Remaining, 0)
+
+		// two windows later nothing counts
+		now = start.Add(180 * time.Second)
+		assert.Equal(t, allow(limiter, "client1").Remaining, 3)
+	})
+
+	t.Run("zero limit allows everything", func(t *testing.T) {
+		limiter := newLimiter(t)
+		result, err := limiter.Allow(ctx, "client1", ratelimit.Budget{Window: time.Minute})
+		assert.Equal(t, err, nil)
+		assert.Equal(t, result.Allowed, true)
+	})
+}
+
+func TestMemoryLimiter(t *testing.T) {
+	limiterContract(t, func(t *testing.T) ratelimit.Limiter {
+		return ratelimit.NewMemoryLimiter()
+	})
+}
+
+// TestRedisLimiter runs when TEST_REDIS_ADDR is set, keys are prefixed with a new id on every run.
+func TestRedisLimiter(t *testing.T) {
+	addr := os.Getenv("TEST_REDIS_ADDR")
+	if addr == "" {
+		t.Skip("TEST_REDIS_ADDR is not set")
+	}
+	client := redis.NewClient(&redis.Options{Addr: addr})
+	defer client.Close()
+	limiterContract(t, func(t *testing.T) ratelimit.Limiter {
+		return ratelimit.NewRedisLimiterWithPrefix(client, "test:"+IDGEN.NewUUID()+":")
+	})
+}
+
+type brokenLimiter struct{}
+
+func (brokenLimiter) Allow(ctx context.Context, key string, budget ratelimit.Budget) (ratelimit.Result, error) {
+	return ratelimit.Result{}, context.DeadlineExceeded
+}
+
+func TestFallbackLimiter(t *testing.T) {
+	limiter := ratelimit.NewFallbackLimiter(brokenLimiter{}, ratelimit.NewMemoryLimiter())
+	result, err := limiter.Allow(context.Background(), "client1", ratelimit.Budget{Limit: 1, Window: time.Minute})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, result.Allowed, true)
+	result, err = limiter.Allow(context.Background(), "client1", ratelimit.Budget{Limit: 1, Window: time.Minute})
+	assert.Equal(t, err, nil)
+	assert.Equal(t, result.Allowed, false)
+}
@@ -0,0 +1,55 @@
+package ratelimit
+
+import (
+	"context"
+	"github.com/go-redis/redis"
+	"news/shared/Date"
+	"strconv"
+)
+
+// allowScript reads both windows and only counts the request when it fits, so
+// rejected requests don't use the budget. It returns allowed, previous and current.
+var allowScript = redis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[1]) or '0')
+local previous = tonumber(redis.call('GET', KEYS[2]) or '0')
+if previous * tonumber(ARGV[2]) + current + 1 > tonumber(ARGV[1]) then
+  return {0, previous, current}
+end
+current = redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], ARGV[3])
+return {1, previous, current}
+`)
+
+// redisLimiter shares the budgets between every instance using the same redis.
+type redisLimiter struct {
+	redis  *redis.Client
+	prefix string
+}
+
+func NewRedisLimiter(client *redis.Client) *redisLimiter {
+	return NewRedisLimiterWithPrefix(client, "ratelimit:")
+}
+
+// NewRedisLimiterWithPrefix keeps the counters under keys starting with prefix.
+func NewRedisLimiterWithPrefix(client *redis.Client, prefix string) *redisLimiter {
+	return &redisLimiter{redis: client, prefix: prefix}
+}
+
+func (l *redisLimiter) Allow(ctx context.Context, key string, budget Budget) (res Result, err error) {
+	if budget.Limit <= 0 || budget.Window <= 0 {
+		return Result{Allowed: true}, nil
+	}
+	index, elapsed := window(Date.Now(), budget)
+	keys := []string{
+		l.prefix + key + ":" + strconv.FormatInt(index, 10),
+		l.prefix + key + ":" + strconv.FormatInt(index-1, 10),
+	}
+	// the current window is read as the previous one during the next window
+	expire := (2 * budget.Window).Milliseconds()
+	values, err := allowScript.Run(l.redis.WithContext(ctx), keys, budget.Limit, weight(elapsed, budget), expire).Result()
+	if err != nil {
+		return
+	}
+	counts := values.([]interface{})
+	return result(counts[0].(int64) == 1, int(counts[1].(int64)), int(counts[2].(int64)), elapsed, budget), nil
+}
@@ -0,0 +1,70 @@
+// Package retention deletes the rows the app only keeps for a while, like the
+// published outbox events and the finished webhook deliveries, so their tables
+// don't grow forever.
+package retention
+
+import (
+	"context"
+	"news/shared/Date"
+	"news/shared/logger"
+	"time"
+)
+
+// Purge deletes the rows of a table older than before and returns how many it deleted.
+type Purge func(ctx context.Context, before time.Time) (int64, error)
+
+// Job purges the rows of one table older than Retention.
+type Job struct {
+	Name      string
+	Retention time.Duration
+	Purge     Purge
+}
+
+// Purger runs its jobs every interval, a job failing doesn't stop the others.
+type Purger struct {
+	interval time.Duration
+	jobs     []Job
+}
+
+func NewPurger(interval time.Duration, jobs ...Job) *Purger {
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	return &Purger{interval: interval, jobs: jobs}
+}
+
+// Run purges every interval until ctx is done.
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+	for {
+		err := p.Flush(ctx)
+		// a pass cut short by the shutdown is not an error
+		if err != nil && ctx.Err() == nil {
+			logger.ErrorWithStack(ctx, err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Flush runs every job once and returns the first error.
+func (p *Purger) Flush(ctx context.Context) (err error) {
+	now := Date.Now()
+	for _, job := range p.jobs {
+		deleted, errs := job.Purge(ctx, now.Add(-job.Retention))
+		if errs != nil {
+			if err == nil {
+				err = errs
+			}
+			continue
+		}
+		if deleted > 0 {
+			logger.Ctx(ctx).Info().Str("job", job.Name).Int64("deleted", deleted).Msg("rows purged")
+		}
+	}
+	return
+}
@@ -0,0 +1,57 @@
+package retention_test
+
+import (
+	"context"
+	"errors"
+	"github.com/magiconair/properties/assert"
+	"news/shared/Date"
+	"news/shared/retention"
+	"testing"
+	"time"
+)
+
+func TestPurger(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2022, 5, 1, 10, 0, 0, 0, time.UTC)
+	Date.Now = func() time.Time {
+		return now
+	}
+	defer func() { Date.Now = time.Now }()
+
+	t.Run("purges every job before its retention", func(t *testing.T) {
+		befores := map[string]time.Time{}
+		purge := func(name string, err error) retention.Purge {
+			return func(ctx context.Context, before time.Time) (int64, error) {
+				befores[name] = before
+				return 1, err
+			}
+		}
+		purger := retention.NewPurger(time.Hour,
+			retention.Job{Name: "outbox", Retention: time.Hour, Purge: purge("outbox", errors.New("down"))},
+			retention.Job{Name: "deliveries", Retention: 24 * time.Hour, Purge: purge("deliveries", nil)})
+
+		err := purger.Flush(ctx)
+		assert.Equal(t, err.Error(), "down")
+		// a failing job doesn't stop the next ones
+		assert.Equal(t, befores["outbox"], now.Add(-time.Hour))
+		assert.Equal(t, befores["deliveries"], now.Add(-24*time.Hour))
+	})
+
+	t.Run("run stops with its context", func(t *testing.T) {
+		passes := 0
+		purger := retention.NewPurger(time.Hour, retention.Job{Name: "outbox", Retention: time.Hour,
+			Purge: func(ctx context.Context, before time.Time) (int64, error) {
+				passes++
+				return 0, nil
+			}})
+		ctx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			purger.Run(ctx)
+			close(done)
+		}()
+		cancel()
+		<-done
+		assert.Equal(t, passes, 1)
+	})
+}